@@ -6,6 +6,7 @@ import (
 	"syscall"
 	"tracker-bot/internal/application"
 	"tracker-bot/internal/config"
+	"tracker-bot/internal/utils/logger"
 
 	"github.com/rs/zerolog/log"
 )
@@ -22,6 +23,9 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to parse config")
 	}
+	if err := logger.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up logger")
+	}
 
 	app := application.NewApplication(cfg)
 	if err := app.Build(ctx); err != nil {
@@ -31,5 +35,6 @@ func main() {
 	if err := app.Run(); err != nil {
 		log.Fatal().Err(err).Msg("All systems closed with errors!")
 	}
+	log.Info().Msg("All systems closed")
 
 }
@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+	"tracker-bot/internal/metrics"
+	"tracker-bot/internal/utils/httpserver"
+)
+
+// readyTimeout bounds all readiness checks of one request.
+const readyTimeout = 5 * time.Second
+
+// Check is one readiness dependency (database, Telegram API).
+type Check struct {
+	Name string
+	Func func(ctx context.Context) error
+}
+
+// Cached wraps check so that its result is reused for ttl,
+// e.g. to avoid calling Telegram on every probe.
+func Cached(check Check, ttl time.Duration) Check {
+	var (
+		mu      sync.Mutex
+		checked time.Time
+		last    error
+	)
+	return Check{
+		Name: check.Name,
+		Func: func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if !checked.IsZero() && time.Since(checked) < ttl {
+				return last
+			}
+			last = check.Func(ctx)
+			checked = time.Now()
+			return last
+		},
+	}
+}
+
+// Server exposes /healthz, /readyz and /metrics.
+type Server struct {
+	server *httpserver.Server
+	checks []Check
+}
+
+// NewServer creates admin HTTP server listening on addr.
+func NewServer(addr string, checks ...Check) *Server {
+	s := &Server{checks: checks}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleReady)
+	mux.Handle("/metrics", metrics.Handler())
+	s.server = httpserver.New("admin", addr, mux)
+	return s
+}
+
+// Start serves health, readiness and metrics endpoints in background.
+func (s *Server) Start() error {
+	return s.server.Start()
+}
+
+// Shutdown stops admin endpoints, letting in-flight probes finish.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// handleHealth reports that process is alive.
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// handleReady runs all checks and reports their status as JSON; 503 if any failed.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	status := http.StatusOK
+	result := make(map[string]string, len(s.checks))
+	for _, c := range s.checks {
+		if err := c.Func(ctx); err != nil {
+			status = http.StatusServiceUnavailable
+			result[c.Name] = err.Error()
+			continue
+		}
+		result[c.Name] = "ok"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(result)
+}
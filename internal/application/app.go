@@ -2,25 +2,41 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+	"tracker-bot/internal/admin"
 	"tracker-bot/internal/config"
 	"tracker-bot/internal/dispatcher"
 	"tracker-bot/internal/handlers"
+	"tracker-bot/internal/metrics"
 	"tracker-bot/internal/repo"
 	"tracker-bot/internal/scheduler"
 	"tracker-bot/internal/service"
+	"tracker-bot/internal/share"
+	"tracker-bot/internal/tracing"
 	"tracker-bot/internal/utils/pgclient"
 	"tracker-bot/internal/utils/tgclient"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
 )
 
 type Application struct {
+	ctx            context.Context
 	cfg            *config.Config
 	db             *pgclient.Client
 	bot            *tgbotapi.BotAPI
+	sender         *tgclient.Sender
+	webhook        *tgclient.WebhookServer
+	admin          *admin.Server
+	stopTracing    func(ctx context.Context) error
 	dispatcher     *dispatcher.Dispatcher
 	timerScheduler *scheduler.TimerScheduler
+	jobRunner      *scheduler.JobRunner
+	pomodoros      *scheduler.JobRunner
+	broadcasts     *scheduler.BroadcastSender
+	share          *share.Server
 }
 
 func NewApplication(cfg *config.Config) *Application {
@@ -32,6 +48,18 @@ func (app *Application) Build(ctx context.Context) error {
 	if app.cfg == nil {
 		return fmt.Errorf("build application: nil config")
 	}
+	app.ctx = ctx
+
+	stopTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    app.cfg.Tracing.Exporter,
+		Endpoint:    app.cfg.Tracing.Endpoint,
+		Insecure:    app.cfg.Tracing.Insecure,
+		SampleRatio: app.cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("init tracing: %w", err)
+	}
+	app.stopTracing = stopTracing
 
 	db, err := pgclient.New(ctx, app.cfg.PostgresDSN())
 	if err != nil {
@@ -41,10 +69,34 @@ func (app *Application) Build(ctx context.Context) error {
 
 	bot, err := tgclient.New(app.cfg.Telegram.TelegramToken)
 	if err != nil {
+		app.db.Close()
 		return fmt.Errorf("init telegram bot: %w", err)
 	}
 	bot.Debug = app.cfg.Telegram.TelegramBotDebug
 	app.bot = bot
+	app.sender = tgclient.NewSender(bot, app.cfg.Telegram.GlobalRate, app.cfg.Telegram.ChatRate)
+
+	switch app.cfg.Telegram.Mode {
+	case config.ModePolling:
+	case config.ModeWebhook:
+		// Without secret anyone reaching the listener could post fake updates, admin commands included.
+		if app.cfg.Webhook.SecretToken == "" {
+			app.db.Close()
+			return fmt.Errorf("build application: WEBHOOK_SECRET_TOKEN is required in webhook mode")
+		}
+		app.webhook = tgclient.NewWebhookServer(tgclient.WebhookConfig{
+			URL:            app.cfg.Webhook.URL,
+			Listen:         app.cfg.Webhook.Listen,
+			Path:           app.cfg.Webhook.Path,
+			SecretToken:    app.cfg.Webhook.SecretToken,
+			TLSCert:        app.cfg.Webhook.TLSCert,
+			TLSKey:         app.cfg.Webhook.TLSKey,
+			AllowedUpdates: dispatcher.AllowedUpdates,
+		})
+	default:
+		app.db.Close()
+		return fmt.Errorf("build application: unknown telegram mode %q", app.cfg.Telegram.Mode)
+	}
 
 	//repositories
 	entryRepo := repo.NewEntryRepository(app.db.Pool())
@@ -54,29 +106,221 @@ func (app *Application) Build(ctx context.Context) error {
 	subscriptionRepo := repo.NewSubscriptionRepository(app.db.Pool())
 	timerRepo := repo.NewTimerRepository(app.db.Pool())
 	sessionRepo := repo.NewSessionRepository(app.db.Pool())
+	goalRepo := repo.NewGoalRepository(app.db.Pool())
+	digestRepo := repo.NewDigestRepository(app.db.Pool())
+	tagRepo := repo.NewTagRepository(app.db.Pool())
+	adminRepo := repo.NewAdminRepository(app.db.Pool())
+	broadcastRepo := repo.NewBroadcastRepository(app.db.Pool())
+	shareRepo := repo.NewShareRepository(app.db.Pool())
+	teamRepo := repo.NewTeamRepository(app.db.Pool())
+	partnerRepo := repo.NewPartnerRepository(app.db.Pool())
+	onboardingRepo := repo.NewOnboardingRepository(app.db.Pool())
+	pomodoroRepo := repo.NewPomodoroRepository(app.db.Pool())
 
 	//services
 	entrysvc := service.NewEntryService(entryRepo)
 	provilesvc := service.NewProfileService(profileRepo)
-	tracksvc := service.NewTrackerService(trackRepo)
-	timersvc := service.NewTimerService(timerRepo, sessionRepo)
+	tracksvc := service.NewTrackerService(trackRepo, goalRepo, tagRepo)
+	timerQueue := scheduler.NewTimerQueue()
+	timersvc := service.NewTimerService(timerRepo, sessionRepo, timerQueue)
 	learningsvc := service.NewLearningService(learningRepo)
 	subscriptionsvc := service.NewSubscriptionService(subscriptionRepo)
+	goalsvc := service.NewGoalService(goalRepo)
+	digestsvc := service.NewDigestService(digestRepo)
+	tagsvc := service.NewTagService(tagRepo)
+	adminsvc := service.NewAdminService(adminRepo)
+	broadcastWaker := scheduler.NewBroadcastWaker()
+	broadcastsvc := service.NewBroadcastService(broadcastRepo, broadcastWaker)
+	sharesvc := service.NewShareService(shareRepo, app.cfg.Share.BaseURL, app.cfg.Share.Secret, app.cfg.Share.TTL)
+	teamsvc := service.NewTeamService(teamRepo)
+	partnersvc := service.NewPartnerService(partnerRepo, goalRepo, app.bot.Self.UserName)
+	onboardingsvc := service.NewOnboardingService(onboardingRepo)
+	pomodorosvc := service.NewPomodoroService(pomodoroRepo)
+
+	metrics.RegisterSender(app.sender)
+	metrics.RegisterPool(app.db.Pool())
+	if app.cfg.Admin.Listen != "" {
+		app.admin = admin.NewServer(app.cfg.Admin.Listen,
+			admin.Check{Name: "postgres", Func: app.db.Pool().Ping},
+			admin.Cached(admin.Check{Name: "telegram", Func: app.checkTelegram}, 30*time.Second),
+		)
+	}
+
+	if app.cfg.Share.Listen != "" {
+		app.share = share.NewServer(app.cfg.Share.Listen, sharesvc, tracksvc)
+	}
+
+	// Components are stopped explicitly by lifecycle, so work they already started
+	// is not cut by the shutdown signal.
+	workCtx := context.WithoutCancel(ctx)
 
 	//handlers and dispatcher
-	module := handlers.New(app.bot, entrysvc, provilesvc, tracksvc, timersvc, learningsvc, subscriptionsvc, app.cfg.TestTimerMinutes)
-	app.dispatcher = dispatcher.New(app.bot, ctx, entrysvc, module, module, module, module, module)
-	app.timerScheduler = scheduler.NewTimerScheduler(ctx, timersvc, module)
+	module := handlers.New(app.sender, entrysvc, provilesvc, tracksvc, timersvc, learningsvc, subscriptionsvc, goalsvc, digestsvc, tagsvc, adminsvc, broadcastsvc, sharesvc, teamsvc, partnersvc, onboardingsvc, pomodorosvc, app.cfg.Admin.TgUserIDs, app.cfg.TestTimerMinutes)
+	app.dispatcher = dispatcher.New(app.sender, workCtx, entrysvc, module, module, module, module, module)
+	app.timerScheduler = scheduler.NewTimerScheduler(workCtx, timersvc, module, timerQueue)
+	app.jobRunner = scheduler.NewJobRunner(workCtx, time.Minute,
+		scheduler.NewDigestJob(digestsvc, module),
+		scheduler.NewTeamDigestJob(teamsvc, module),
+		scheduler.NewPromptDeliveryCleanupJob(timersvc),
+	)
+	// Pomodoro phases are minutes long, so they get their own faster runner.
+	app.pomodoros = scheduler.NewJobRunner(workCtx, scheduler.PomodoroTick, scheduler.NewPomodoroJob(pomodorosvc, module))
+	app.broadcasts = scheduler.NewBroadcastSender(workCtx, broadcastsvc, module, broadcastWaker)
 
 	return nil
 }
 
-// Run starts background jobs and blocks on dispatcher loop.
+// Run starts components, blocks until shutdown signal and stops them in reverse order.
+// Start and shutdown errors are returned together.
 func (app *Application) Run() error {
-	if app.dispatcher == nil || app.timerScheduler == nil {
+	if app.dispatcher == nil || app.timerScheduler == nil || app.jobRunner == nil || app.pomodoros == nil || app.broadcasts == nil {
 		return fmt.Errorf("run application: app is not built")
 	}
-	app.timerScheduler.Run()
-	app.dispatcher.Run()
-	return nil
+
+	lc := newLifecycle(app.cfg.ShutdownTimeout)
+	served := make(chan struct{})
+
+	// Spans are flushed last, after components that may still produce them.
+	lc.add(component{
+		name: "tracing",
+		stop: app.stopTracing,
+	})
+	// Pool is closed after everything that may still write.
+	lc.add(component{
+		name: "postgres",
+		stop: func(ctx context.Context) error {
+			closed := make(chan struct{})
+			go func() {
+				app.db.Close()
+				close(closed)
+			}()
+			return waitDone(ctx, closed)
+		},
+	})
+	if app.admin != nil {
+		lc.add(component{
+			name:  "admin http",
+			start: app.admin.Start,
+			stop:  app.admin.Shutdown,
+		})
+	}
+	if app.share != nil {
+		lc.add(component{
+			name:  "share http",
+			start: app.share.Start,
+			stop:  app.share.Shutdown,
+		})
+	}
+	lc.add(component{
+		name:  "timer scheduler",
+		start: func() error { app.timerScheduler.Run(); return nil },
+		stop:  app.timerScheduler.Stop,
+	})
+	lc.add(component{
+		name:  "job runner",
+		start: func() error { app.jobRunner.Run(); return nil },
+		stop:  app.jobRunner.Stop,
+	})
+	lc.add(component{
+		name:  "pomodoro runner",
+		start: func() error { app.pomodoros.Run(); return nil },
+		stop:  app.pomodoros.Stop,
+	})
+	lc.add(component{
+		name:  "broadcast sender",
+		start: func() error { app.broadcasts.Run(); return nil },
+		stop:  app.broadcasts.Stop,
+	})
+
+	lc.add(component{
+		name: "bot commands",
+		// Command menu is cosmetic, so a Telegram error does not block startup.
+		start: func() error {
+			if err := app.dispatcher.RegisterCommands(); err != nil {
+				log.Warn().Err(err).Msg("register bot commands failed")
+			}
+			return nil
+		},
+	})
+
+	if app.webhook != nil {
+		lc.add(component{
+			name: "webhook",
+			start: func() error {
+				if err := app.webhook.Register(app.bot); err != nil {
+					return err
+				}
+				if err := app.webhook.Start(); err != nil {
+					return err
+				}
+				go func() {
+					app.dispatcher.Serve(app.webhook.Updates())
+					close(served)
+				}()
+				return nil
+			},
+			// Stop accepting requests, then let dispatcher drain accepted updates.
+			stop: func(ctx context.Context) error {
+				if err := app.webhook.Shutdown(ctx); err != nil {
+					return err
+				}
+				return waitDone(ctx, served)
+			},
+			timeout: app.cfg.Webhook.DrainTimeout,
+		})
+	} else {
+		lc.add(component{
+			name: "long polling",
+			start: func() error {
+				go func() {
+					app.dispatcher.Run()
+					close(served)
+				}()
+				return nil
+			},
+			stop: func(ctx context.Context) error {
+				app.dispatcher.Stop()
+				return waitDone(ctx, served)
+			},
+		})
+	}
+
+	var runErr error
+	if err := lc.start(); err != nil {
+		runErr = fmt.Errorf("run application: %w", err)
+	} else {
+		select {
+		case <-app.ctx.Done():
+			log.Info().Msg("shutdown signal received")
+		case <-served:
+			runErr = fmt.Errorf("run application: update loop stopped unexpectedly")
+		}
+	}
+
+	return errors.Join(runErr, lc.stop())
+}
+
+// checkTelegram calls getMe; the client has no context support, so ctx only bounds the wait.
+func (app *Application) checkTelegram(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := app.bot.GetMe()
+		errCh <- err
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitDone waits for done or returns context error.
+func waitDone(ctx context.Context, done <-chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
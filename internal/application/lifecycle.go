@@ -0,0 +1,81 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// component is one unit of application started and stopped by lifecycle.
+// start must not block; stop gets context bounded by component timeout.
+type component struct {
+	name    string
+	start   func() error
+	stop    func(ctx context.Context) error
+	timeout time.Duration
+}
+
+// lifecycle starts components in registration order and stops them in reverse.
+type lifecycle struct {
+	components []component
+	started    int
+	timeout    time.Duration
+}
+
+func newLifecycle(timeout time.Duration) *lifecycle {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &lifecycle{timeout: timeout}
+}
+
+func (l *lifecycle) add(c component) {
+	l.components = append(l.components, c)
+}
+
+// start runs components one by one; on failure the ones already started stay
+// registered, so stop shuts them down.
+func (l *lifecycle) start() error {
+	for _, c := range l.components[l.started:] {
+		if c.start != nil {
+			if err := c.start(); err != nil {
+				return fmt.Errorf("start %s: %w", c.name, err)
+			}
+		}
+		l.started++
+		log.Info().Str("component", c.name).Msg("lifecycle: started")
+	}
+	return nil
+}
+
+// stop shuts down started components in reverse order, each within its timeout,
+// and returns all errors joined.
+func (l *lifecycle) stop() error {
+	var errs []error
+	for i := l.started - 1; i >= 0; i-- {
+		c := l.components[i]
+		if c.stop == nil {
+			continue
+		}
+		timeout := c.timeout
+		if timeout <= 0 {
+			timeout = l.timeout
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		began := time.Now()
+		err := c.stop(ctx)
+		cancel()
+		if err != nil {
+			log.Error().Err(err).Str("component", c.name).Msg("lifecycle: stop failed")
+			errs = append(errs, fmt.Errorf("stop %s: %w", c.name, err))
+			continue
+		}
+		log.Info().Str("component", c.name).Dur("took", time.Since(began)).Msg("lifecycle: stopped")
+	}
+	l.started = 0
+	return errors.Join(errs...)
+}
@@ -0,0 +1,28 @@
+package admin
+
+// Inline callbacks; broadcast callbacks end with broadcast id.
+const (
+	AdminCBBroadcastLanguage = "admin:bc:lang:"
+	AdminCBBroadcastPlan     = "admin:bc:plan:"
+	AdminCBBroadcastActive   = "admin:bc:days:"
+	AdminCBBroadcastSendNow  = "admin:bc:now:"
+	AdminCBBroadcastSchedule = "admin:bc:at:"
+	AdminCBBroadcastCancel   = "admin:bc:cancel:"
+)
+
+// Inline menu buttons.
+const (
+	AdminButtonBroadcastLanguage = "🌐 Language"
+	AdminButtonBroadcastPlan     = "💳 Plan"
+	AdminButtonBroadcastActive   = "🕐 Active"
+	AdminButtonBroadcastSendNow  = "📣 Send now"
+	AdminButtonBroadcastSchedule = "🗓 Schedule"
+	AdminButtonBroadcastCancel   = "✖️ Cancel"
+)
+
+// Broadcast segment choices cycled by buttons; empty value means "all".
+var (
+	BroadcastLanguages  = []string{"", "ru", "en", "de", "uk", "ar"}
+	BroadcastPlans      = []string{"", "free", "premium"}
+	BroadcastActiveDays = []int{0, 7, 30, 90}
+)
@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"tracker-bot/internal/models"
+	"tracker-bot/pkg/buttonbuilder"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Inline button menus
+
+// AdminBroadcastDraftInlineMenu shows segment switches and send controls of draft.
+func AdminBroadcastDraftInlineMenu(b models.Broadcast) tgbotapi.InlineKeyboardMarkup {
+	id := strconv.FormatInt(b.ID, 10)
+	return buttonbuilder.IK(
+		buttonbuilder.IR(
+			buttonbuilder.IB(fmt.Sprintf("%s: %s", AdminButtonBroadcastLanguage, orAll(b.Segment.Language)), AdminCBBroadcastLanguage+id),
+			buttonbuilder.IB(fmt.Sprintf("%s: %s", AdminButtonBroadcastPlan, orAll(b.Segment.Plan)), AdminCBBroadcastPlan+id),
+		),
+		buttonbuilder.IR(
+			buttonbuilder.IB(fmt.Sprintf("%s: %s", AdminButtonBroadcastActive, ActiveDaysLabel(b.Segment.ActiveDays)), AdminCBBroadcastActive+id),
+		),
+		buttonbuilder.IR(
+			buttonbuilder.IB(AdminButtonBroadcastSendNow, AdminCBBroadcastSendNow+id),
+			buttonbuilder.IB(AdminButtonBroadcastSchedule, AdminCBBroadcastSchedule+id),
+		),
+		buttonbuilder.IR(
+			buttonbuilder.IB(AdminButtonBroadcastCancel, AdminCBBroadcastCancel+id),
+		),
+	)
+}
+
+// AdminBroadcastQueuedInlineMenu lets operator cancel scheduled or running broadcast.
+func AdminBroadcastQueuedInlineMenu(id int64) tgbotapi.InlineKeyboardMarkup {
+	return buttonbuilder.IK(
+		buttonbuilder.IR(
+			buttonbuilder.IB(AdminButtonBroadcastCancel, AdminCBBroadcastCancel+strconv.FormatInt(id, 10)),
+		),
+	)
+}
+
+// BroadcastContentMarkup builds URL buttons of announcement itself; nil when there are none.
+func BroadcastContentMarkup(buttons []models.BroadcastButton) *tgbotapi.InlineKeyboardMarkup {
+	if len(buttons) == 0 {
+		return nil
+	}
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(buttons))
+	for _, btn := range buttons {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonURL(btn.Text, btn.URL)))
+	}
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &markup
+}
+
+// ActiveDaysLabel formats activity filter.
+func ActiveDaysLabel(days int) string {
+	if days == 0 {
+		return "any"
+	}
+	return fmt.Sprintf("%dd", days)
+}
+
+func orAll(v string) string {
+	if v == "" {
+		return "all"
+	}
+	return v
+}
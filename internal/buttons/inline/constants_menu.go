@@ -0,0 +1,21 @@
+package inline
+
+// Inline result ids; log result id is "log:<activity_id>:<start_unix>:<end_unix>".
+const (
+	InlineResultLog   = "log:"
+	InlineResultToday = "today"
+)
+
+// Result titles.
+const (
+	InlineTitleLog          = "Log %s to %s"
+	InlineTitleToday        = "Share today's summary"
+	InlineTitleUsage        = "Type activity and time"
+	InlineDescriptionUsage  = "e.g. go 30m or english 14:00-15:30"
+	InlineDescriptionToday  = "Total: %s"
+	InlineDescriptionLogged = "%s–%s"
+)
+
+// InlineCacheSeconds keeps results fresh: they depend on time and today's totals.
+// Must be positive: zero is omitted from the request and Telegram caches for 300 s.
+const InlineCacheSeconds = 1
@@ -0,0 +1,40 @@
+package inline
+
+import (
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// LogResult offers logging duration to activity; message is what gets posted to chat.
+func LogResult(activityID int64, start, end time.Time, duration, activity, message string) tgbotapi.InlineQueryResultArticle {
+	id := fmt.Sprintf("%s%d:%d:%d", InlineResultLog, activityID, start.Unix(), end.Unix())
+	res := tgbotapi.NewInlineQueryResultArticle(id, fmt.Sprintf(InlineTitleLog, duration, activity), message)
+	res.Description = fmt.Sprintf(InlineDescriptionLogged, start.Format("15:04"), end.Format("15:04"))
+	return res
+}
+
+// TodayResult shares today's report text.
+func TodayResult(total, message string) tgbotapi.InlineQueryResultArticle {
+	res := tgbotapi.NewInlineQueryResultArticle(InlineResultToday, InlineTitleToday, message)
+	res.Description = fmt.Sprintf(InlineDescriptionToday, total)
+	return res
+}
+
+// UsageResult explains query format when it cannot be parsed.
+func UsageResult(message string) tgbotapi.InlineQueryResultArticle {
+	res := tgbotapi.NewInlineQueryResultArticle("usage", InlineTitleUsage, message)
+	res.Description = InlineDescriptionUsage
+	return res
+}
+
+// Answer builds personal answer to inline query, cached for InlineCacheSeconds only.
+func Answer(queryID string, results ...any) tgbotapi.InlineConfig {
+	return tgbotapi.InlineConfig{
+		InlineQueryID: queryID,
+		Results:       results,
+		CacheTime:     InlineCacheSeconds,
+		IsPersonal:    true,
+	}
+}
@@ -0,0 +1,80 @@
+package onboarding
+
+import "tracker-bot/internal/models"
+
+// Inline callbacks; value-carrying ones end with the chosen value.
+const (
+	OnboardingCBLanguage    = "onb:lang:"
+	OnboardingCBTimeZone    = "onb:tz:"
+	OnboardingCBTimeZoneOwn = "onb:tzown"
+	OnboardingCBActivity    = "onb:act:"
+	OnboardingCBActivityOwn = "onb:actown"
+	OnboardingCBNext        = "onb:next"
+	OnboardingCBInterval    = "onb:int:"
+	OnboardingCBQuiet       = "onb:quiet:"
+	OnboardingCBQuietOff    = "onb:quietoff"
+	OnboardingCBStartTimer  = "onb:timer"
+	OnboardingCBLater       = "onb:later"
+	OnboardingCBSkip        = "onb:skip"
+)
+
+// Inline menu buttons.
+const (
+	OnboardingButtonTimeZoneOwn = "⌨️ Other (type it)"
+	OnboardingButtonActivityOn  = "✅ %s"
+	OnboardingButtonActivityOff = "➕ %s"
+	OnboardingButtonActivityOwn = "✍️ Type my own"
+	OnboardingButtonNext        = "Next ➡️"
+	OnboardingButtonInterval    = "%d min"
+	OnboardingButtonQuiet       = "🌙 %02d:00–%02d:00"
+	OnboardingButtonQuietOff    = "🔔 No quiet hours"
+	OnboardingButtonStartTimer  = "▶️ Start timer"
+	OnboardingButtonLater       = "Later"
+	OnboardingButtonSkip        = "⏭ Skip setup"
+)
+
+// Choice is one preset answer of wizard step.
+type Choice struct {
+	Value string
+	Label string
+}
+
+// Languages match users_allowed_language constraint.
+var Languages = []Choice{
+	{Value: "en", Label: "🇺🇸 English"},
+	{Value: "ru", Label: "🇷🇺 Русский"},
+	{Value: "de", Label: "🇩🇪 Deutsch"},
+	{Value: "uk", Label: "🇺🇦 Українська"},
+	{Value: "ar", Label: "🇸🇦 العربية"},
+}
+
+// TimeZones are common IANA zones; others are typed.
+var TimeZones = []Choice{
+	{Value: "Europe/London", Label: "London"},
+	{Value: "Europe/Berlin", Label: "Berlin"},
+	{Value: "Europe/Kyiv", Label: "Kyiv"},
+	{Value: "Europe/Moscow", Label: "Moscow"},
+	{Value: "Asia/Dubai", Label: "Dubai"},
+	{Value: "Asia/Almaty", Label: "Almaty"},
+	{Value: "America/New_York", Label: "New York"},
+	{Value: "America/Los_Angeles", Label: "Los Angeles"},
+	{Value: "UTC", Label: "UTC"},
+}
+
+// StarterActivities are offered on activities step; callbacks carry template index.
+var StarterActivities = []models.ActivityTemplate{
+	{Name: "Coding", Emoji: "💻"},
+	{Name: "Reading", Emoji: "📚"},
+	{Name: "English", Emoji: "🗣"},
+	{Name: "Workout", Emoji: "🏋️"},
+	{Name: "Meditation", Emoji: "🧘"},
+	{Name: "Writing", Emoji: "✍️"},
+	{Name: "Study", Emoji: "🎓"},
+	{Name: "Music", Emoji: "🎸"},
+}
+
+// Intervals are default prompt intervals in minutes.
+var Intervals = []int{15, 30, 45, 60}
+
+// QuietPresets are quiet windows as [from, to] local hours.
+var QuietPresets = [][2]int{{22, 8}, {23, 7}, {0, 9}}
@@ -0,0 +1,99 @@
+package onboarding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"tracker-bot/pkg/buttonbuilder"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Inline button menus
+
+// LanguageInlineMenu offers interface languages.
+func LanguageInlineMenu() tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(Languages)+1)
+	for _, l := range Languages {
+		rows = append(rows, buttonbuilder.IR(buttonbuilder.IB(l.Label, OnboardingCBLanguage+l.Value)))
+	}
+	rows = append(rows, skipRow())
+	return buttonbuilder.IK(rows...)
+}
+
+// TimeZoneInlineMenu offers common zones three per row.
+func TimeZoneInlineMenu() tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(TimeZones)/3+3)
+	var row []tgbotapi.InlineKeyboardButton
+	for _, tz := range TimeZones {
+		row = append(row, buttonbuilder.IB(tz.Label, OnboardingCBTimeZone+tz.Value))
+		if len(row) == 3 {
+			rows = append(rows, buttonbuilder.IR(row...))
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, buttonbuilder.IR(row...))
+	}
+	rows = append(rows, buttonbuilder.IR(buttonbuilder.IB(OnboardingButtonTimeZoneOwn, OnboardingCBTimeZoneOwn)), skipRow())
+	return buttonbuilder.IK(rows...)
+}
+
+// ActivitiesInlineMenu offers starter templates two per row; added ones are checked.
+func ActivitiesInlineMenu(added map[string]bool) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(StarterActivities)/2+3)
+	var row []tgbotapi.InlineKeyboardButton
+	for i, t := range StarterActivities {
+		label := fmt.Sprintf(OnboardingButtonActivityOff, t.Emoji+" "+t.Name)
+		if added[strings.ToLower(t.Name)] {
+			label = fmt.Sprintf(OnboardingButtonActivityOn, t.Emoji+" "+t.Name)
+		}
+		row = append(row, buttonbuilder.IB(label, OnboardingCBActivity+strconv.Itoa(i)))
+		if len(row) == 2 {
+			rows = append(rows, buttonbuilder.IR(row...))
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, buttonbuilder.IR(row...))
+	}
+	rows = append(rows,
+		buttonbuilder.IR(buttonbuilder.IB(OnboardingButtonActivityOwn, OnboardingCBActivityOwn)),
+		buttonbuilder.IR(buttonbuilder.IB(OnboardingButtonNext, OnboardingCBNext)),
+	)
+	return buttonbuilder.IK(rows...)
+}
+
+// IntervalInlineMenu offers default prompt intervals.
+func IntervalInlineMenu() tgbotapi.InlineKeyboardMarkup {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, len(Intervals))
+	for _, interval := range Intervals {
+		row = append(row, buttonbuilder.IB(fmt.Sprintf(OnboardingButtonInterval, interval), OnboardingCBInterval+strconv.Itoa(interval)))
+	}
+	return buttonbuilder.IK(buttonbuilder.IR(row...), skipRow())
+}
+
+// QuietInlineMenu offers quiet hour presets; callback is "onb:quiet:<from>:<to>" in hours.
+func QuietInlineMenu() tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(QuietPresets)+2)
+	for _, q := range QuietPresets {
+		rows = append(rows, buttonbuilder.IR(buttonbuilder.IB(
+			fmt.Sprintf(OnboardingButtonQuiet, q[0], q[1]),
+			fmt.Sprintf("%s%d:%d", OnboardingCBQuiet, q[0], q[1]),
+		)))
+	}
+	rows = append(rows, buttonbuilder.IR(buttonbuilder.IB(OnboardingButtonQuietOff, OnboardingCBQuietOff)), skipRow())
+	return buttonbuilder.IK(rows...)
+}
+
+// TimerInlineMenu asks whether to start prompting now.
+func TimerInlineMenu() tgbotapi.InlineKeyboardMarkup {
+	return buttonbuilder.IK(buttonbuilder.IR(
+		buttonbuilder.IB(OnboardingButtonStartTimer, OnboardingCBStartTimer),
+		buttonbuilder.IB(OnboardingButtonLater, OnboardingCBLater),
+	))
+}
+
+func skipRow() []tgbotapi.InlineKeyboardButton {
+	return buttonbuilder.IR(buttonbuilder.IB(OnboardingButtonSkip, OnboardingCBSkip))
+}
@@ -0,0 +1,27 @@
+package partner
+
+// Inline callbacks; most end with partnership id (and activity id).
+const (
+	PartnerCBList     = "partner:list"
+	PartnerCBInvite   = "partner:invite"
+	PartnerCBAccept   = "partner:accept:"
+	PartnerCBDecline  = "partner:decline"
+	PartnerCBOpen     = "partner:open:"
+	PartnerCBSharing  = "partner:share:"
+	PartnerCBActivity = "partner:act:"
+	PartnerCBNudge    = "partner:nudge:"
+	PartnerCBEnd      = "partner:end:"
+)
+
+// Inline menu buttons.
+const (
+	PartnerButtonInvite      = "➕ Invite partner"
+	PartnerButtonAccept      = "🤝 Accept"
+	PartnerButtonDecline     = "✖️ Decline"
+	PartnerButtonNudge       = "👋 Nudge"
+	PartnerButtonSharing     = "🔒 What I share"
+	PartnerButtonEnd         = "💔 End partnership"
+	PartnerButtonBack        = "⬅️ Back"
+	PartnerButtonActivityOn  = "✅ %s"
+	PartnerButtonActivityOff = "▫️ %s"
+)
@@ -0,0 +1,82 @@
+package partner
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"tracker-bot/internal/models"
+	"tracker-bot/pkg/buttonbuilder"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Inline button menus
+
+// PartnerListInlineMenu lists partners and invite button.
+func PartnerListInlineMenu(partners []models.Partner) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(partners)+1)
+	for _, p := range partners {
+		rows = append(rows, buttonbuilder.IR(
+			buttonbuilder.IB(Label(p.Name), PartnerCBOpen+strconv.FormatInt(p.PartnershipID, 10)),
+		))
+	}
+	rows = append(rows, buttonbuilder.IR(buttonbuilder.IB(PartnerButtonInvite, PartnerCBInvite)))
+	return buttonbuilder.IK(rows...)
+}
+
+// PartnerConsentInlineMenu asks invitee to accept or decline invite.
+func PartnerConsentInlineMenu(token string) tgbotapi.InlineKeyboardMarkup {
+	return buttonbuilder.IK(
+		buttonbuilder.IR(
+			buttonbuilder.IB(PartnerButtonAccept, PartnerCBAccept+token),
+			buttonbuilder.IB(PartnerButtonDecline, PartnerCBDecline),
+		),
+	)
+}
+
+// PartnerInlineMenu shows partner actions; nudge only when partner tracked nothing today.
+func PartnerInlineMenu(p models.PartnerProgress) tgbotapi.InlineKeyboardMarkup {
+	id := strconv.FormatInt(p.Partner.PartnershipID, 10)
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 4)
+	if !p.TrackedToday {
+		rows = append(rows, buttonbuilder.IR(buttonbuilder.IB(PartnerButtonNudge, PartnerCBNudge+id)))
+	}
+	rows = append(rows,
+		buttonbuilder.IR(buttonbuilder.IB(PartnerButtonSharing, PartnerCBSharing+id)),
+		buttonbuilder.IR(
+			buttonbuilder.IB(PartnerButtonBack, PartnerCBList),
+			buttonbuilder.IB(PartnerButtonEnd, PartnerCBEnd+id),
+		),
+	)
+	return buttonbuilder.IK(rows...)
+}
+
+// PartnerSharingInlineMenu toggles activities whose goals partner sees.
+func PartnerSharingInlineMenu(p models.Partner, activities []models.TrackActivityItem) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(activities)+1)
+	for _, a := range activities {
+		title := a.Name
+		if a.Emoji != "" {
+			title = a.Emoji + " " + a.Name
+		}
+		label := fmt.Sprintf(PartnerButtonActivityOff, title)
+		if slices.Contains(p.ActivityIDs, a.ID) {
+			label = fmt.Sprintf(PartnerButtonActivityOn, title)
+		}
+		rows = append(rows, buttonbuilder.IR(
+			buttonbuilder.IB(label, fmt.Sprintf("%s%d:%d", PartnerCBActivity, p.PartnershipID, a.ID)),
+		))
+	}
+	rows = append(rows, buttonbuilder.IR(
+		buttonbuilder.IB(PartnerButtonBack, PartnerCBOpen+strconv.FormatInt(p.PartnershipID, 10)),
+	))
+	return buttonbuilder.IK(rows...)
+}
+
+// Label shows partner by username; users without one stay anonymous.
+func Label(username string) string {
+	if username == "" {
+		return "your partner"
+	}
+	return "@" + username
+}
@@ -6,6 +6,8 @@ const (
 	ProfileCBEditTimeZone = "profile:edit:timezone"
 	ProfileCBEditContact  = "profile:edit:contact"
 	ProfileCBRefresh      = "profile:refresh"
+	ProfileCBShares       = "profile:shares"
+	ProfileCBShareRevoke  = "profile:shares:revoke:"
 )
 
 // Inline menu buttons.
@@ -14,6 +16,9 @@ const (
 	ProfileButtonEditTimeZone = "📍 Time zone"
 	ProfileButtonEditContact  = "📧 Contact"
 	ProfileButtonRefresh      = "🔁 Refresh"
+	ProfileButtonShares       = "🔗 Shared links"
+	ProfileButtonShareRevoke  = "✖️ Revoke #%d"
+	ProfileButtonBack         = "⬅️ Back"
 )
 
 // Language reply menu buttons.
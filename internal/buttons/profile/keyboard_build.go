@@ -1,6 +1,8 @@
 package profile
 
 import (
+	"fmt"
+	"tracker-bot/internal/models"
 	"tracker-bot/pkg/buttonbuilder"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -18,9 +20,24 @@ func ProfileEntryInlineMenu() tgbotapi.InlineKeyboardMarkup {
 			buttonbuilder.IB(ProfileButtonEditContact, ProfileCBEditContact),
 			buttonbuilder.IB(ProfileButtonRefresh, ProfileCBRefresh),
 		),
+		buttonbuilder.IR(
+			buttonbuilder.IB(ProfileButtonShares, ProfileCBShares),
+		),
 	)
 }
 
+// ProfileSharesInlineMenu lists revoke buttons for active report links.
+func ProfileSharesInlineMenu(shares []models.ReportShare) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(shares)+1)
+	for _, s := range shares {
+		rows = append(rows, buttonbuilder.IR(
+			buttonbuilder.IB(fmt.Sprintf(ProfileButtonShareRevoke, s.ID), fmt.Sprintf("%s%d", ProfileCBShareRevoke, s.ID)),
+		))
+	}
+	rows = append(rows, buttonbuilder.IR(buttonbuilder.IB(ProfileButtonBack, ProfileCBRefresh)))
+	return buttonbuilder.IK(rows...)
+}
+
 // Reply button menus
 
 func ProfileLanguageManageReplyMenu() tgbotapi.ReplyKeyboardMarkup {
@@ -0,0 +1,46 @@
+package team
+
+import "time"
+
+// Group inline callbacks; the team is resolved from the chat they come from.
+const (
+	TeamCBJoin           = "team:join"
+	TeamCBSetDigest      = "team:set:digest"
+	TeamCBSetDigestDay   = "team:set:day"
+	TeamCBSetDigestHour  = "team:set:hour"
+	TeamCBSetLeaderboard = "team:set:period"
+)
+
+// Private chat inline callbacks; they end with team id (and activity id).
+const (
+	TeamCBList     = "team:list"
+	TeamCBOpen     = "team:open:"
+	TeamCBSharing  = "team:share:"
+	TeamCBActivity = "team:act:"
+	TeamCBLeave    = "team:leave:"
+)
+
+// Inline menu buttons.
+const (
+	TeamButtonJoin          = "🙋 Join team"
+	TeamButtonDigest        = "📬 Weekly digest"
+	TeamButtonDigestDay     = "📅 Day"
+	TeamButtonDigestHour    = "🕘 Hour (UTC)"
+	TeamButtonLeaderboard   = "🏆 Leaderboard"
+	TeamButtonSharingOn     = "🟢 Sharing totals"
+	TeamButtonSharingOff    = "⚪️ Not sharing"
+	TeamButtonLeave         = "🚪 Leave team"
+	TeamButtonBack          = "⬅️ Back"
+	TeamButtonActivityOn    = "✅ %s"
+	TeamButtonActivityOff   = "▫️ %s"
+	TeamButtonOn            = "on"
+	TeamButtonOff           = "off"
+	TeamButtonLeaderboardNd = "%dd"
+)
+
+// Team setting choices cycled by group admins.
+var (
+	DigestWeekdays  = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+	DigestHours     = []int{6, 9, 12, 15, 18, 21}
+	LeaderboardDays = []int{7, 30}
+)
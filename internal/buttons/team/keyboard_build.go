@@ -0,0 +1,84 @@
+package team
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"tracker-bot/internal/models"
+	"tracker-bot/pkg/buttonbuilder"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Inline button menus
+
+// TeamJoinInlineMenu is shown in group under welcome and leaderboard messages.
+func TeamJoinInlineMenu() tgbotapi.InlineKeyboardMarkup {
+	return buttonbuilder.IK(
+		buttonbuilder.IR(buttonbuilder.IB(TeamButtonJoin, TeamCBJoin)),
+	)
+}
+
+// TeamSettingsInlineMenu shows group settings cycled by admins.
+func TeamSettingsInlineMenu(s models.TeamSettings) tgbotapi.InlineKeyboardMarkup {
+	digest := TeamButtonOff
+	if s.DigestEnabled {
+		digest = TeamButtonOn
+	}
+	return buttonbuilder.IK(
+		buttonbuilder.IR(
+			buttonbuilder.IB(fmt.Sprintf("%s: %s", TeamButtonDigest, digest), TeamCBSetDigest),
+		),
+		buttonbuilder.IR(
+			buttonbuilder.IB(fmt.Sprintf("%s: %s", TeamButtonDigestDay, s.DigestWeekday.String()[:3]), TeamCBSetDigestDay),
+			buttonbuilder.IB(fmt.Sprintf("%s: %02d:00", TeamButtonDigestHour, s.DigestHour), TeamCBSetDigestHour),
+		),
+		buttonbuilder.IR(
+			buttonbuilder.IB(fmt.Sprintf("%s: "+TeamButtonLeaderboardNd, TeamButtonLeaderboard, s.LeaderboardDays), TeamCBSetLeaderboard),
+		),
+	)
+}
+
+// TeamListInlineMenu lists user's teams in private chat.
+func TeamListInlineMenu(memberships []models.TeamMembership) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(memberships))
+	for _, m := range memberships {
+		rows = append(rows, buttonbuilder.IR(
+			buttonbuilder.IB(m.Team.Title, TeamCBOpen+strconv.FormatInt(m.Team.ID, 10)),
+		))
+	}
+	return buttonbuilder.IK(rows...)
+}
+
+// TeamPrivacyInlineMenu shows sharing switch and activities member shares with team.
+func TeamPrivacyInlineMenu(m models.TeamMembership, activities []models.TrackActivityItem) tgbotapi.InlineKeyboardMarkup {
+	id := strconv.FormatInt(m.Team.ID, 10)
+	sharing := TeamButtonSharingOff
+	if m.Sharing {
+		sharing = TeamButtonSharingOn
+	}
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(activities)+3)
+	rows = append(rows, buttonbuilder.IR(buttonbuilder.IB(sharing, TeamCBSharing+id)))
+	for _, a := range activities {
+		label := fmt.Sprintf(TeamButtonActivityOff, activityTitle(a))
+		if slices.Contains(m.ActivityIDs, a.ID) {
+			label = fmt.Sprintf(TeamButtonActivityOn, activityTitle(a))
+		}
+		rows = append(rows, buttonbuilder.IR(
+			buttonbuilder.IB(label, fmt.Sprintf("%s%d:%d", TeamCBActivity, m.Team.ID, a.ID)),
+		))
+	}
+	rows = append(rows, buttonbuilder.IR(
+		buttonbuilder.IB(TeamButtonBack, TeamCBList),
+		buttonbuilder.IB(TeamButtonLeave, TeamCBLeave+id),
+	))
+	return buttonbuilder.IK(rows...)
+}
+
+func activityTitle(a models.TrackActivityItem) string {
+	if a.Emoji == "" {
+		return a.Name
+	}
+	return a.Emoji + " " + a.Name
+}
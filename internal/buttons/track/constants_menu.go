@@ -28,6 +28,8 @@ const (
 	TrackCBReportsPeriodSetRange  = "track:report:period:set_range"
 	TrackCBReportsPeriodText      = "track:report:period:text"
 	TrackCBReportsPeriodChart     = "track:report:period:chart"
+	TrackCBReportsPeriodShare     = "track:report:period:share"
+	TrackCBReportsPeriodShareLink = "track:report:period:share_link"
 	TrackCBReportsCalPrev         = "track:report:cal:prev"
 	TrackCBReportsCalNext         = "track:report:cal:next"
 	TrackCBReportsCalPrevYear     = "track:report:cal:prev_year"
@@ -37,6 +39,46 @@ const (
 	TrackCBReportsCalCancel       = "track:report:cal:cancel"
 	TrackCBReportsCalThisMonth    = "track:report:cal:this_month"
 	TrackCBReportsCalThisYear     = "track:report:cal:this_year"
+	TrackCBGoalsOpen              = "track:goals:open"
+	TrackCBGoalPick               = "track:goals:pick:"
+	TrackCBGoalDelete             = "track:goals:delete:"
+	TrackCBGoalStreak             = "track:goals:streak:"
+	TrackCBDigestOpen             = "track:digest:open"
+	TrackCBDigestToggle           = "track:digest:toggle:"
+	TrackCBDigestTime             = "track:digest:time:"
+	TrackCBDigestCustomTime       = "track:digest:time_custom"
+	TrackCBTagsOpen               = "track:tags:open"
+	TrackCBTagCreate              = "track:tags:create"
+	TrackCBTagOpen                = "track:tags:item:"
+	TrackCBTagAssign              = "track:tags:assign:"
+	TrackCBTagDelete              = "track:tags:delete:"
+	TrackCBReportsPeriodTag       = "track:report:period:tag:"
+	TrackCBEditOpen               = "track:edit:open"
+	TrackCBEditItem               = "track:edit:item:"
+	TrackCBEditMoveUp             = "track:edit:up:"
+	TrackCBEditMoveDown           = "track:edit:down:"
+	TrackCBEditRename             = "track:edit:rename:"
+	TrackCBEditEmoji              = "track:edit:emoji:"
+	TrackCBEditMerge              = "track:edit:merge:"
+	TrackCBEditMergeTo            = "track:edit:merge_to:"
+	TrackCBEditMergeConfirm       = "track:edit:merge_ok:"
+	TrackCBTrashOpen              = "track:trash:open"
+	TrackCBTrashRestore           = "track:trash:restore:"
+	TrackCBTrashUndo              = "track:trash:undo:"
+	TrackCBPurgeAsk               = "track:purge:ask:"
+	TrackCBPurgeConfirm           = "track:purge:confirm:"
+	TrackCBTemplatesOpen          = "track:tpl:open"
+	TrackCBTemplatePack           = "track:tpl:pack:"
+	TrackCBTemplateAdd            = "track:tpl:add:"
+	TrackCBTemplateExport         = "track:tpl:export"
+	TrackCBTemplateImport         = "track:tpl:import"
+	TrackCBPomodoroOpen           = "track:pomo:open"
+	TrackCBPomodoroStart          = "track:pomo:start:"
+	TrackCBPomodoroStop           = "track:pomo:stop"
+	TrackCBPomodoroSkip           = "track:pomo:skip"
+	TrackCBPomodoroNext           = "track:pomo:next"
+	TrackCBPomodoroSetting        = "track:pomo:set:"
+	TrackCBAdaptiveSetting        = "track:adapt:"
 )
 
 // ---------------------------------------------------------------------
@@ -49,6 +91,12 @@ const (
 	TrackButtonExitTracking   = "⏹ Stop Tracking"
 	TrackButtonViewReports    = "📈 Reports"
 	TrackButtonViewArchive    = "🗄 Archive"
+	TrackButtonGoals          = "🎯 Goals"
+	TrackButtonDigests        = "🔔 Digests"
+	TrackButtonTags           = "🏷 Tags"
+	TrackButtonEditActivities = "✏️ Edit"
+	TrackButtonTrash          = "🗑 Trash"
+	TrackButtonTemplates      = "📦 Templates"
 )
 
 // Shared inline labels
@@ -65,6 +113,9 @@ const (
 	TrackLabelSelectedActivities = "Selected activities"
 	TrackLabelTextReport         = "📄 Text report"
 	TrackLabelChartReport        = "📉 Chart report"
+	TrackLabelShareReport        = "📤 Share"
+	TrackLabelShareLink          = "🔗 Create link"
+	TrackLabelOpenLink           = "🌐 Open"
 	TrackLabelSelectActivities   = "🧩 Select activities"
 	TrackLabelBuildChart         = "✅ Build chart"
 	TrackLabelStopTimer          = "⏹ Stop Timer"
@@ -81,6 +132,46 @@ const (
 	TrackLabelSat                = "Sa"
 	TrackLabelSun                = "Su"
 	TrackLabelArchiveItemPrefix  = "📦 "
+	TrackLabelGoalItemPrefix     = "🎯 "
+	TrackLabelGoalDelete         = "🗑 Remove goal"
+	TrackLabelGoalStreakOn       = "🔥 Streak: goal"
+	TrackLabelGoalStreakOff      = "🔥 Streak: any session"
+	TrackLabelDigestDaily        = "End-of-day summary"
+	TrackLabelDigestWeekly       = "Monday weekly review"
+	TrackLabelDigestMonthly      = "First-of-month summary"
+	TrackLabelDigestCustomTime   = "⌨ Custom time"
+	TrackLabelNewTag             = "➕ New tag"
+	TrackLabelDeleteTag          = "🗑 Delete tag"
+	TrackLabelBackToTags         = "↩️ Back to tags"
+	TrackLabelTags               = "Tags"
+	TrackLabelTagItemPrefix      = "🏷 "
+	TrackLabelMoveUp             = "⬆️"
+	TrackLabelMoveDown           = "⬇️"
+	TrackLabelRename             = "✏️ Rename"
+	TrackLabelChangeEmoji        = "😀 Emoji"
+	TrackLabelMerge              = "🔀 Merge into…"
+	TrackLabelConfirmMerge       = "✅ Merge"
+	TrackLabelBackToEdit         = "↩️ Back to list"
+	TrackLabelUndo               = "↩️ Undo"
+	TrackLabelPurge              = "🔥 Delete with history"
+	TrackLabelConfirmPurge       = "🔥 Yes, delete everything"
+	TrackLabelTrashItemPrefix    = "🗑 "
+	TrackLabelAddPack            = "➕ Add %d activities"
+	TrackLabelExportTemplate     = "📤 Export my activities"
+	TrackLabelImportTemplate     = "📥 Import template"
+	TrackLabelBackToTemplates    = "↩️ Back to templates"
+	TrackLabelPomodoroFocus      = "🍅 Focus: %d min"
+	TrackLabelPomodoroShort      = "☕ Short break: %d min"
+	TrackLabelPomodoroLong       = "🛋 Long break: %d min"
+	TrackLabelPomodoroEvery      = "🔁 Long break every %d"
+	TrackLabelPomodoroStart      = "▶️ "
+	TrackLabelPomodoroStop       = "⏹ Stop pomodoro"
+	TrackLabelPomodoroSkip       = "⏭ Skip break"
+	TrackLabelPomodoroNext       = "▶️ Next focus"
+	TrackLabelAdaptiveOn         = "✅ Adaptive interval: on"
+	TrackLabelAdaptiveOff        = "⬜ Adaptive interval: off"
+	TrackLabelAdaptiveMin        = "⏬ Shortest: %d min"
+	TrackLabelAdaptiveMax        = "⏫ Longest: %d min"
 )
 
 // Common reply buttons
@@ -112,6 +203,8 @@ const (
 	TrackButtonTimer30     = "⏱ 30 min"
 	TrackButtonTimer60     = "⏱ 60 min"
 	TrackButtonTimerCreate = "➕ Custom Timer"
+	TrackButtonPomodoro    = "🍅 Pomodoro"
+	TrackButtonAdaptive    = "🧠 Adaptive interval"
 )
 
 // ---------------------------------------------------------------------
@@ -124,6 +217,7 @@ const (
 	TrackUIMainLabelTodayTime       = "⏱ Tracked today:"
 	TrackUIMainLabelStreak          = "🔥 Streak:"
 	TrackUIMainLabelTodayCount      = "✅ Sessions today:"
+	TrackUIMainLabelGoals           = "🎯 Goals:"
 )
 
 // Activity report screen
@@ -141,4 +235,70 @@ const (
 const (
 	TrackMsgActivityListTitle     = "📂 Select Activity"
 	TrackMsgActivityListConfirmed = "📂 Activated Activities:"
+	TrackMsgGoalsTitle            = "🎯 Goals"
+	TrackMsgGoalsEmpty            = "No goals yet. Pick an activity to set one."
+	TrackMsgGoalPrompt            = "Enter goal for %s, e.g. `30m/day`, `5h/week` or `max 1h/day`:"
+	TrackMsgGoalFormat            = "Use format: 30m/day, 5h/week or max 1h/day"
+	TrackMsgDigestTitle           = "🔔 Digests"
+	TrackMsgDigestHint            = "Automatic reports are sent at the chosen local time."
+	TrackMsgDigestTimePrompt      = "Enter delivery time as HH:MM (your local time):"
+	TrackMsgDigestTimeFormat      = "Use format: HH:MM, e.g. 21:30"
+	TrackMsgTagsTitle             = "🏷 Tags"
+	TrackMsgTagsEmpty             = "No tags yet. Tags group activities, e.g. Learning → Go, English."
+	TrackMsgTagPrompt             = "Enter tag name. Use Parent/Name for a subcategory, e.g. Learning/Go:"
+	TrackMsgTagAssignHint         = "Tap activities to assign or unassign this tag."
+	TrackMsgEditTitle             = "✏️ Edit activities"
+	TrackMsgEditHint              = "Tap an activity to rename it, change emoji or merge. Use ⬆️/⬇️ to change order."
+	TrackMsgRenamePrompt          = "Enter new name for %s:"
+	TrackMsgEmojiPrompt           = "Send new emoji for %s, or - to remove it:"
+	TrackMsgMergePick             = "Merge %s into:"
+	TrackMsgMergeConfirm          = "All sessions of %s will move to %s, and %s will be removed. Continue?"
+	TrackMsgTrashTitle            = "🗑 Trash"
+	TrackMsgTrashEmpty            = "Trash is empty."
+	TrackMsgTrashHint             = "Deleted activities keep their history until you delete them with history."
+	TrackMsgDeletedUndo           = "🗑 Moved to trash: %d\nUndo is available for %d sec; later restore from 🗑 Trash in Archive."
+	TrackMsgPurgeConfirm          = "Delete %s together with all tracked sessions? This cannot be undone."
+	TrackMsgTemplatesTitle        = "📦 Templates"
+	TrackMsgTemplatesHint         = "Add a starter pack in one tap, save your activities as a template or import one."
+	TrackMsgTemplatePackHint      = "✅ already in your list, ➕ will be added."
+	TrackMsgTemplateExported      = "Your activities as a template. Copy it and send it back via 📥 Import template, e.g. on another account:"
+	TrackMsgTemplateImportPrompt  = "Paste a template in JSON or YAML, e.g.:\n\nname: Morning\nactivities:\n  - name: Reading\n    emoji: 📚\n  - name: Workout"
+	TrackMsgTemplateInvalid       = "Could not read template. Each activity needs a name (up to 64 characters) and an optional emoji."
+	TrackMsgTemplateTooLarge      = "Template is too large: up to 50 activities at once."
+	TrackMsgPomodoroTitle         = "🍅 Pomodoro"
+	TrackMsgPomodoroHint          = "Focus on one activity, then take a break. Every finished focus block is saved as a session. Tap a setting to change it, then pick an activity to start."
+	TrackMsgPomodoroNoActivities  = "Create an activity first to start a pomodoro."
+	TrackMsgPomodoroTimerStopped  = "⏸ Interval prompts are stopped while you focus. Start them again from ⏱ Timer."
+	TrackMsgAdaptiveTitle         = "🧠 Adaptive interval"
+	TrackMsgAdaptiveHint          = "When you answer the same activity 3 times in a row, prompts come less often. When you keep switching, they come more often. The interval stays between the bounds below."
+	TrackMsgAdaptiveCurrent       = "Current interval: %d min."
+)
+
+// Pomodoro setting keys used in TrackCBPomodoroSetting callbacks.
+const (
+	PomodoroSettingFocus = "focus"
+	PomodoroSettingShort = "short"
+	PomodoroSettingLong  = "long"
+	PomodoroSettingEvery = "every"
+)
+
+// Pomodoro setting values cycled by settings buttons: minutes, and focus blocks per long break.
+var (
+	PomodoroFocusChoices = []int{15, 20, 25, 30, 45, 50, 60}
+	PomodoroShortChoices = []int{3, 5, 10}
+	PomodoroLongChoices  = []int{10, 15, 20, 30}
+	PomodoroEveryChoices = []int{2, 3, 4, 5, 6}
+)
+
+// Adaptive interval setting keys used in TrackCBAdaptiveSetting callbacks.
+const (
+	AdaptiveSettingToggle = "toggle"
+	AdaptiveSettingMin    = "min"
+	AdaptiveSettingMax    = "max"
+)
+
+// Adaptive interval bounds cycled by settings buttons; every shortest bound fits under every longest one.
+var (
+	AdaptiveMinChoices = []int{5, 10, 15, 20, 30}
+	AdaptiveMaxChoices = []int{30, 45, 60, 90, 120}
 )
@@ -22,6 +22,9 @@ func TrackEntryInlineMenu() tgbotapi.InlineKeyboardMarkup {
 			buttonbuilder.IB(TrackButtonViewReports, TrackCBReportSummary),
 			buttonbuilder.IB(TrackButtonViewArchive, TrackCBArchiveOpen),
 		),
+		buttonbuilder.IR(
+			buttonbuilder.IB(TrackButtonGoals, TrackCBGoalsOpen),
+		),
 	)
 }
 
@@ -59,6 +62,7 @@ func TrackArchiveReplyMenu() tgbotapi.ReplyKeyboardMarkup {
 func TrackReportsReplyMenu() tgbotapi.ReplyKeyboardMarkup {
 	return buttonbuilder.RK(
 		buttonbuilder.RR(buttonbuilder.RB(TrackButtonToday), buttonbuilder.RB(TrackButtonPeriod)),
+		buttonbuilder.RR(buttonbuilder.RB(TrackButtonDigests)),
 		buttonbuilder.RR(buttonbuilder.RB(TrackButtonBack), buttonbuilder.RB(TrackButtonBackHome)),
 	)
 }
@@ -66,6 +70,7 @@ func TrackReportsReplyMenu() tgbotapi.ReplyKeyboardMarkup {
 func TrackTimerReplyMenu() tgbotapi.ReplyKeyboardMarkup {
 	return buttonbuilder.RK(
 		buttonbuilder.RR(buttonbuilder.RB(TrackButtonTimer15), buttonbuilder.RB(TrackButtonTimer30)),
+		buttonbuilder.RR(buttonbuilder.RB(TrackButtonPomodoro), buttonbuilder.RB(TrackButtonAdaptive)),
 		buttonbuilder.RR(buttonbuilder.RB(TrackButtonBackHome)),
 	)
 }
@@ -96,6 +101,13 @@ func TrackActivitiesInlineMenu(items []models.TrackActivityItem) tgbotapi.Inline
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData(TrackLabelArchiveSelected, TrackCBArchiveSelected),
 	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackButtonEditActivities, TrackCBEditOpen),
+		tgbotapi.NewInlineKeyboardButtonData(TrackButtonTags, TrackCBTagsOpen),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackButtonTemplates, TrackCBTemplatesOpen),
+	))
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBack, "back_to_main"),
 	))
@@ -144,6 +156,7 @@ func TrackArchiveInlineMenu(items []models.TrackActivityItem) tgbotapi.InlineKey
 	}
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData(TrackLabelActiveActivities, TrackCBArchiveToActive),
+		tgbotapi.NewInlineKeyboardButtonData(TrackButtonTrash, TrackCBTrashOpen),
 	))
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBack, "back_to_main"),
@@ -151,6 +164,296 @@ func TrackArchiveInlineMenu(items []models.TrackActivityItem) tgbotapi.InlineKey
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
+func TrackGoalsInlineMenu(items []models.TrackActivityItem, goals []models.GoalProgress) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(goals)*2+len(items)+1)
+	for _, g := range goals {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelGoalItemPrefix+goalTitle(g.Goal), "noop"),
+		))
+		actions := []tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelGoalDelete, fmt.Sprintf("%s%d", TrackCBGoalDelete, g.Goal.ID)),
+		}
+		if g.Goal.Period == models.GoalPeriodDay {
+			label := TrackLabelGoalStreakOff
+			if g.Goal.UseForStreak {
+				label = TrackLabelGoalStreakOn
+			}
+			actions = append(actions, tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("%s%d", TrackCBGoalStreak, g.Goal.ID)))
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(actions...))
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item.Name) == "" {
+			continue
+		}
+		title := item.Name
+		if item.Emoji != "" {
+			title = item.Emoji + " " + item.Name
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ "+title, fmt.Sprintf("%s%d", TrackCBGoalPick, item.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBack, "back_to_main"),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func TrackDigestInlineMenu(settings models.DigestSettings) tgbotapi.InlineKeyboardMarkup {
+	toggle := func(label string, on bool, kind string) tgbotapi.InlineKeyboardButton {
+		check := "⚪"
+		if on {
+			check = "🟢"
+		}
+		return tgbotapi.NewInlineKeyboardButtonData(check+" "+label, TrackCBDigestToggle+kind)
+	}
+	timeButton := func(minutes int) tgbotapi.InlineKeyboardButton {
+		label := FormatClock(minutes)
+		if minutes == settings.SendAtMin {
+			label = "• " + label
+		}
+		return tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("%s%d", TrackCBDigestTime, minutes))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(toggle(TrackLabelDigestDaily, settings.Daily, models.DigestKindDaily)),
+		tgbotapi.NewInlineKeyboardRow(toggle(TrackLabelDigestWeekly, settings.Weekly, models.DigestKindWeekly)),
+		tgbotapi.NewInlineKeyboardRow(toggle(TrackLabelDigestMonthly, settings.Monthly, models.DigestKindMonthly)),
+		tgbotapi.NewInlineKeyboardRow(timeButton(8*60), timeButton(12*60), timeButton(18*60), timeButton(21*60)),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(TrackLabelDigestCustomTime, TrackCBDigestCustomTime)),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(TrackLabelBackToReports, TrackCBReportsBackHub)),
+	)
+}
+
+// TrackTemplatesInlineMenu lists built-in packs and export/import actions.
+func TrackTemplatesInlineMenu(packs []models.ActivityPack) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(packs)/2+4)
+	var row []tgbotapi.InlineKeyboardButton
+	for _, p := range packs {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(p.Name, TrackCBTemplatePack+p.Key))
+		if len(row) == 2 {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(row...))
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(row...))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelExportTemplate, TrackCBTemplateExport),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelImportTemplate, TrackCBTemplateImport),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBack, TrackCBOpenActivities),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// TrackTemplatePackInlineMenu offers to add pack activities user does not have yet.
+func TrackTemplatePackInlineMenu(key string, missing int) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 2)
+	if missing > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(TrackLabelAddPack, missing), TrackCBTemplateAdd+key),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBackToTemplates, TrackCBTemplatesOpen),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// TrackPomodoroInlineMenu shows pomodoro settings and activities to start focus with.
+func TrackPomodoroInlineMenu(settings models.PomodoroSettings, items []models.TrackActivityItem) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items)+3)
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(TrackLabelPomodoroFocus, settings.FocusMin), TrackCBPomodoroSetting+PomodoroSettingFocus),
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(TrackLabelPomodoroShort, settings.ShortBreakMin), TrackCBPomodoroSetting+PomodoroSettingShort),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(TrackLabelPomodoroLong, settings.LongBreakMin), TrackCBPomodoroSetting+PomodoroSettingLong),
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(TrackLabelPomodoroEvery, settings.LongBreakEvery), TrackCBPomodoroSetting+PomodoroSettingEvery),
+	))
+	for _, item := range items {
+		title := item.Name
+		if item.Emoji != "" {
+			title = item.Emoji + " " + item.Name
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelPomodoroStart+title, fmt.Sprintf("%s%d", TrackCBPomodoroStart, item.ID)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// TrackPomodoroRunningInlineMenu is attached to countdown message; breaks can be skipped
+// and next focus is started by the user once a break is over.
+func TrackPomodoroRunningInlineMenu(phase string) tgbotapi.InlineKeyboardMarkup {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, 2)
+	switch phase {
+	case models.PomodoroPhaseFocus:
+	case models.PomodoroPhaseWaiting:
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(TrackLabelPomodoroNext, TrackCBPomodoroNext))
+	default:
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(TrackLabelPomodoroSkip, TrackCBPomodoroSkip))
+	}
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(TrackLabelPomodoroStop, TrackCBPomodoroStop))
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(row...))
+}
+
+func TrackTagsInlineMenu(tags []models.Tag) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(tags)+2)
+	for _, tag := range tags {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelTagItemPrefix+tag.Path, fmt.Sprintf("%s%d", TrackCBTagOpen, tag.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelNewTag, TrackCBTagCreate),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBack, TrackCBOpenActivities),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func TrackTagInlineMenu(tagID int64, items []models.TrackActivityItem, assigned map[int64]bool) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items)+2)
+	for _, item := range items {
+		if strings.TrimSpace(item.Name) == "" {
+			continue
+		}
+		check := "☐"
+		if assigned[item.ID] {
+			check = "☑"
+		}
+		title := check + " " + item.Name
+		if item.Emoji != "" {
+			title = check + " " + item.Emoji + " " + item.Name
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(title, fmt.Sprintf("%s%d:%d", TrackCBTagAssign, tagID, item.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelDeleteTag, fmt.Sprintf("%s%d", TrackCBTagDelete, tagID)),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBackToTags, TrackCBTagsOpen),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func TrackEditActivitiesInlineMenu(items []models.TrackActivityItem) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items)+1)
+	for _, item := range items {
+		if strings.TrimSpace(item.Name) == "" {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(activityTitle(item), fmt.Sprintf("%s%d", TrackCBEditItem, item.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelMoveUp, fmt.Sprintf("%s%d", TrackCBEditMoveUp, item.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelMoveDown, fmt.Sprintf("%s%d", TrackCBEditMoveDown, item.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBack, TrackCBOpenActivities),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func TrackEditActivityInlineMenu(activityID int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelRename, fmt.Sprintf("%s%d", TrackCBEditRename, activityID)),
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelChangeEmoji, fmt.Sprintf("%s%d", TrackCBEditEmoji, activityID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelMerge, fmt.Sprintf("%s%d", TrackCBEditMerge, activityID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelBackToEdit, TrackCBEditOpen),
+		),
+	)
+}
+
+func TrackMergeTargetsInlineMenu(fromID int64, items []models.TrackActivityItem) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items)+1)
+	for _, item := range items {
+		if item.ID == fromID || strings.TrimSpace(item.Name) == "" {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(activityTitle(item), fmt.Sprintf("%s%d:%d", TrackCBEditMergeTo, fromID, item.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBack, fmt.Sprintf("%s%d", TrackCBEditItem, fromID)),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func TrackMergeConfirmInlineMenu(fromID, toID int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelConfirmMerge, fmt.Sprintf("%s%d:%d", TrackCBEditMergeConfirm, fromID, toID)),
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelCancel, fmt.Sprintf("%s%d", TrackCBEditItem, fromID)),
+		),
+	)
+}
+
+// activityTitle prefixes activity name with its emoji when set.
+func activityTitle(item models.TrackActivityItem) string {
+	if item.Emoji != "" {
+		return item.Emoji + " " + item.Name
+	}
+	return item.Name
+}
+
+func TrackTrashInlineMenu(items []models.TrackActivityItem) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items)*2+1)
+	for _, item := range items {
+		if strings.TrimSpace(item.Name) == "" {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelTrashItemPrefix+activityTitle(item), "noop"),
+		))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelRestore, fmt.Sprintf("%s%d", TrackCBTrashRestore, item.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelPurge, fmt.Sprintf("%s%d", TrackCBPurgeAsk, item.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelOpenArchive, TrackCBOpenArchive),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// TrackPurgeConfirmInlineMenu asks to confirm deletion with history; cancel returns to cancelCB screen.
+func TrackPurgeConfirmInlineMenu(activityID int64, cancelCB string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelConfirmPurge, fmt.Sprintf("%s%d", TrackCBPurgeConfirm, activityID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelCancel, cancelCB),
+		),
+	)
+}
+
+// TrackUndoDeleteInlineMenu offers undo for one deletion identified by its timestamp.
+func TrackUndoDeleteInlineMenu(deletedAt time.Time) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelUndo, fmt.Sprintf("%s%d", TrackCBTrashUndo, deletedAt.UnixMicro())),
+		),
+	)
+}
+
 func TrackCreateSuccessInlineMenu() tgbotapi.InlineKeyboardMarkup {
 	return buttonbuilder.IK(
 		buttonbuilder.IR(
@@ -183,6 +486,9 @@ func TrackReportsHubInlineMenu() tgbotapi.InlineKeyboardMarkup {
 		buttonbuilder.IR(
 			buttonbuilder.IB(TrackButtonPeriod, TrackCBReportsPeriodOpen),
 		),
+		buttonbuilder.IR(
+			buttonbuilder.IB(TrackButtonDigests, TrackCBDigestOpen),
+		),
 		buttonbuilder.IR(
 			buttonbuilder.IB(TrackLabelBack, "back_to_main"),
 		),
@@ -227,8 +533,8 @@ func TrackTodaySelectActivitiesInlineMenu(items []models.TrackActivityItem, sele
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
-func TrackReportPeriodInlineMenu(items []models.TrackActivityItem, selected map[int64]bool, rangeLabel string) tgbotapi.InlineKeyboardMarkup {
-	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items)+5)
+func TrackReportPeriodInlineMenu(items []models.TrackActivityItem, tags []models.Tag, selected map[int64]bool, rangeLabel string) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items)+len(tags)+6)
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData(TrackLabelSelectedActivities, "noop"),
 	))
@@ -248,6 +554,16 @@ func TrackReportPeriodInlineMenu(items []models.TrackActivityItem, selected map[
 			tgbotapi.NewInlineKeyboardButtonData(title, fmt.Sprintf("%s%d", TrackCBReportsPeriodToggle, item.ID)),
 		))
 	}
+	if len(tags) > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TrackLabelTags, "noop"),
+		))
+		for _, tag := range tags {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(TrackLabelTagItemPrefix+tag.Path, fmt.Sprintf("%s%d", TrackCBReportsPeriodTag, tag.ID)),
+			))
+		}
+	}
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData(TrackLabelRangePrefix+rangeLabel, TrackCBReportsPeriodSetRange),
 	))
@@ -255,12 +571,29 @@ func TrackReportPeriodInlineMenu(items []models.TrackActivityItem, selected map[
 		tgbotapi.NewInlineKeyboardButtonData(TrackLabelTextReport, TrackCBReportsPeriodText),
 		tgbotapi.NewInlineKeyboardButtonData(TrackLabelChartReport, TrackCBReportsPeriodChart),
 	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelShareReport, TrackCBReportsPeriodShare),
+	))
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData(TrackLabelBackToReports, TrackCBReportsBackHub),
 	))
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
+// TrackReportShareInlineMenu is attached to report card; link button only when links are enabled.
+func TrackReportShareInlineMenu() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TrackLabelShareLink, TrackCBReportsPeriodShareLink),
+	))
+}
+
+// TrackReportLinkInlineMenu opens shared report page.
+func TrackReportLinkInlineMenu(url string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonURL(TrackLabelOpenLink, url),
+	))
+}
+
 func TrackReportPeriodCalendarInlineMenu(month time.Time, from, to time.Time) tgbotapi.InlineKeyboardMarkup {
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 14)
 	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
@@ -345,3 +678,20 @@ func inRange(day, from, to time.Time) bool {
 	}
 	return !sameDay(day, from) && !sameDay(day, to)
 }
+
+// TrackAdaptiveInlineMenu shows adaptive interval switch and its bounds.
+func TrackAdaptiveInlineMenu(adaptive models.AdaptiveInterval) tgbotapi.InlineKeyboardMarkup {
+	toggle := TrackLabelAdaptiveOff
+	if adaptive.Enabled {
+		toggle = TrackLabelAdaptiveOn
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(toggle, TrackCBAdaptiveSetting+AdaptiveSettingToggle),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(TrackLabelAdaptiveMin, adaptive.MinMin), TrackCBAdaptiveSetting+AdaptiveSettingMin),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(TrackLabelAdaptiveMax, adaptive.MaxMin), TrackCBAdaptiveSetting+AdaptiveSettingMax),
+		),
+	)
+}
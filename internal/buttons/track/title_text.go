@@ -2,8 +2,10 @@ package track
 
 import (
 	"fmt"
+	"strings"
 	"time"
 	"tracker-bot/internal/models"
+	"tracker-bot/pkg/textbuilder"
 )
 
 // Main screen
@@ -26,15 +28,143 @@ type TrackActivityReportStats struct {
 func TrackingMenuText(stats models.MainStats) string {
 	target := 120 * time.Minute
 	progress := progressBar(stats.TodayTracked, target, 10)
-	return fmt.Sprintf(
-		"%s\n\n%s *%s*\n%s *%s*\n`%s`\n%s *%d*\n%s *%d*\n",
+	streak := fmt.Sprintf("%d", stats.StreakDays)
+	if stats.StreakByGoal {
+		streak += " (goal)"
+	}
+	text := fmt.Sprintf(
+		"%s\n\n%s *%s*\n%s *%s*\n`%s`\n%s *%s*\n%s *%d*\n",
 		TrackUIMainTitle,
 		TrackUIMainLabelCurrentActivity, safeText(stats.CurrentActivityName),
 		TrackUIMainLabelTodayTime, formatDuration(stats.TodayTracked),
 		progress,
-		TrackUIMainLabelStreak, stats.StreakDays,
+		TrackUIMainLabelStreak, streak,
 		TrackUIMainLabelTodayCount, stats.TodaySessions,
 	)
+	if len(stats.Goals) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	b.WriteString(text)
+	b.WriteString("\n" + TrackUIMainLabelGoals + "\n")
+	for _, g := range stats.Goals {
+		name := g.Goal.ActivityName
+		if g.Goal.Emoji != "" {
+			name = g.Goal.Emoji + " " + name
+		}
+		b.WriteString(fmt.Sprintf("%s\n`%s`\n", textbuilder.StrOrDashMD(&name), GoalProgressText(g)))
+	}
+	return b.String()
+}
+
+// GoalsMenuText renders goals screen header with current progress.
+func GoalsMenuText(goals []models.GoalProgress) string {
+	if len(goals) == 0 {
+		return TrackMsgGoalsTitle + "\n\n" + TrackMsgGoalsEmpty
+	}
+	var b strings.Builder
+	b.WriteString(TrackMsgGoalsTitle + "\n\n")
+	for _, g := range goals {
+		b.WriteString(fmt.Sprintf("%s\n%s\n\n", goalTitle(g.Goal), GoalProgressText(g)))
+	}
+	return b.String()
+}
+
+// GoalProgressText renders one goal as a progress bar line.
+func GoalProgressText(p models.GoalProgress) string {
+	bar, percent := barWithPercent(p.Tracked, p.Goal.Target, 10)
+	status := ""
+	switch {
+	case p.Goal.Kind == models.GoalKindMax && !p.Met():
+		status = " ⛔"
+	case p.Goal.Kind == models.GoalKindMin && p.Met():
+		status = " ✅"
+	}
+	return fmt.Sprintf("%s %d%% %s/%s%s", bar, percent, formatDuration(p.Tracked), formatDuration(p.Goal.Target), status)
+}
+
+// GoalCompletionText renders goal completion over a report range.
+func GoalCompletionText(c models.GoalCompletion) string {
+	unit := "days"
+	if c.Goal.Period == models.GoalPeriodWeek {
+		unit = "weeks"
+	}
+	return fmt.Sprintf("%s: %d/%d %s met", goalTitle(c.Goal), c.PeriodsMet, c.PeriodsTotal, unit)
+}
+
+// goalTitle renders "📚 English · 5h/week" or "📱 Social · max 1h/day".
+func goalTitle(g models.Goal) string {
+	name := g.ActivityName
+	if g.Emoji != "" {
+		name = g.Emoji + " " + name
+	}
+	prefix := ""
+	if g.Kind == models.GoalKindMax {
+		prefix = "max "
+	}
+	return fmt.Sprintf("%s · %s%s/%s", name, prefix, formatDuration(g.Target), g.Period)
+}
+
+// DigestSettingsText renders digest settings screen.
+func DigestSettingsText(settings models.DigestSettings) string {
+	return fmt.Sprintf("%s\n\n%s\nDelivery time: %s", TrackMsgDigestTitle, TrackMsgDigestHint, FormatClock(settings.SendAtMin))
+}
+
+// PomodoroText renders countdown message of running pomodoro phase.
+func PomodoroText(p models.Pomodoro, now time.Time) string {
+	name := p.ActivityName
+	if p.ActivityEmoji != "" {
+		name = p.ActivityEmoji + " " + name
+	}
+	if p.Phase == models.PomodoroPhaseWaiting {
+		return fmt.Sprintf("🍅 Ready for focus #%d · %s?\n\nTap %s when you are back.", p.Cycle+1, name, TrackLabelPomodoroNext)
+	}
+	var title string
+	switch p.Phase {
+	case models.PomodoroPhaseFocus:
+		title = fmt.Sprintf("🍅 Focus #%d · %s", p.Cycle, name)
+	case models.PomodoroPhaseLongBreak:
+		title = "🛋 Long break"
+	default:
+		title = "☕ Short break"
+	}
+
+	total := p.PhaseEndsAt.Sub(p.PhaseStartedAt)
+	bar, _ := barWithPercent(now.Sub(p.PhaseStartedAt), total, 10)
+	text := fmt.Sprintf("%s\n\n%s\n⏳ %d min left of %s", title, bar, PomodoroMinutesLeft(p, now), formatDuration(total))
+
+	if every := p.Settings.LongBreakEvery; every > 0 && p.Phase == models.PomodoroPhaseFocus {
+		longAfter := (p.Cycle + every - 1) / every * every
+		text += fmt.Sprintf("\n🛋 Long break after focus #%d", longAfter)
+	}
+	return text
+}
+
+// PomodoroDoneText replaces countdown of a finished phase.
+func PomodoroDoneText(p models.Pomodoro) string {
+	if p.Phase != models.PomodoroPhaseFocus {
+		return "✅ Break is over"
+	}
+	name := p.ActivityName
+	if p.ActivityEmoji != "" {
+		name = p.ActivityEmoji + " " + name
+	}
+	return fmt.Sprintf("✅ Focus #%d done · %s of %s saved", p.Cycle, formatDuration(p.PhaseEndsAt.Sub(p.PhaseStartedAt)), name)
+}
+
+// PomodoroMinutesLeft returns whole minutes left in current phase, rounded up.
+func PomodoroMinutesLeft(p models.Pomodoro, now time.Time) int {
+	left := p.PhaseEndsAt.Sub(now)
+	if left <= 0 {
+		return 0
+	}
+	return int((left + time.Minute - 1) / time.Minute)
+}
+
+// FormatClock formats minutes after midnight as "HH:MM".
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
 }
 
 // formatDuration formats duration into human-readable string like "4h 30m".
@@ -73,6 +203,22 @@ func progressBar(value, target time.Duration, width int) string {
 	if value < 0 {
 		value = 0
 	}
+	bar, percent := barWithPercent(value, target, width)
+	if percent > 100 {
+		percent = 100
+	}
+	return fmt.Sprintf("Progress: %s (%d%%, target %s)", bar, percent, formatDuration(target))
+}
+
+// barWithPercent renders a fixed-width bar and uncapped percent of target.
+func barWithPercent(value, target time.Duration, width int) (string, int) {
+	if target <= 0 {
+		target = time.Minute
+	}
+	if value < 0 {
+		value = 0
+	}
+	percent := int(float64(value) / float64(target) * 100)
 	ratio := float64(value) / float64(target)
 	if ratio > 1 {
 		ratio = 1
@@ -85,7 +231,6 @@ func progressBar(value, target time.Duration, width int) string {
 		filled = 0
 	}
 
-	percent := int(ratio * 100)
 	bar := ""
 	for i := 0; i < width; i++ {
 		if i < filled {
@@ -94,5 +239,5 @@ func progressBar(value, target time.Duration, width int) string {
 			bar += "░"
 		}
 	}
-	return fmt.Sprintf("Progress: %s (%d%%, target %s)", bar, percent, formatDuration(target))
+	return bar, percent
 }
@@ -3,14 +3,22 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
 	Telegram         Telegram
+	Webhook          Webhook
+	Admin            Admin
+	Share            Share
+	Log              Log
+	Tracing          Tracing
 	PostreSQL        PgConfig
 	TestTimerMinutes int `env:"TEST_TIMER_MINUTES" env-default:"0"`
+	// ShutdownTimeout bounds stop of each component.
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"15s"`
 }
 type PgConfig struct {
 	Host    string `env:"HOST_DB"`
@@ -21,10 +29,63 @@ type PgConfig struct {
 	SSLMode string `env:"SSL_MODE" env-default:"disable"`
 }
 type Telegram struct {
-	TelegramToken    string `env:"TELEGRAM_TOKEN"`
-	TelegramBotDebug bool   `env:"TELEGRAM_BOT_DEBUG"`
+	TelegramToken    string  `env:"TELEGRAM_TOKEN"`
+	TelegramBotDebug bool    `env:"TELEGRAM_BOT_DEBUG"`
+	GlobalRate       float64 `env:"TELEGRAM_GLOBAL_RATE" env-default:"30"`
+	ChatRate         float64 `env:"TELEGRAM_CHAT_RATE" env-default:"1"`
+	// Mode is "polling" (default) or "webhook".
+	Mode string `env:"TELEGRAM_MODE" env-default:"polling"`
 }
 
+// Webhook configures update delivery over HTTP when Telegram.Mode is "webhook".
+type Webhook struct {
+	URL          string        `env:"WEBHOOK_URL"`
+	Listen       string        `env:"WEBHOOK_LISTEN" env-default:":8443"`
+	Path         string        `env:"WEBHOOK_PATH" env-default:"/telegram/webhook"`
+	SecretToken  string        `env:"WEBHOOK_SECRET_TOKEN"`
+	TLSCert      string        `env:"WEBHOOK_TLS_CERT"`
+	TLSKey       string        `env:"WEBHOOK_TLS_KEY"`
+	DrainTimeout time.Duration `env:"WEBHOOK_DRAIN_TIMEOUT" env-default:"10s"`
+}
+
+// Admin configures health, readiness and metrics HTTP server.
+type Admin struct {
+	// Listen is server address; empty disables admin server.
+	Listen string `env:"ADMIN_LISTEN" env-default:":9090"`
+	// TgUserIDs may run operator commands (/stats, /user, /broadcast, /timers, /impersonate).
+	TgUserIDs []int64 `env:"ADMIN_TG_USER_IDS" env-separator:","`
+}
+
+// Share configures read-only report links.
+type Share struct {
+	// Listen is share server address; empty disables it.
+	Listen string `env:"SHARE_LISTEN"`
+	// BaseURL is public address of share server, e.g. https://reports.example.com.
+	BaseURL string `env:"SHARE_BASE_URL"`
+	// Secret signs link tokens; links are disabled when empty.
+	Secret string        `env:"SHARE_SECRET"`
+	TTL    time.Duration `env:"SHARE_TTL" env-default:"168h"`
+}
+
+// Log configures global logger.
+type Log struct {
+	Level  string `env:"LOG_LEVEL" env-default:"info"`
+	Format string `env:"LOG_FORMAT" env-default:"json"` // json | console
+}
+
+// Tracing configures OpenTelemetry span export.
+type Tracing struct {
+	Exporter    string  `env:"TRACING_EXPORTER" env-default:"none"` // none | stdout | otlp
+	Endpoint    string  `env:"TRACING_OTLP_ENDPOINT"`
+	Insecure    bool    `env:"TRACING_OTLP_INSECURE"`
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
 const (
 	envConfigPath     = "CONFIG_PATH"
 	defaultConfigPath = ".env"
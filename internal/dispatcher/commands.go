@@ -0,0 +1,151 @@
+package dispatcher
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// commandNames lists public commands in menu order.
+var commandNames = []string{"log", "start_timer", "stop", "today", "report", "pomodoro", "templates", "teams", "partners", "help"}
+
+// groupCommandNames lists commands served in groups.
+var groupCommandNames = []string{"leaderboard", "join", "leave", "team_settings", "help"}
+
+// commandDescriptions holds command menu descriptions per user language.
+// "en" is also registered as default for languages not listed here.
+var commandDescriptions = map[string]map[string]string{
+	"en": {
+		"log":           "Log time: /log Go 45m",
+		"start_timer":   "Start prompts: /start_timer 20",
+		"stop":          "Stop prompts",
+		"today":         "Today report",
+		"report":        "Period report: /report 2026-09-01..2026-09-30",
+		"pomodoro":      "Pomodoro: /pomodoro Go",
+		"templates":     "Activity packs, export and import",
+		"teams":         "Teams and what you share",
+		"partners":      "Accountability partners",
+		"help":          "Command help",
+		"leaderboard":   "Team ranking",
+		"join":          "Join team",
+		"leave":         "Leave team",
+		"team_settings": "Team settings (admins)",
+	},
+	"ru": {
+		"log":           "Записать время: /log Go 45m",
+		"start_timer":   "Включить напоминания: /start_timer 20",
+		"stop":          "Выключить напоминания",
+		"today":         "Отчёт за сегодня",
+		"report":        "Отчёт за период: /report 2026-09-01..2026-09-30",
+		"pomodoro":      "Помидоро: /pomodoro Go",
+		"templates":     "Наборы активностей, экспорт и импорт",
+		"teams":         "Команды и что вы им показываете",
+		"partners":      "Партнёры по целям",
+		"help":          "Справка по командам",
+		"leaderboard":   "Рейтинг команды",
+		"join":          "Вступить в команду",
+		"leave":         "Выйти из команды",
+		"team_settings": "Настройки команды (админы)",
+	},
+	"de": {
+		"log":           "Zeit erfassen: /log Go 45m",
+		"start_timer":   "Erinnerungen starten: /start_timer 20",
+		"stop":          "Erinnerungen stoppen",
+		"today":         "Bericht für heute",
+		"report":        "Zeitraumbericht: /report 2026-09-01..2026-09-30",
+		"pomodoro":      "Pomodoro: /pomodoro Go",
+		"templates":     "Aktivitätspakete, Export und Import",
+		"teams":         "Teams und was du teilst",
+		"partners":      "Accountability-Partner",
+		"help":          "Befehlshilfe",
+		"leaderboard":   "Team-Rangliste",
+		"join":          "Team beitreten",
+		"leave":         "Team verlassen",
+		"team_settings": "Team-Einstellungen (Admins)",
+	},
+	"uk": {
+		"log":           "Записати час: /log Go 45m",
+		"start_timer":   "Увімкнути нагадування: /start_timer 20",
+		"stop":          "Вимкнути нагадування",
+		"today":         "Звіт за сьогодні",
+		"report":        "Звіт за період: /report 2026-09-01..2026-09-30",
+		"pomodoro":      "Помідоро: /pomodoro Go",
+		"templates":     "Набори активностей, експорт та імпорт",
+		"teams":         "Команди і що ви їм показуєте",
+		"partners":      "Партнери за цілями",
+		"help":          "Довідка з команд",
+		"leaderboard":   "Рейтинг команди",
+		"join":          "Вступити до команди",
+		"leave":         "Вийти з команди",
+		"team_settings": "Налаштування команди (адміни)",
+	},
+	"ar": {
+		"log":           "تسجيل الوقت: /log Go 45m",
+		"start_timer":   "تشغيل التذكيرات: /start_timer 20",
+		"stop":          "إيقاف التذكيرات",
+		"today":         "تقرير اليوم",
+		"report":        "تقرير الفترة: /report 2026-09-01..2026-09-30",
+		"pomodoro":      "بومودورو: /pomodoro Go",
+		"templates":     "حزم الأنشطة والتصدير والاستيراد",
+		"teams":         "الفرق وما تشاركه",
+		"partners":      "شركاء الالتزام",
+		"help":          "مساعدة الأوامر",
+		"leaderboard":   "ترتيب الفريق",
+		"join":          "الانضمام إلى الفريق",
+		"leave":         "مغادرة الفريق",
+		"team_settings": "إعدادات الفريق (المشرفون)",
+	},
+}
+
+// helpText is reply to /help.
+const helpText = `Доступные команды:
+/log Go 45m — записать 45 минут
+/log English 14:00-15:30 yesterday — записать интервал
+/start_timer 20 — напоминать каждые 20 минут
+/stop — выключить напоминания
+/today — отчёт за сегодня
+/report 2026-09-01..2026-09-30 Go,English — отчёт за период
+/pomodoro Go — помидоро: фокус и перерывы, фокус сохраняется как сессия
+/templates — наборы активностей, экспорт и импорт
+/teams — команды и что вы им показываете
+/partners — партнёры: цели друг друга и напоминания
+/start — главное меню`
+
+// groupHelpText is reply to /help in groups.
+const groupHelpText = `Team commands:
+/join — join the team
+/leaderboard — ranking of members who share their time
+/leave — leave the team
+/team_settings — weekly digest and leaderboard window (group admins)
+
+Members choose what to share via /teams in private chat with the bot.`
+
+// RegisterCommands publishes command menus for private chats and groups in every supported language.
+func (d *Dispatcher) RegisterCommands() error {
+	if err := d.registerCommands(tgbotapi.NewBotCommandScopeAllPrivateChats(), commandNames); err != nil {
+		return err
+	}
+	return d.registerCommands(tgbotapi.NewBotCommandScopeAllGroupChats(), groupCommandNames)
+}
+
+func (d *Dispatcher) registerCommands(scope tgbotapi.BotCommandScope, names []string) error {
+	if _, err := d.bot.Request(tgbotapi.NewSetMyCommandsWithScope(scope, botCommands("en", names)...)); err != nil {
+		return fmt.Errorf("register commands %s: %w", scope.Type, err)
+	}
+	for lang := range commandDescriptions {
+		cfg := tgbotapi.NewSetMyCommandsWithScopeAndLanguage(scope, lang, botCommands(lang, names)...)
+		if _, err := d.bot.Request(cfg); err != nil {
+			return fmt.Errorf("register commands %s %s: %w", scope.Type, lang, err)
+		}
+	}
+	return nil
+}
+
+func botCommands(lang string, names []string) []tgbotapi.BotCommand {
+	descriptions := commandDescriptions[lang]
+	out := make([]tgbotapi.BotCommand, 0, len(names))
+	for _, name := range names {
+		out = append(out, tgbotapi.BotCommand{Command: name, Description: descriptions[name]})
+	}
+	return out
+}
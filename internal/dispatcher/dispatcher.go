@@ -3,23 +3,36 @@ package dispatcher
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	adminbtn "tracker-bot/internal/buttons/admin"
+	onboardingbtn "tracker-bot/internal/buttons/onboarding"
+	partnerbtn "tracker-bot/internal/buttons/partner"
+	profilebtn "tracker-bot/internal/buttons/profile"
+	teambtn "tracker-bot/internal/buttons/team"
 	trackbtn "tracker-bot/internal/buttons/track"
+	"tracker-bot/internal/metrics"
 	"tracker-bot/internal/models"
 	"tracker-bot/internal/service"
+	"tracker-bot/internal/tracing"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	h "tracker-bot/internal/buttons/handlers"
 	"tracker-bot/internal/handlers"
+	"tracker-bot/internal/utils/tgclient"
 	"tracker-bot/internal/utils/tgctx"
 )
 
 type Dispatcher struct {
-	bot          *tgbotapi.BotAPI
+	bot          tgclient.BotAPI
 	appCtx       context.Context
 	entrysvc     service.EntryService
 	track        *handlers.Module
@@ -31,6 +44,11 @@ type Dispatcher struct {
 	reply *h.ReplyModule
 
 	waitingActivityName map[int64]bool
+	waitingGoalActivity map[int64]int64
+	waitingDigestTime   map[int64]bool
+	waitingTagName      map[int64]bool
+	waitingRename       map[int64]int64
+	waitingEmoji        map[int64]int64
 	userScreen          map[int64]string
 	reportSelected      map[int64]map[int64]bool
 	reportFrom          map[int64]time.Time
@@ -39,6 +57,14 @@ type Dispatcher struct {
 	reportCalMonth      map[int64]time.Time
 	reportCalFrom       map[int64]time.Time
 	reportCalTo         map[int64]time.Time
+	waitingBroadcast    map[int64]bool
+	waitingBroadcastAt  map[int64]int64
+	waitingOnbTimeZone  map[int64]bool
+	waitingTemplate     map[int64]bool
+	waitingOnbActivity  map[int64]bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 const (
@@ -49,10 +75,11 @@ const (
 	screenTrackArchive   = "track_archive"
 	screenCreateActivity = "create_activity"
 	screenTrackReports   = "track_reports"
+	screenTrackGoals     = "track_goals"
 )
 
 func New(
-	bot *tgbotapi.BotAPI,
+	bot tgclient.BotAPI,
 	appCtx context.Context,
 	entrysvc service.EntryService,
 	track *handlers.Module,
@@ -79,6 +106,11 @@ func New(
 		profile:             profile,
 		learning:            learning,
 		waitingActivityName: make(map[int64]bool),
+		waitingGoalActivity: make(map[int64]int64),
+		waitingDigestTime:   make(map[int64]bool),
+		waitingTagName:      make(map[int64]bool),
+		waitingRename:       make(map[int64]int64),
+		waitingEmoji:        make(map[int64]int64),
 		userScreen:          make(map[int64]string),
 		reportSelected:      make(map[int64]map[int64]bool),
 		reportFrom:          make(map[int64]time.Time),
@@ -87,26 +119,184 @@ func New(
 		reportCalMonth:      make(map[int64]time.Time),
 		reportCalFrom:       make(map[int64]time.Time),
 		reportCalTo:         make(map[int64]time.Time),
+		waitingBroadcast:    make(map[int64]bool),
+		waitingBroadcastAt:  make(map[int64]int64),
+		waitingOnbTimeZone:  make(map[int64]bool),
+		waitingTemplate:     make(map[int64]bool),
+		waitingOnbActivity:  make(map[int64]bool),
+		stop:                make(chan struct{}),
 	}
 
 	d.reply = h.New(bot, track, subscription, entry, profile, learning)
 	return d
 }
 
-// Run listens for Telegram updates and routes them by update type.
+// AllowedUpdates lists update types the dispatcher handles.
+var AllowedUpdates = []string{"message", "callback_query", "my_chat_member", "inline_query", "chosen_inline_result"}
+
+// Run listens for Telegram updates via long polling and routes them.
 func (d *Dispatcher) Run() {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
+	u.AllowedUpdates = AllowedUpdates
 
-	updates := d.bot.GetUpdatesChan(u)
+	d.serve(d.bot.GetUpdatesChan(u), d.stop)
+}
+
+// Stop ends long polling; Run returns after handling updates already buffered.
+// Updates from a poll still in progress are not confirmed, so Telegram delivers them again.
+func (d *Dispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+		d.bot.StopReceivingUpdates()
+	})
+}
 
-	for update := range updates {
-		switch {
-		case update.Message != nil:
-			d.handleMessage(update.Message)
+// Serve routes updates by type until channel is closed (e.g. by webhook shutdown).
+func (d *Dispatcher) Serve(updates tgbotapi.UpdatesChannel) {
+	d.serve(updates, nil)
+}
 
-		case update.CallbackQuery != nil:
-			d.handleCallback(update.CallbackQuery)
+// serve handles updates one by one until channel is closed or stop fires.
+func (d *Dispatcher) serve(updates tgbotapi.UpdatesChannel, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			// Buffered updates were already confirmed by the next poll; handle them before exit.
+			for {
+				select {
+				case update, ok := <-updates:
+					if !ok {
+						return
+					}
+					d.route(update)
+				default:
+					return
+				}
+			}
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			d.route(update)
+		}
+	}
+}
+
+// route handles one update by its type inside root span with update-scoped logger.
+func (d *Dispatcher) route(update tgbotapi.Update) {
+	began := time.Now()
+	typ := updateType(update)
+	ctx, span := tracing.Start(d.appCtx, "update."+typ, attribute.Int("telegram.update_id", update.UpdateID))
+	defer func() {
+		span.End()
+		metrics.ObserveUpdate(typ, time.Since(began))
+	}()
+
+	lc := log.With().Int("update_id", update.UpdateID).Str("update_type", typ)
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		lc = lc.Str("trace_id", traceID)
+	}
+	logger := lc.Logger()
+	ctx = logger.WithContext(ctx)
+
+	switch {
+	case update.Message != nil:
+		d.handleMessage(ctx, update.Message)
+
+	case update.CallbackQuery != nil:
+		d.handleCallback(ctx, update.CallbackQuery)
+
+	case update.MyChatMember != nil:
+		d.handleMyChatMember(ctx, update.MyChatMember)
+
+	case update.InlineQuery != nil:
+		d.handleInlineQuery(ctx, update.InlineQuery)
+
+	case update.ChosenInlineResult != nil:
+		d.handleChosenInlineResult(ctx, update.ChosenInlineResult)
+	}
+}
+
+// handleInlineQuery answers "@bot <activity> <time>" typed in any chat.
+// Inline queries have no chat, so errors go to user's private chat.
+func (d *Dispatcher) handleInlineQuery(ctx context.Context, q *tgbotapi.InlineQuery) {
+	if q.From == nil {
+		return
+	}
+	userID := int64(q.From.ID)
+	mctx := newRequestContext(ctx, userID, userID, nil)
+	if !d.ensureUser(mctx, userID, q.From) {
+		return
+	}
+	d.track.AnswerInlineQuery(mctx, q.ID, q.Query)
+}
+
+// handleChosenInlineResult records session for chosen inline result.
+// Requires inline feedback enabled for the bot in BotFather.
+func (d *Dispatcher) handleChosenInlineResult(ctx context.Context, r *tgbotapi.ChosenInlineResult) {
+	if r.From == nil {
+		return
+	}
+	userID := int64(r.From.ID)
+	mctx := newRequestContext(ctx, userID, userID, func(c zerolog.Context) zerolog.Context {
+		return c.Str("result_id", r.ResultID)
+	})
+	if !d.ensureUser(mctx, userID, r.From) {
+		return
+	}
+	d.track.RecordInlineResult(mctx, r.ResultID)
+}
+
+// updateType returns metrics label for update.
+func updateType(update tgbotapi.Update) string {
+	switch {
+	case update.Message != nil:
+		if update.Message.IsCommand() {
+			return "command"
+		}
+		return "message"
+	case update.CallbackQuery != nil:
+		return "callback_query"
+	case update.MyChatMember != nil:
+		return "my_chat_member"
+	case update.InlineQuery != nil:
+		return "inline_query"
+	case update.ChosenInlineResult != nil:
+		return "chosen_inline_result"
+	default:
+		return "other"
+	}
+}
+
+// handleMyChatMember tracks whether user blocked or unblocked the bot in private chat
+// and whether the bot was added to or removed from a group.
+func (d *Dispatcher) handleMyChatMember(ctx context.Context, upd *tgbotapi.ChatMemberUpdated) {
+	if upd.Chat.IsGroup() || upd.Chat.IsSuperGroup() {
+		d.handleGroupMembership(ctx, upd)
+		return
+	}
+	if !upd.Chat.IsPrivate() {
+		return
+	}
+
+	switch upd.NewChatMember.Status {
+	case "kicked", "left":
+		d.track.SetUserActive(ctx, upd.Chat.ID, false)
+	case "member":
+		d.track.SetUserActive(ctx, upd.Chat.ID, true)
+	}
+}
+
+// handleGroupMembership registers team when bot joins group and deactivates it when bot leaves.
+func (d *Dispatcher) handleGroupMembership(ctx context.Context, upd *tgbotapi.ChatMemberUpdated) {
+	wasOut := upd.OldChatMember.Status == "left" || upd.OldChatMember.Status == "kicked"
+	switch upd.NewChatMember.Status {
+	case "kicked", "left":
+		d.track.TeamRemoved(ctx, upd.Chat.ID)
+	case "member", "administrator":
+		if wasOut {
+			d.track.TeamAdded(ctx, upd.Chat.ID, upd.Chat.Title)
 		}
 	}
 }
@@ -124,42 +314,81 @@ func (d *Dispatcher) ensureUser(ctx *tgctx.MsgContext, chatID int64, from *tgbot
 
 	dbID, err := d.entrysvc.EnsureUser(ctx.Ctx, in)
 	if err != nil {
-		log.Error().Err(err).Msg("ensure user failed")
+		ctx.Log.Error().Err(err).Msg("ensure user failed")
 		out := tgbotapi.NewMessage(chatID, "⚠️ Ошибка. Попробуй ещё раз.")
 		_, _ = d.bot.Send(out)
 		return false
 	}
 	ctx.DBUserID = dbID
+	ctx.Log = ctx.Log.With().Int64("user_id", dbID).Logger()
+	ctx.Ctx = ctx.Log.WithContext(ctx.Ctx)
+	trace.SpanFromContext(ctx.Ctx).SetAttributes(attribute.Int64("user.id", dbID))
 	return true
 }
 
 // newMessageContext converts Telegram message into internal context.
-func (d *Dispatcher) newMessageContext(msg *tgbotapi.Message) *tgctx.MsgContext {
-	ctx := &tgctx.MsgContext{
-		Ctx:    d.appCtx,
-		ChatID: msg.Chat.ID,
-		Text:   msg.Text,
-	}
-
+// Message text is not logged; only command name is.
+func (d *Dispatcher) newMessageContext(ctx context.Context, msg *tgbotapi.Message) *tgctx.MsgContext {
+	var userID int64
 	if msg.From != nil {
-		ctx.UserID = int64(msg.From.ID)
+		userID = int64(msg.From.ID)
 	}
+	mctx := newRequestContext(ctx, msg.Chat.ID, userID, func(c zerolog.Context) zerolog.Context {
+		if msg.IsCommand() {
+			return c.Str("command", msg.Command())
+		}
+		return c
+	})
+	mctx.Text = msg.Text
+	mctx.IsGroup = msg.Chat.IsGroup() || msg.Chat.IsSuperGroup()
+	return mctx
+}
 
-	return ctx
+// newRequestContext builds handler context whose logger and span carry chat and user ids.
+func newRequestContext(ctx context.Context, chatID, userID int64, fields func(zerolog.Context) zerolog.Context) *tgctx.MsgContext {
+	lc := zerolog.Ctx(ctx).With().Int64("chat_id", chatID).Int64("tg_user_id", userID)
+	if fields != nil {
+		lc = fields(lc)
+	}
+	logger := lc.Logger()
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int64("telegram.chat_id", chatID),
+		attribute.Int64("telegram.user_id", userID),
+	)
+	return &tgctx.MsgContext{
+		Ctx:    logger.WithContext(ctx),
+		Log:    logger,
+		ChatID: chatID,
+		UserID: userID,
+	}
 }
 
 // handleMessage processes incoming text/command updates.
-func (d *Dispatcher) handleMessage(msg *tgbotapi.Message) {
+func (d *Dispatcher) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
 	if msg == nil || msg.From == nil {
 		return
 	}
 
-	mctx := d.newMessageContext(msg)
+	mctx := d.newMessageContext(ctx, msg)
+
+	// Groups only get team commands, so private flows never prompt inside group.
+	if mctx.IsGroup {
+		d.handleGroupMessage(mctx, msg)
+		return
+	}
 
 	if !d.ensureUser(mctx, msg.Chat.ID, msg.From) {
 		return
 	}
 
+	// Broadcast drafts may be photos, so they are taken before text-only handlers.
+	if d.waitingBroadcast[mctx.UserID] && !msg.IsCommand() {
+		if d.handleBroadcastDraft(mctx, msg) {
+			delete(d.waitingBroadcast, mctx.UserID)
+		}
+		return
+	}
+
 	// Handle slash commands first, so they are not treated as plain reply button text.
 	if msg.IsCommand() {
 		d.handleCommand(msg, mctx)
@@ -184,25 +413,53 @@ func (d *Dispatcher) handleMessage(msg *tgbotapi.Message) {
 }
 
 // handleCallback processes incoming inline callback updates.
-func (d *Dispatcher) handleCallback(q *tgbotapi.CallbackQuery) {
+func (d *Dispatcher) handleCallback(ctx context.Context, q *tgbotapi.CallbackQuery) {
 	if q == nil || q.Message == nil || q.From == nil {
 		return
 	}
 
+	mctx := newRequestContext(ctx, q.Message.Chat.ID, int64(q.From.ID), func(c zerolog.Context) zerolog.Context {
+		return c.Str("callback_data", q.Data)
+	})
+	mctx.Text = q.Data
+	mctx.MessageID = q.Message.MessageID
+	mctx.IsGroup = q.Message.Chat.IsGroup() || q.Message.Chat.IsSuperGroup()
+
 	ack := tgbotapi.NewCallback(q.ID, "")
 	if _, err := d.bot.Request(ack); err != nil {
-		log.Error().Err(err).Msg("callback ack failed")
+		mctx.Log.Error().Err(err).Msg("callback ack failed")
 	}
 
-	mctx := &tgctx.MsgContext{
-		Ctx:       d.appCtx,
-		ChatID:    q.Message.Chat.ID,
-		Text:      q.Data,
-		UserID:    int64(q.From.ID),
-		MessageID: q.Message.MessageID,
+	if !d.ensureUser(mctx, q.Message.Chat.ID, q.From) {
+		return
 	}
 
-	if !d.ensureUser(mctx, q.Message.Chat.ID, q.From) {
+	if mctx.IsGroup {
+		d.handleGroupCallback(mctx, q)
+		return
+	}
+
+	if strings.HasPrefix(q.Data, "admin:") {
+		d.handleAdminCallback(mctx, q.Data)
+		return
+	}
+
+	if strings.HasPrefix(q.Data, "team:") {
+		d.handleTeamCallback(mctx, q.Data)
+		return
+	}
+
+	if strings.HasPrefix(q.Data, "partner:") {
+		d.handlePartnerCallback(mctx, q.Data)
+		return
+	}
+
+	if strings.HasPrefix(q.Data, "onb:") {
+		d.handleOnboardingCallback(mctx, q.Data)
+		return
+	}
+
+	if strings.HasPrefix(q.Data, "profile:") && d.handleProfileCallback(mctx, q.Data) {
 		return
 	}
 
@@ -216,8 +473,269 @@ func (d *Dispatcher) handleCallback(q *tgbotapi.CallbackQuery) {
 	}
 }
 
+// handleGroupMessage serves team commands in groups; other messages and commands of other bots are ignored.
+func (d *Dispatcher) handleGroupMessage(ctx *tgctx.MsgContext, msg *tgbotapi.Message) {
+	if msg.MigrateToChatID != 0 {
+		d.track.TeamChatMigrated(ctx.Ctx, msg.Chat.ID, msg.MigrateToChatID)
+		return
+	}
+	if !msg.IsCommand() || !slices.Contains(groupCommandNames, msg.Command()) {
+		return
+	}
+	if !d.ensureUser(ctx, ctx.ChatID, msg.From) || !d.track.SyncTeam(ctx, msg.Chat.Title) {
+		return
+	}
+
+	switch msg.Command() {
+	case "join":
+		d.track.JoinTeam(ctx, displayName(msg.From))
+	case "leave":
+		d.track.LeaveGroupTeam(ctx, displayName(msg.From))
+	case "leaderboard":
+		d.track.ShowLeaderboard(ctx)
+	case "team_settings":
+		d.track.ShowTeamSettings(ctx)
+	case "help":
+		out := tgbotapi.NewMessage(ctx.ChatID, groupHelpText)
+		if _, err := d.bot.Send(out); err != nil {
+			ctx.Log.Error().Err(err).Msg("send group help failed")
+		}
+	}
+}
+
+// handleGroupCallback handles team buttons posted in groups.
+func (d *Dispatcher) handleGroupCallback(ctx *tgctx.MsgContext, q *tgbotapi.CallbackQuery) {
+	switch q.Data {
+	case teambtn.TeamCBJoin:
+		d.track.JoinTeam(ctx, displayName(q.From))
+	case teambtn.TeamCBSetDigest, teambtn.TeamCBSetDigestDay, teambtn.TeamCBSetDigestHour, teambtn.TeamCBSetLeaderboard:
+		d.track.CycleTeamSetting(ctx, q.Data)
+	}
+}
+
+// handleTeamCallback handles team privacy screens in private chat.
+func (d *Dispatcher) handleTeamCallback(ctx *tgctx.MsgContext, data string) {
+	switch {
+	case data == teambtn.TeamCBList:
+		d.track.ShowTeams(ctx, true)
+	case strings.HasPrefix(data, teambtn.TeamCBOpen):
+		if id, ok := parseCallbackID(data, teambtn.TeamCBOpen); ok {
+			d.track.ShowTeamPrivacy(ctx, id, true)
+		}
+	case strings.HasPrefix(data, teambtn.TeamCBSharing):
+		if id, ok := parseCallbackID(data, teambtn.TeamCBSharing); ok {
+			d.track.ToggleTeamSharing(ctx, id)
+		}
+	case strings.HasPrefix(data, teambtn.TeamCBActivity):
+		if teamID, activityID, ok := parseCallbackPair(data, teambtn.TeamCBActivity); ok {
+			d.track.ToggleTeamActivity(ctx, teamID, activityID)
+		}
+	case strings.HasPrefix(data, teambtn.TeamCBLeave):
+		if id, ok := parseCallbackID(data, teambtn.TeamCBLeave); ok {
+			d.track.LeaveTeam(ctx, id)
+		}
+	}
+}
+
+// handlePartnerCallback handles accountability partner screens.
+func (d *Dispatcher) handlePartnerCallback(ctx *tgctx.MsgContext, data string) {
+	switch {
+	case data == partnerbtn.PartnerCBList:
+		d.track.ShowPartners(ctx, true)
+	case data == partnerbtn.PartnerCBInvite:
+		d.track.CreatePartnerInvite(ctx)
+	case data == partnerbtn.PartnerCBDecline:
+		d.track.DeclinePartnerInvite(ctx)
+	case strings.HasPrefix(data, partnerbtn.PartnerCBAccept):
+		d.track.AcceptPartnerInvite(ctx, strings.TrimPrefix(data, partnerbtn.PartnerCBAccept))
+	case strings.HasPrefix(data, partnerbtn.PartnerCBOpen):
+		if id, ok := parseCallbackID(data, partnerbtn.PartnerCBOpen); ok {
+			d.track.ShowPartner(ctx, id, true)
+		}
+	case strings.HasPrefix(data, partnerbtn.PartnerCBSharing):
+		if id, ok := parseCallbackID(data, partnerbtn.PartnerCBSharing); ok {
+			d.track.ShowPartnerSharing(ctx, id, true)
+		}
+	case strings.HasPrefix(data, partnerbtn.PartnerCBActivity):
+		if id, activityID, ok := parseCallbackPair(data, partnerbtn.PartnerCBActivity); ok {
+			d.track.TogglePartnerActivity(ctx, id, activityID)
+		}
+	case strings.HasPrefix(data, partnerbtn.PartnerCBNudge):
+		if id, ok := parseCallbackID(data, partnerbtn.PartnerCBNudge); ok {
+			d.track.NudgePartner(ctx, id)
+		}
+	case strings.HasPrefix(data, partnerbtn.PartnerCBEnd):
+		if id, ok := parseCallbackID(data, partnerbtn.PartnerCBEnd); ok {
+			d.track.EndPartnership(ctx, id)
+		}
+	}
+}
+
+// handleOnboardingCallback handles first-run wizard buttons.
+func (d *Dispatcher) handleOnboardingCallback(ctx *tgctx.MsgContext, data string) {
+	delete(d.waitingOnbTimeZone, ctx.UserID)
+	delete(d.waitingOnbActivity, ctx.UserID)
+
+	switch {
+	case data == onboardingbtn.OnboardingCBTimeZoneOwn:
+		d.waitingOnbTimeZone[ctx.UserID] = true
+		d.track.PromptOnboardingTimeZone(ctx)
+	case data == onboardingbtn.OnboardingCBActivityOwn:
+		d.waitingOnbActivity[ctx.UserID] = true
+		d.track.PromptOnboardingActivity(ctx)
+	case data == onboardingbtn.OnboardingCBNext:
+		d.track.FinishOnboardingActivities(ctx)
+	case data == onboardingbtn.OnboardingCBQuietOff:
+		d.track.SetOnboardingQuiet(ctx, models.QuietHours{})
+	case data == onboardingbtn.OnboardingCBStartTimer:
+		d.track.CompleteOnboarding(ctx, true)
+	case data == onboardingbtn.OnboardingCBLater:
+		d.track.CompleteOnboarding(ctx, false)
+	case data == onboardingbtn.OnboardingCBSkip:
+		d.track.SkipOnboarding(ctx)
+	case strings.HasPrefix(data, onboardingbtn.OnboardingCBLanguage):
+		d.track.SetOnboardingLanguage(ctx, strings.TrimPrefix(data, onboardingbtn.OnboardingCBLanguage))
+	case strings.HasPrefix(data, onboardingbtn.OnboardingCBTimeZone):
+		d.track.SetOnboardingTimeZone(ctx, strings.TrimPrefix(data, onboardingbtn.OnboardingCBTimeZone), true)
+	case strings.HasPrefix(data, onboardingbtn.OnboardingCBActivity):
+		if i, err := strconv.Atoi(strings.TrimPrefix(data, onboardingbtn.OnboardingCBActivity)); err == nil {
+			d.track.AddOnboardingActivity(ctx, i)
+		}
+	case strings.HasPrefix(data, onboardingbtn.OnboardingCBInterval):
+		if interval, err := strconv.Atoi(strings.TrimPrefix(data, onboardingbtn.OnboardingCBInterval)); err == nil {
+			d.track.SetOnboardingInterval(ctx, interval)
+		}
+	case strings.HasPrefix(data, onboardingbtn.OnboardingCBQuiet):
+		if from, to, ok := parseCallbackPair(data, onboardingbtn.OnboardingCBQuiet); ok {
+			d.track.SetOnboardingQuiet(ctx, models.QuietHours{FromMin: int(from) * 60, ToMin: int(to) * 60})
+		}
+	}
+}
+
+// displayName is how user appears on team leaderboard.
+func displayName(u *tgbotapi.User) string {
+	if u.FirstName != "" {
+		return u.FirstName
+	}
+	return u.String()
+}
+
+// handleProfileCallback handles profile screen buttons; false for ones it does not own.
+func (d *Dispatcher) handleProfileCallback(ctx *tgctx.MsgContext, data string) bool {
+	switch {
+	case data == profilebtn.ProfileCBRefresh:
+		d.profile.ShowProfileMenu(ctx)
+	case data == profilebtn.ProfileCBShares:
+		d.profile.ShowShares(ctx, false)
+	case strings.HasPrefix(data, profilebtn.ProfileCBShareRevoke):
+		if id, ok := parseCallbackID(data, profilebtn.ProfileCBShareRevoke); ok {
+			d.profile.RevokeShare(ctx, id)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// handleAdminCommand routes operator commands; caller checks admin rights.
+func (d *Dispatcher) handleAdminCommand(cmd, args string, ctx *tgctx.MsgContext) bool {
+	switch cmd {
+	case "stats":
+		d.track.ShowAdminStats(ctx)
+	case "user":
+		d.track.ShowAdminUser(ctx, args)
+	case "timers":
+		d.track.ShowAdminTimers(ctx)
+	case "impersonate":
+		d.track.ShowImpersonatedReport(ctx, args)
+	case "broadcast":
+		delete(d.waitingBroadcastAt, ctx.UserID)
+		d.waitingBroadcast[ctx.UserID] = true
+		d.track.PromptBroadcastDraft(ctx)
+	case "broadcasts":
+		d.track.ShowBroadcasts(ctx)
+	default:
+		return false
+	}
+	return true
+}
+
+// handleBroadcastDraft passes composed announcement text and largest photo to handler.
+func (d *Dispatcher) handleBroadcastDraft(ctx *tgctx.MsgContext, msg *tgbotapi.Message) bool {
+	text, photoID := msg.Text, ""
+	if len(msg.Photo) > 0 {
+		text = msg.Caption
+		photoID = msg.Photo[len(msg.Photo)-1].FileID
+	}
+	return d.track.ProcessBroadcastDraft(ctx, text, photoID)
+}
+
+// handleAdminCallback handles broadcast control panel buttons.
+func (d *Dispatcher) handleAdminCallback(ctx *tgctx.MsgContext, data string) {
+	if !d.track.IsAdmin(ctx.UserID) {
+		return
+	}
+	for _, prefix := range []string{
+		adminbtn.AdminCBBroadcastLanguage,
+		adminbtn.AdminCBBroadcastPlan,
+		adminbtn.AdminCBBroadcastActive,
+	} {
+		if id, ok := parseCallbackID(data, prefix); ok {
+			d.track.CycleBroadcastSegment(ctx, id, prefix)
+			return
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(data, adminbtn.AdminCBBroadcastSendNow):
+		if id, ok := parseCallbackID(data, adminbtn.AdminCBBroadcastSendNow); ok {
+			d.track.SendBroadcastNow(ctx, id)
+		}
+	case strings.HasPrefix(data, adminbtn.AdminCBBroadcastSchedule):
+		if id, ok := parseCallbackID(data, adminbtn.AdminCBBroadcastSchedule); ok {
+			d.waitingBroadcastAt[ctx.UserID] = id
+			d.track.PromptBroadcastSchedule(ctx)
+		}
+	case strings.HasPrefix(data, adminbtn.AdminCBBroadcastCancel):
+		if id, ok := parseCallbackID(data, adminbtn.AdminCBBroadcastCancel); ok {
+			delete(d.waitingBroadcastAt, ctx.UserID)
+			d.track.CancelBroadcast(ctx, id)
+		}
+	}
+}
+
 // handleUserState handles temporary per-user states (FSM-like flow).
 func (d *Dispatcher) handleUserState(ctx *tgctx.MsgContext) bool {
+	if d.waitingOnbTimeZone[ctx.UserID] {
+		if d.isTrackButtonText(ctx.Text) {
+			delete(d.waitingOnbTimeZone, ctx.UserID)
+			return false
+		}
+		if d.track.SetOnboardingTimeZone(ctx, ctx.Text, false) {
+			delete(d.waitingOnbTimeZone, ctx.UserID)
+		}
+		return true
+	}
+	if d.waitingOnbActivity[ctx.UserID] {
+		if d.isTrackButtonText(ctx.Text) {
+			delete(d.waitingOnbActivity, ctx.UserID)
+			return false
+		}
+		if d.track.ProcessOnboardingActivity(ctx) {
+			delete(d.waitingOnbActivity, ctx.UserID)
+		}
+		return true
+	}
+	if id, ok := d.waitingBroadcastAt[ctx.UserID]; ok {
+		if d.isTrackButtonText(ctx.Text) {
+			delete(d.waitingBroadcastAt, ctx.UserID)
+			return false
+		}
+		if d.track.ProcessBroadcastSchedule(ctx, id) {
+			delete(d.waitingBroadcastAt, ctx.UserID)
+		}
+		return true
+	}
 	if d.waitingActivityName[ctx.UserID] {
 		if d.isTrackButtonText(ctx.Text) {
 			_, _ = d.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Use buttons from menu. Enter activity name as plain text."))
@@ -230,10 +748,70 @@ func (d *Dispatcher) handleUserState(ctx *tgctx.MsgContext) bool {
 		}
 		return true
 	}
+	if activityID, ok := d.waitingGoalActivity[ctx.UserID]; ok {
+		if d.isTrackButtonText(ctx.Text) {
+			delete(d.waitingGoalActivity, ctx.UserID)
+			return false
+		}
+		if d.track.ProcessGoalInput(ctx, activityID) {
+			delete(d.waitingGoalActivity, ctx.UserID)
+		}
+		return true
+	}
+	if activityID, ok := d.waitingRename[ctx.UserID]; ok {
+		if d.isTrackButtonText(ctx.Text) {
+			delete(d.waitingRename, ctx.UserID)
+			return false
+		}
+		if d.track.ProcessRenameActivity(ctx, activityID) {
+			delete(d.waitingRename, ctx.UserID)
+		}
+		return true
+	}
+	if activityID, ok := d.waitingEmoji[ctx.UserID]; ok {
+		if d.isTrackButtonText(ctx.Text) {
+			delete(d.waitingEmoji, ctx.UserID)
+			return false
+		}
+		if d.track.ProcessActivityEmoji(ctx, activityID) {
+			delete(d.waitingEmoji, ctx.UserID)
+		}
+		return true
+	}
+	if d.waitingTemplate[ctx.UserID] {
+		if d.isTrackButtonText(ctx.Text) {
+			delete(d.waitingTemplate, ctx.UserID)
+			return false
+		}
+		if d.track.ProcessImportTemplate(ctx) {
+			delete(d.waitingTemplate, ctx.UserID)
+		}
+		return true
+	}
+	if d.waitingTagName[ctx.UserID] {
+		if d.isTrackButtonText(ctx.Text) {
+			delete(d.waitingTagName, ctx.UserID)
+			return false
+		}
+		if d.track.ProcessCreateTag(ctx) {
+			delete(d.waitingTagName, ctx.UserID)
+		}
+		return true
+	}
+	if d.waitingDigestTime[ctx.UserID] {
+		if d.isTrackButtonText(ctx.Text) {
+			delete(d.waitingDigestTime, ctx.UserID)
+			return false
+		}
+		if d.track.ProcessDigestTimeInput(ctx) {
+			delete(d.waitingDigestTime, ctx.UserID)
+		}
+		return true
+	}
 	if d.waitingPeriodRange[ctx.UserID] {
-		from, to, err := parseDateRange(ctx.Text)
+		from, to, err := d.track.TimeParser(ctx).Range(ctx.Text)
 		if err != nil {
-			_, _ = d.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Use format: YYYY-MM-DD..YYYY-MM-DD"))
+			_, _ = d.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Examples: last week, this month, 3d, since monday, YYYY-MM-DD..YYYY-MM-DD"))
 			return true
 		}
 		d.reportFrom[ctx.UserID] = from
@@ -252,23 +830,74 @@ func (d *Dispatcher) handleUserState(ctx *tgctx.MsgContext) bool {
 func (d *Dispatcher) handleCommand(msg *tgbotapi.Message, ctx *tgctx.MsgContext) {
 	cmd := msg.Command()
 
+	if d.track.IsAdmin(ctx.UserID) && d.handleAdminCommand(cmd, msg.CommandArguments(), ctx) {
+		return
+	}
+
 	switch cmd {
 	case "start":
+		d.track.SetUserActive(ctx.Ctx, ctx.UserID, true)
 		d.userScreen[ctx.UserID] = screenHome
-		d.entry.ShowEntryMenu(ctx)
+		// Deep link t.me/<bot>?start=<payload> arrives as "/start <payload>".
+		payload := msg.CommandArguments()
+		if payload != "" {
+			d.track.RecordStartPayload(ctx, payload)
+		}
+		if !d.track.ResumeOnboarding(ctx, false) {
+			d.entry.ShowEntryMenu(ctx)
+		}
+		if token, ok := strings.CutPrefix(payload, service.PartnerInvitePrefix); ok {
+			d.track.ShowPartnerInvite(ctx, token)
+		}
+		return
+
+	case "log":
+		d.track.LogSessionCommand(ctx, msg.CommandArguments())
+		return
+
+	case "start_timer":
+		d.track.StartTimerCommand(ctx, msg.CommandArguments())
+		return
+
+	case "stop":
+		d.track.StopTrackTimer(ctx)
+		return
+
+	case "today":
+		d.track.ShowTodayReport(ctx)
+		return
+
+	case "report":
+		d.track.ReportCommand(ctx, msg.CommandArguments())
+		return
+
+	case "teams":
+		d.track.ShowTeams(ctx, false)
+		return
+
+	case "partners":
+		d.track.ShowPartners(ctx, false)
+		return
+
+	case "templates":
+		d.track.ShowTemplates(ctx, false)
+		return
+
+	case "pomodoro":
+		d.track.PomodoroCommand(ctx, strings.TrimSpace(msg.CommandArguments()))
 		return
 
 	case "help":
-		out := tgbotapi.NewMessage(ctx.ChatID, "Доступные команды: /start, /help")
+		out := tgbotapi.NewMessage(ctx.ChatID, helpText)
 		if _, err := d.bot.Send(out); err != nil {
-			log.Error().Err(err).Msg("send help failed")
+			ctx.Log.Error().Err(err).Msg("send help failed")
 		}
 		return
 
 	default:
 		out := tgbotapi.NewMessage(ctx.ChatID, "Неизвестная команда.")
 		if _, err := d.bot.Send(out); err != nil {
-			log.Error().Err(err).Msg("send unknown command failed")
+			ctx.Log.Error().Err(err).Msg("send unknown command failed")
 		}
 		return
 	}
@@ -323,6 +952,13 @@ func (d *Dispatcher) handleText(ctx *tgctx.MsgContext) {
 		d.ensurePeriodDefaults(ctx.UserID)
 		d.showPeriodMenu(ctx)
 		return
+	case trackbtn.TrackButtonDigests:
+		if !d.isScreen(ctx.UserID, screenTrackReports) {
+			d.replyUseButtons(ctx.ChatID)
+			return
+		}
+		d.track.ShowDigestSettings(ctx, false)
+		return
 	case trackbtn.TrackButtonSelectActivity:
 		d.setScreen(ctx.UserID, screenTrackManage)
 		d.track.ShowTrackActivitySelectionMenu(ctx)
@@ -343,6 +979,12 @@ func (d *Dispatcher) handleText(ctx *tgctx.MsgContext) {
 		d.track.ActivateTrackTimer(ctx, 30)
 		d.setScreen(ctx.UserID, screenHome)
 		return
+	case trackbtn.TrackButtonPomodoro:
+		d.track.ShowPomodoro(ctx, false)
+		return
+	case trackbtn.TrackButtonAdaptive:
+		d.track.ShowAdaptiveInterval(ctx, false)
+		return
 	case trackbtn.TrackButtonBackHome:
 		d.setScreen(ctx.UserID, screenHome)
 		d.entry.ShowEntryMenu(ctx)
@@ -351,7 +993,7 @@ func (d *Dispatcher) handleText(ctx *tgctx.MsgContext) {
 
 	out := tgbotapi.NewMessage(ctx.ChatID, "Я тебя понял, но не знаю что с этим сделать. Напиши /help")
 	if _, err := d.bot.Send(out); err != nil {
-		log.Error().Err(err).Msg("send fallback failed")
+		ctx.Log.Error().Err(err).Msg("send fallback failed")
 	}
 }
 
@@ -467,6 +1109,12 @@ func (d *Dispatcher) handleTrackCallback(ctx *tgctx.MsgContext, data string) {
 		d.setScreen(ctx.UserID, screenTrackReports)
 		ids := selectedIDs(d.getReportSelected(ctx.UserID))
 		d.track.ShowPeriodChartReport(ctx, d.reportFrom[ctx.UserID], d.reportTo[ctx.UserID], ids)
+	case data == trackbtn.TrackCBReportsPeriodShare:
+		ids := selectedIDs(d.getReportSelected(ctx.UserID))
+		d.track.ShareReportCard(ctx, d.reportFrom[ctx.UserID], d.reportTo[ctx.UserID], ids)
+	case data == trackbtn.TrackCBReportsPeriodShareLink:
+		ids := selectedIDs(d.getReportSelected(ctx.UserID))
+		d.track.CreateShareLink(ctx, d.reportFrom[ctx.UserID], d.reportTo[ctx.UserID], ids)
 	case data == trackbtn.TrackCBReportsBackHub:
 		d.setScreen(ctx.UserID, screenTrackReports)
 		d.track.ShowReportsHub(ctx, true)
@@ -497,6 +1145,144 @@ func (d *Dispatcher) handleTrackCallback(ctx *tgctx.MsgContext, data string) {
 	case data == trackbtn.TrackCBArchiveToActive:
 		d.setScreen(ctx.UserID, screenTrackManage)
 		d.track.ShowTrackActivitySelectionMenuInPlace(ctx)
+	case data == trackbtn.TrackCBGoalsOpen:
+		delete(d.waitingGoalActivity, ctx.UserID)
+		d.setScreen(ctx.UserID, screenTrackGoals)
+		d.track.ShowGoalsMenu(ctx, true)
+	case strings.HasPrefix(data, trackbtn.TrackCBGoalPick):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBGoalPick)
+		if !ok {
+			return
+		}
+		d.waitingGoalActivity[ctx.UserID] = id
+		d.setScreen(ctx.UserID, screenTrackGoals)
+		d.track.PromptGoalTarget(ctx, id)
+	case strings.HasPrefix(data, trackbtn.TrackCBGoalDelete):
+		d.track.DeleteGoal(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBGoalStreak):
+		d.track.ToggleGoalStreak(ctx)
+	case data == trackbtn.TrackCBDigestOpen:
+		d.setScreen(ctx.UserID, screenTrackReports)
+		d.track.ShowDigestSettings(ctx, true)
+	case strings.HasPrefix(data, trackbtn.TrackCBDigestToggle):
+		d.track.ToggleDigest(ctx)
+	case data == trackbtn.TrackCBDigestCustomTime:
+		d.waitingDigestTime[ctx.UserID] = true
+		d.track.PromptDigestTime(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBDigestTime):
+		d.track.SetDigestTime(ctx)
+	case data == trackbtn.TrackCBEditOpen:
+		delete(d.waitingRename, ctx.UserID)
+		delete(d.waitingEmoji, ctx.UserID)
+		d.setScreen(ctx.UserID, screenTrackManage)
+		d.track.ShowEditActivities(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBEditItem):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBEditItem)
+		if !ok {
+			return
+		}
+		d.track.ShowEditActivity(ctx, id)
+	case strings.HasPrefix(data, trackbtn.TrackCBEditMoveUp):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBEditMoveUp)
+		if !ok {
+			return
+		}
+		d.track.MoveActivity(ctx, id, -1)
+	case strings.HasPrefix(data, trackbtn.TrackCBEditMoveDown):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBEditMoveDown)
+		if !ok {
+			return
+		}
+		d.track.MoveActivity(ctx, id, 1)
+	case strings.HasPrefix(data, trackbtn.TrackCBEditRename):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBEditRename)
+		if !ok {
+			return
+		}
+		delete(d.waitingEmoji, ctx.UserID)
+		d.waitingRename[ctx.UserID] = id
+		d.track.PromptRenameActivity(ctx, id)
+	case strings.HasPrefix(data, trackbtn.TrackCBEditEmoji):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBEditEmoji)
+		if !ok {
+			return
+		}
+		delete(d.waitingRename, ctx.UserID)
+		d.waitingEmoji[ctx.UserID] = id
+		d.track.PromptActivityEmoji(ctx, id)
+	case strings.HasPrefix(data, trackbtn.TrackCBEditMerge):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBEditMerge)
+		if !ok {
+			return
+		}
+		d.track.ShowMergeTargets(ctx, id)
+	case strings.HasPrefix(data, trackbtn.TrackCBEditMergeTo):
+		fromID, toID, ok := parseCallbackPair(data, trackbtn.TrackCBEditMergeTo)
+		if !ok {
+			return
+		}
+		d.track.ShowMergeConfirm(ctx, fromID, toID)
+	case strings.HasPrefix(data, trackbtn.TrackCBEditMergeConfirm):
+		fromID, toID, ok := parseCallbackPair(data, trackbtn.TrackCBEditMergeConfirm)
+		if !ok {
+			return
+		}
+		delete(d.getReportSelected(ctx.UserID), fromID)
+		d.track.MergeActivities(ctx, fromID, toID)
+	case data == trackbtn.TrackCBTagsOpen:
+		delete(d.waitingTagName, ctx.UserID)
+		d.setScreen(ctx.UserID, screenTrackManage)
+		d.track.ShowTagsMenu(ctx, true)
+	case data == trackbtn.TrackCBTagCreate:
+		d.waitingTagName[ctx.UserID] = true
+		d.track.PromptCreateTag(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBTagOpen):
+		d.track.ShowTagMenu(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBTagAssign):
+		d.track.ToggleActivityTag(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBTagDelete):
+		d.track.DeleteTag(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBReportsPeriodTag):
+		d.setScreen(ctx.UserID, screenTrackReports)
+		id, ok := parseCallbackID(data, trackbtn.TrackCBReportsPeriodTag)
+		if !ok {
+			return
+		}
+		ids, ok := d.track.ResolveTagActivityIDs(ctx, id)
+		if !ok {
+			return
+		}
+		toggleSelectedGroup(d.getReportSelected(ctx.UserID), ids)
+		d.showPeriodMenu(ctx)
+	case data == trackbtn.TrackCBTemplatesOpen:
+		delete(d.waitingTemplate, ctx.UserID)
+		d.setScreen(ctx.UserID, screenTrackManage)
+		d.track.ShowTemplates(ctx, true)
+	case strings.HasPrefix(data, trackbtn.TrackCBTemplatePack):
+		d.track.ShowTemplatePack(ctx, strings.TrimPrefix(data, trackbtn.TrackCBTemplatePack))
+	case strings.HasPrefix(data, trackbtn.TrackCBTemplateAdd):
+		d.track.AddTemplatePack(ctx, strings.TrimPrefix(data, trackbtn.TrackCBTemplateAdd))
+	case data == trackbtn.TrackCBTemplateExport:
+		d.track.ExportTemplate(ctx)
+	case data == trackbtn.TrackCBTemplateImport:
+		d.waitingTemplate[ctx.UserID] = true
+		d.track.PromptImportTemplate(ctx)
+	case data == trackbtn.TrackCBPomodoroOpen:
+		d.track.ShowPomodoro(ctx, true)
+	case strings.HasPrefix(data, trackbtn.TrackCBPomodoroSetting):
+		d.track.CyclePomodoroSetting(ctx, strings.TrimPrefix(data, trackbtn.TrackCBPomodoroSetting))
+	case strings.HasPrefix(data, trackbtn.TrackCBPomodoroStart):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBPomodoroStart)
+		if !ok {
+			return
+		}
+		d.track.StartPomodoro(ctx, id)
+	case data == trackbtn.TrackCBPomodoroSkip, data == trackbtn.TrackCBPomodoroNext:
+		d.track.NextPomodoroFocus(ctx)
+	case data == trackbtn.TrackCBPomodoroStop:
+		d.track.StopPomodoro(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBAdaptiveSetting):
+		d.track.CycleAdaptiveSetting(ctx, strings.TrimPrefix(data, trackbtn.TrackCBAdaptiveSetting))
 	case data == trackbtn.TrackCBPromptStopTimer:
 		d.track.StopTrackTimer(ctx)
 	case strings.HasPrefix(data, trackbtn.TrackCBPromptActivity):
@@ -505,6 +1291,25 @@ func (d *Dispatcher) handleTrackCallback(ctx *tgctx.MsgContext, data string) {
 		d.track.RestoreArchivedActivity(ctx)
 	case strings.HasPrefix(data, trackbtn.TrackCBArchiveDelete):
 		d.track.DeleteArchivedForever(ctx)
+	case data == trackbtn.TrackCBTrashOpen:
+		d.setScreen(ctx.UserID, screenTrackArchive)
+		d.track.ShowTrashMenu(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBTrashRestore):
+		d.track.RestoreTrashedActivity(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBTrashUndo):
+		d.track.UndoDeleteActivities(ctx)
+	case strings.HasPrefix(data, trackbtn.TrackCBPurgeAsk):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBPurgeAsk)
+		if !ok {
+			return
+		}
+		d.track.ConfirmPurgeActivity(ctx, id)
+	case strings.HasPrefix(data, trackbtn.TrackCBPurgeConfirm):
+		id, ok := parseCallbackID(data, trackbtn.TrackCBPurgeConfirm)
+		if !ok {
+			return
+		}
+		d.track.PurgeActivity(ctx, id)
 	case strings.HasPrefix(data, "act_toggle_:"):
 		if !d.isScreen(ctx.UserID, screenTrackManage) {
 			d.closeInlineMenu(ctx, "Activities menu is closed. Open Activities again from Track.")
@@ -527,9 +1332,12 @@ func (d *Dispatcher) isTrackButtonText(text string) bool {
 		trackbtn.TrackButtonActivityDelete,
 		trackbtn.TrackButtonTimer15,
 		trackbtn.TrackButtonTimer30,
+		trackbtn.TrackButtonPomodoro,
+		trackbtn.TrackButtonAdaptive,
 		trackbtn.TrackButtonBackHome,
 		trackbtn.TrackButtonViewArchive,
-		trackbtn.TrackButtonPeriod:
+		trackbtn.TrackButtonPeriod,
+		trackbtn.TrackButtonDigests:
 		return true
 	default:
 		return false
@@ -590,26 +1398,6 @@ func (d *Dispatcher) getReportSelected(userID int64) map[int64]bool {
 	return d.reportSelected[userID]
 }
 
-// parseDateRange parses "YYYY-MM-DD..YYYY-MM-DD".
-func parseDateRange(s string) (time.Time, time.Time, error) {
-	parts := strings.Split(strings.TrimSpace(s), "..")
-	if len(parts) != 2 {
-		return time.Time{}, time.Time{}, fmt.Errorf("bad format")
-	}
-	from, err := time.Parse("2006-01-02", strings.TrimSpace(parts[0]))
-	if err != nil {
-		return time.Time{}, time.Time{}, err
-	}
-	to, err := time.Parse("2006-01-02", strings.TrimSpace(parts[1]))
-	if err != nil {
-		return time.Time{}, time.Time{}, err
-	}
-	if from.After(to) {
-		return time.Time{}, time.Time{}, fmt.Errorf("from after to")
-	}
-	return from, to, nil
-}
-
 // selectedIDs converts selected map to slice of ids.
 func selectedIDs(m map[int64]bool) []int64 {
 	out := make([]int64, 0, len(m))
@@ -629,6 +1417,24 @@ func toggleSelected(m map[int64]bool, id int64) {
 	}
 }
 
+// toggleSelectedGroup selects all ids, or unselects them when all are already selected.
+func toggleSelectedGroup(m map[int64]bool, ids []int64) {
+	allSelected := len(ids) > 0
+	for _, id := range ids {
+		if !m[id] {
+			allSelected = false
+			break
+		}
+	}
+	for _, id := range ids {
+		if allSelected {
+			delete(m, id)
+		} else {
+			m[id] = true
+		}
+	}
+}
+
 // parseCallbackID extracts int64 id from callback by prefix.
 func parseCallbackID(data, prefix string) (int64, bool) {
 	idRaw := strings.TrimPrefix(data, prefix)
@@ -639,6 +1445,20 @@ func parseCallbackID(data, prefix string) (int64, bool) {
 	return id, true
 }
 
+// parseCallbackPair extracts "a:b" int64 pair from callback by prefix.
+func parseCallbackPair(data, prefix string) (int64, int64, bool) {
+	parts := strings.Split(strings.TrimPrefix(data, prefix), ":")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	a, err1 := strconv.ParseInt(parts[0], 10, 64)
+	b, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return a, b, true
+}
+
 // sameDay checks whether two dates are the same day.
 func sameDay(a, b time.Time) bool {
 	ay, am, ad := a.Date()
@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tracker-bot/internal/buttons/admin"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/utils/tgclient"
+	"tracker-bot/internal/utils/tgctx"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// adminTimersLimit is how many nearest timers /timers lists.
+	adminTimersLimit = 10
+	// impersonateDays is report range of /impersonate.
+	impersonateDays = 7
+)
+
+// IsAdmin reports whether Telegram user may run operator commands.
+func (m *Module) IsAdmin(tgUserID int64) bool {
+	return m.adminIDs[tgUserID]
+}
+
+// ShowAdminStats sends totals across all users.
+func (m *Module) ShowAdminStats(ctx *tgctx.MsgContext) {
+	st, err := m.adminsvc.Stats(ctx.Ctx, time.Now())
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("admin stats failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load stats."))
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("🛠 Bot stats (UTC day)\n\n")
+	b.WriteString(fmt.Sprintf("Users: %d (inactive: %d)\n", st.Users, st.InactiveUsers))
+	b.WriteString(fmt.Sprintf("Tracked in last 24h: %d users\n", st.ActiveUsers24h))
+	b.WriteString(fmt.Sprintf("Active timers: %d\n", st.ActiveTimers))
+	b.WriteString(fmt.Sprintf("Sessions today: %d\n", st.SessionsToday))
+	b.WriteString(fmt.Sprintf("Tracked today: %s\n", formatReportDuration(st.TrackedToday)))
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, b.String()))
+}
+
+// ShowAdminUser sends operator view of user given by Telegram id in args.
+func (m *Module) ShowAdminUser(ctx *tgctx.MsgContext, args string) {
+	info, ok := m.loadAdminUser(ctx, args, "/user <tg_id>")
+	if !ok {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("👤 User %d\n\n", info.TgUserID))
+	b.WriteString(fmt.Sprintf("DB id: %d\n", info.DBUserID))
+	if info.UserName != "" {
+		b.WriteString(fmt.Sprintf("Username: @%s\n", info.UserName))
+	}
+	b.WriteString(fmt.Sprintf("Language: %s\nTimezone: %s\n", info.Language, info.TimeZone))
+	b.WriteString(fmt.Sprintf("Active: %t\n", info.IsActive))
+	b.WriteString(fmt.Sprintf("Registered: %s\n", info.CreatedAt.UTC().Format("2006-01-02 15:04")))
+	if info.TimerEnabled {
+		b.WriteString(fmt.Sprintf("Timer: every %d min, next %s\n", info.IntervalMin, formatTimeOrDash(info.NextPingAt)))
+	} else {
+		b.WriteString("Timer: off\n")
+	}
+	b.WriteString(fmt.Sprintf("Activities: %d\nSessions: %d\n", info.Activities, info.Sessions))
+	b.WriteString(fmt.Sprintf("Last session: %s\n", formatTimeOrDash(info.LastSessionAt)))
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, b.String()))
+}
+
+// ShowAdminTimers sends overdue timer backlog and nearest prompts.
+func (m *Module) ShowAdminTimers(ctx *tgctx.MsgContext) {
+	now := time.Now().UTC()
+	backlog, err := m.adminsvc.TimerBacklog(ctx.Ctx, now, adminTimersLimit)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("admin timers failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load timers."))
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("⏱ Timers\n\n")
+	b.WriteString(fmt.Sprintf("Enabled: %d\nOverdue: %d\n", backlog.Enabled, backlog.Overdue))
+	if backlog.Oldest != nil {
+		b.WriteString(fmt.Sprintf("Oldest overdue: %s ago\n", formatReportDuration(now.Sub(*backlog.Oldest))))
+	}
+	if len(backlog.Upcoming) > 0 {
+		b.WriteString("\nNearest:\n")
+		for _, item := range backlog.Upcoming {
+			b.WriteString(fmt.Sprintf("user %d - %s\n", item.DBUserID, item.NextPingAt.UTC().Format("2006-01-02 15:04:05")))
+		}
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, b.String()))
+}
+
+// ShowImpersonatedReport sends read-only today and last week reports of another user.
+func (m *Module) ShowImpersonatedReport(ctx *tgctx.MsgContext, args string) {
+	info, ok := m.loadAdminUser(ctx, args, "/impersonate <tg_id>")
+	if !ok {
+		return
+	}
+	ctx.Log.Info().Int64("target_user_id", info.DBUserID).Msg("admin views user reports")
+
+	today, err := m.tracksvc.GetTodayReport(ctx.Ctx, info.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("impersonated today report failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load today report."))
+		return
+	}
+	header := fmt.Sprintf("👁 Viewing as %d (read-only)\n\n", info.TgUserID)
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, header+todayReportText(today, "📅 Today")))
+
+	items, err := m.tracksvc.ListActivities(ctx.Ctx, info.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("impersonated activities failed")
+		return
+	}
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	to := time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	from := to.AddDate(0, 0, -impersonateDays)
+	stats, err := m.tracksvc.GetPeriodReport(ctx.Ctx, info.DBUserID, from, to, ids)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("impersonated period report failed")
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteString(fmt.Sprintf("📄 Last %d days · %s..%s\n\n", impersonateDays, from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02")))
+	b.WriteString(fmt.Sprintf("Total: %s\nSessions: %d\n", formatReportDuration(stats.TotalTracked), stats.TotalSessions))
+	for i, a := range stats.Activities {
+		b.WriteString(fmt.Sprintf("%d) %s - %s (%s, %d)\n", i+1, activityLabel(a.Emoji, a.Name), formatReportDuration(a.Duration), percentOf(a.Duration, stats.TotalTracked), a.Sessions))
+	}
+	appendGoalCompletionText(&b, stats.Goals)
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, b.String()))
+}
+
+// PromptBroadcastDraft explains how to compose announcement.
+func (m *Module) PromptBroadcastDraft(ctx *tgctx.MsgContext) {
+	text := "📣 Send the announcement: text, or photo with caption.\n" +
+		"Add link buttons as last lines in form:\nButton text | https://example.com"
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+}
+
+// ProcessBroadcastDraft stores draft, shows preview and control panel.
+// Returns false when input must be sent again.
+func (m *Module) ProcessBroadcastDraft(ctx *tgctx.MsgContext, text, photoFileID string) bool {
+	b, err := m.broadcastsvc.CreateDraft(ctx.Ctx, ctx.UserID, text, photoFileID)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidBroadcast) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ "+err.Error()+". Send the announcement again."))
+			return false
+		}
+		ctx.Log.Error().Err(err).Msg("create broadcast draft failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to save draft."))
+		return true
+	}
+
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Preview:"))
+	if _, err := m.bot.Send(broadcastMessage(b, ctx.ChatID)); err != nil {
+		ctx.Log.Warn().Err(err).Msg("broadcast preview failed")
+	}
+	m.renderBroadcastPanel(ctx, b, false)
+	return true
+}
+
+// CycleBroadcastSegment switches one segment field of draft to next value.
+func (m *Module) CycleBroadcastSegment(ctx *tgctx.MsgContext, id int64, field string) {
+	b, err := m.broadcastsvc.Get(ctx.Ctx, id)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get broadcast failed")
+		return
+	}
+	if b.Status != models.BroadcastStatusDraft {
+		m.renderBroadcastPanel(ctx, b, true)
+		return
+	}
+
+	seg := b.Segment
+	switch field {
+	case admin.AdminCBBroadcastLanguage:
+		seg.Language = nextOf(admin.BroadcastLanguages, seg.Language)
+	case admin.AdminCBBroadcastPlan:
+		seg.Plan = nextOf(admin.BroadcastPlans, seg.Plan)
+	case admin.AdminCBBroadcastActive:
+		seg.ActiveDays = nextOf(admin.BroadcastActiveDays, seg.ActiveDays)
+	}
+	if err := m.broadcastsvc.SetSegment(ctx.Ctx, id, seg); err != nil {
+		ctx.Log.Error().Err(err).Msg("set broadcast segment failed")
+		return
+	}
+	b.Segment = seg
+	m.renderBroadcastPanel(ctx, b, true)
+}
+
+// SendBroadcastNow queues draft for immediate delivery.
+func (m *Module) SendBroadcastNow(ctx *tgctx.MsgContext, id int64) {
+	m.scheduleBroadcast(ctx, id, time.Time{})
+}
+
+// PromptBroadcastSchedule asks for delivery time.
+func (m *Module) PromptBroadcastSchedule(ctx *tgctx.MsgContext) {
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Send delivery time in UTC: YYYY-MM-DD HH:MM"))
+}
+
+// ProcessBroadcastSchedule parses delivery time and schedules draft; false on invalid input.
+func (m *Module) ProcessBroadcastSchedule(ctx *tgctx.MsgContext, id int64) bool {
+	at, err := time.ParseInLocation("2006-01-02 15:04", strings.TrimSpace(ctx.Text), time.UTC)
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Use format YYYY-MM-DD HH:MM, e.g. 2026-09-01 09:00"))
+		return false
+	}
+	if at.Before(time.Now()) {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Time is in the past."))
+		return false
+	}
+	ctx.MessageID = 0
+	m.scheduleBroadcast(ctx, id, at)
+	return true
+}
+
+// CancelBroadcast cancels draft, scheduled or running broadcast.
+func (m *Module) CancelBroadcast(ctx *tgctx.MsgContext, id int64) {
+	if err := m.broadcastsvc.Cancel(ctx.Ctx, id); err != nil && !errors.Is(err, models.ErrBroadcastNotFound) {
+		ctx.Log.Error().Err(err).Msg("cancel broadcast failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to cancel broadcast."))
+		return
+	}
+	b, err := m.broadcastsvc.Get(ctx.Ctx, id)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get broadcast failed")
+		return
+	}
+	m.renderBroadcastPanel(ctx, b, true)
+}
+
+// ShowBroadcasts lists recent broadcasts with delivery counters.
+func (m *Module) ShowBroadcasts(ctx *tgctx.MsgContext) {
+	items, err := m.broadcastsvc.ListRecent(ctx.Ctx, 10)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list broadcasts failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load broadcasts."))
+		return
+	}
+	if len(items) == 0 {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "No broadcasts yet. Use /broadcast to create one."))
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("📣 Broadcasts\n\n")
+	for _, item := range items {
+		b.WriteString(fmt.Sprintf("#%d %s · %s", item.ID, item.Status, broadcastSegmentText(item.Segment)))
+		if item.ScheduledAt != nil {
+			b.WriteString(" · " + formatTimeOrDash(item.ScheduledAt))
+		}
+		b.WriteString("\n")
+		if item.Status == models.BroadcastStatusDraft {
+			continue
+		}
+		st, err := m.broadcastsvc.Stats(ctx.Ctx, item.ID)
+		if err != nil {
+			ctx.Log.Error().Err(err).Int64("broadcast_id", item.ID).Msg("broadcast stats failed")
+			continue
+		}
+		b.WriteString(fmt.Sprintf("   sent %d, pending %d, failed %d, blocked %d\n", st.Sent, st.Pending, st.Failed, st.Blocked))
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, b.String()))
+}
+
+// SendBroadcastMessage delivers announcement to one recipient through scheduled queue.
+func (m *Module) SendBroadcastMessage(ctx context.Context, b models.Broadcast, chatID int64) error {
+	_, err := m.bot.SendScheduled(broadcastMessage(b, chatID))
+	if tgclient.IsUnreachable(err) {
+		m.SetUserActive(ctx, chatID, false)
+	}
+	return err
+}
+
+// NotifyBroadcastFinished reports delivery totals to broadcast author.
+func (m *Module) NotifyBroadcastFinished(ctx context.Context, b models.Broadcast, st models.BroadcastStats) {
+	text := fmt.Sprintf("📣 Broadcast #%d finished\nSent: %d\nFailed: %d\nBlocked: %d", b.ID, st.Sent, st.Failed, st.Blocked)
+	if _, err := m.bot.Send(tgbotapi.NewMessage(b.CreatedBy, text)); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int64("broadcast_id", b.ID).Msg("broadcast report failed")
+	}
+}
+
+// scheduleBroadcast queues draft at time (zero means now) and re-renders panel.
+func (m *Module) scheduleBroadcast(ctx *tgctx.MsgContext, id int64, at time.Time) {
+	if err := m.broadcastsvc.Schedule(ctx.Ctx, id, at); err != nil && !errors.Is(err, models.ErrBroadcastNotFound) {
+		ctx.Log.Error().Err(err).Msg("schedule broadcast failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to schedule broadcast."))
+		return
+	}
+	b, err := m.broadcastsvc.Get(ctx.Ctx, id)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get broadcast failed")
+		return
+	}
+	ctx.Log.Info().Int64("broadcast_id", id).Str("status", b.Status).Msg("broadcast scheduled")
+	m.renderBroadcastPanel(ctx, b, ctx.MessageID > 0)
+}
+
+// renderBroadcastPanel shows broadcast state, segment and matching recipients.
+func (m *Module) renderBroadcastPanel(ctx *tgctx.MsgContext, b models.Broadcast, inPlace bool) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📣 Broadcast #%d · %s\n\n", b.ID, b.Status))
+	sb.WriteString("Segment: " + broadcastSegmentText(b.Segment) + "\n")
+
+	var markup tgbotapi.InlineKeyboardMarkup
+	switch b.Status {
+	case models.BroadcastStatusDraft:
+		n, err := m.broadcastsvc.CountRecipients(ctx.Ctx, b.Segment)
+		if err != nil {
+			ctx.Log.Error().Err(err).Msg("count broadcast recipients failed")
+		}
+		sb.WriteString(fmt.Sprintf("Recipients now: %d\n", n))
+		markup = admin.AdminBroadcastDraftInlineMenu(b)
+	case models.BroadcastStatusScheduled, models.BroadcastStatusSending:
+		sb.WriteString("Delivery: " + formatTimeOrDash(b.ScheduledAt) + "\n")
+		markup = admin.AdminBroadcastQueuedInlineMenu(b.ID)
+	default:
+		sb.WriteString("Use /broadcasts to see delivery status.\n")
+		markup = tgbotapi.NewInlineKeyboardMarkup()
+	}
+
+	if inPlace && ctx.MessageID > 0 {
+		_, _ = m.bot.Send(tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, sb.String(), markup))
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, sb.String())
+	if len(markup.InlineKeyboard) > 0 {
+		msg.ReplyMarkup = markup
+	}
+	_, _ = m.bot.Send(msg)
+}
+
+// broadcastMessage builds announcement as text or photo message with its link buttons.
+func broadcastMessage(b models.Broadcast, chatID int64) tgbotapi.Chattable {
+	markup := admin.BroadcastContentMarkup(b.Buttons)
+	if b.PhotoFileID != "" {
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(b.PhotoFileID))
+		photo.Caption = b.Text
+		if markup != nil {
+			photo.ReplyMarkup = *markup
+		}
+		return photo
+	}
+	msg := tgbotapi.NewMessage(chatID, b.Text)
+	if markup != nil {
+		msg.ReplyMarkup = *markup
+	}
+	return msg
+}
+
+func broadcastSegmentText(seg models.BroadcastSegment) string {
+	lang, plan := seg.Language, seg.Plan
+	if lang == "" {
+		lang = "all"
+	}
+	if plan == "" {
+		plan = "all"
+	}
+	return fmt.Sprintf("language %s, plan %s, active %s", lang, plan, admin.ActiveDaysLabel(seg.ActiveDays))
+}
+
+// nextOf returns value following cur in values, wrapping around.
+func nextOf[T comparable](values []T, cur T) T {
+	for i, v := range values {
+		if v == cur {
+			return values[(i+1)%len(values)]
+		}
+	}
+	return values[0]
+}
+
+// loadAdminUser parses Telegram id argument and loads user; replies with usage or error itself.
+func (m *Module) loadAdminUser(ctx *tgctx.MsgContext, args, usage string) (models.AdminUserInfo, bool) {
+	tgUserID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil || tgUserID <= 0 {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Usage: "+usage))
+		return models.AdminUserInfo{}, false
+	}
+	info, err := m.adminsvc.GetUserInfo(ctx.Ctx, tgUserID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "User not found."))
+			return models.AdminUserInfo{}, false
+		}
+		ctx.Log.Error().Err(err).Msg("admin user info failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load user."))
+		return models.AdminUserInfo{}, false
+	}
+	return info, true
+}
+
+// formatTimeOrDash formats optional UTC timestamp.
+func formatTimeOrDash(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04") + " UTC"
+}
+
+// activityLabel prefixes activity name with emoji when set.
+func activityLabel(emoji, name string) string {
+	if emoji == "" {
+		return name
+	}
+	return emoji + " " + name
+}
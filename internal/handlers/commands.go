@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/utils/tgctx"
+	"tracker-bot/pkg/cmdparse"
+	"tracker-bot/pkg/timeparse"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	logUsage        = "Usage: /log <activity> <45m|1h30m|14:00-15:30> [yesterday|friday|YYYY-MM-DD]"
+	reportUsage     = "Usage: /report [last week|this month|3d|since monday|YYYY-MM-DD..YYYY-MM-DD] [activity,activity]"
+	startTimerUsage = "Usage: /start_timer <minutes from 1 to 360>, e.g. /start_timer 20"
+	// maxTimerIntervalMin mirrors chk_interval_min_range.
+	maxTimerIntervalMin = 360
+)
+
+// LogSessionCommand records session from "/log Go 45m" or "/log English 14:00-15:30 yesterday".
+func (m *Module) LogSessionCommand(ctx *tgctx.MsgContext, args string) {
+	parsed, err := cmdparse.ParseLog(args, m.TimeParser(ctx))
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, logUsage))
+		return
+	}
+
+	item, ok := m.matchActivity(ctx, parsed.Activity)
+	if !ok {
+		return
+	}
+	if err := m.timersvc.RecordManualSession(ctx.Ctx, ctx.DBUserID, item.ID, parsed.Start, parsed.End); err != nil {
+		if errors.Is(err, models.ErrActivityNotFound) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity not found or archived."))
+			return
+		}
+		ctx.Log.Error().Err(err).Msg("record manual session failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to save session."))
+		return
+	}
+
+	text := fmt.Sprintf("✅ Logged %s to %s (%s–%s)",
+		formatReportDuration(parsed.End.Sub(parsed.Start)), activityLabel(item.Emoji, item.Name),
+		parsed.Start.Format("Jan 2 15:04"), parsed.End.Format("15:04"))
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+}
+
+// StartTimerCommand activates prompts from "/start_timer 20".
+func (m *Module) StartTimerCommand(ctx *tgctx.MsgContext, args string) {
+	intervalMin, err := strconv.Atoi(strings.TrimSpace(args))
+	if err != nil || intervalMin < 1 || intervalMin > maxTimerIntervalMin {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, startTimerUsage))
+		return
+	}
+	m.ActivateTrackTimer(ctx, intervalMin)
+}
+
+// ReportCommand sends period text report from "/report 2026-09-01..2026-09-30 Go,English".
+func (m *Module) ReportCommand(ctx *tgctx.MsgContext, args string) {
+	parsed, err := cmdparse.ParseReport(args, m.TimeParser(ctx))
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, reportUsage))
+		return
+	}
+
+	var ids []int64
+	for _, name := range parsed.Activities {
+		item, ok := m.matchActivity(ctx, name)
+		if !ok {
+			return
+		}
+		ids = append(ids, item.ID)
+	}
+	m.ShowPeriodTextReport(ctx, parsed.From, parsed.To, ids, len(ids) > 0)
+}
+
+// matchActivity resolves fuzzy activity name and explains failure to user.
+func (m *Module) matchActivity(ctx *tgctx.MsgContext, name string) (models.TrackActivityItem, bool) {
+	item, names, err := m.resolveActivity(ctx, name)
+	if err == nil {
+		return item, true
+	}
+	if !errors.Is(err, models.ErrActivityNotFound) {
+		ctx.Log.Error().Err(err).Msg("list activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load activities."))
+		return models.TrackActivityItem{}, false
+	}
+
+	text := fmt.Sprintf("Activity %q not found. Your activities: %s", name, strings.Join(names, ", "))
+	if len(names) == 0 {
+		text = "You have no activities yet. Create one in 📈Track."
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+	return models.TrackActivityItem{}, false
+}
+
+// resolveActivity finds activity by fuzzy name; on miss returns ErrActivityNotFound
+// with all activity names for hints.
+func (m *Module) resolveActivity(ctx *tgctx.MsgContext, name string) (models.TrackActivityItem, []string, error) {
+	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		return models.TrackActivityItem{}, nil, err
+	}
+
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	idx, ok := cmdparse.MatchName(name, names)
+	if !ok {
+		return models.TrackActivityItem{}, names, models.ErrActivityNotFound
+	}
+	return items[idx], names, nil
+}
+
+// TimeParser returns natural-language date parser in user's timezone and language;
+// UTC and English when profile is unavailable.
+func (m *Module) TimeParser(ctx *tgctx.MsgContext) timeparse.Parser {
+	loc, lang := time.UTC, "en"
+	stats, err := m.profilesvc.GetProfileStats(ctx.Ctx, ctx.UserID)
+	if err != nil || stats == nil {
+		return timeparse.New(time.Now().In(loc), lang)
+	}
+	if stats.TimeZone != nil {
+		if l, err := time.LoadLocation(*stats.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	if stats.Language != nil {
+		lang = *stats.Language
+	}
+	return timeparse.New(time.Now().In(loc), lang)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tracker-bot/internal/buttons/inline"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/utils/tgctx"
+	"tracker-bot/pkg/cmdparse"
+)
+
+// AnswerInlineQuery offers "Log 30m to Go" for "@bot go 30m" and today's summary for sharing.
+func (m *Module) AnswerInlineQuery(ctx *tgctx.MsgContext, queryID, query string) {
+	var results []any
+
+	if query = strings.TrimSpace(query); query != "" {
+		if res, ok := m.inlineLogResult(ctx, query); ok {
+			results = append(results, res)
+		} else {
+			results = append(results, inline.UsageResult("Usage: @bot <activity> <30m|1h30m|14:00-15:30>"))
+		}
+	}
+
+	stats, err := m.tracksvc.GetTodayReport(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("inline today report failed")
+	} else {
+		total := formatReportDuration(stats.TotalTracked)
+		results = append(results, inline.TodayResult(total, todayReportText(stats, "📊 My day")))
+	}
+
+	if _, err := m.bot.Request(inline.Answer(queryID, results...)); err != nil {
+		ctx.Log.Error().Err(err).Msg("answer inline query failed")
+	}
+}
+
+// RecordInlineResult stores session for chosen "log" inline result; other results are ignored.
+// Result id is the idempotency key, so choosing the same result again saves nothing.
+func (m *Module) RecordInlineResult(ctx *tgctx.MsgContext, resultID string) {
+	payload, ok := strings.CutPrefix(resultID, inline.InlineResultLog)
+	if !ok {
+		return
+	}
+	parts := strings.Split(payload, ":")
+	if len(parts) != 3 {
+		ctx.Log.Warn().Str("result_id", resultID).Msg("bad inline result id")
+		return
+	}
+	activityID, err1 := strconv.ParseInt(parts[0], 10, 64)
+	startUnix, err2 := strconv.ParseInt(parts[1], 10, 64)
+	endUnix, err3 := strconv.ParseInt(parts[2], 10, 64)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		ctx.Log.Warn().Err(err).Str("result_id", resultID).Msg("bad inline result id")
+		return
+	}
+
+	start, end := time.Unix(startUnix, 0), time.Unix(endUnix, 0)
+	saved, err := m.timersvc.RecordManualSessionOnce(ctx.Ctx, ctx.DBUserID, activityID, start, end, resultID)
+	if err != nil {
+		if errors.Is(err, models.ErrActivityNotFound) {
+			ctx.Log.Warn().Int64("activity_id", activityID).Msg("inline log for unknown activity")
+			return
+		}
+		ctx.Log.Error().Err(err).Msg("record inline session failed")
+		return
+	}
+	if !saved {
+		ctx.Log.Info().Str("result_id", resultID).Msg("inline result already recorded")
+	}
+}
+
+// inlineLogResult parses query like "go 30m" and builds logging result.
+func (m *Module) inlineLogResult(ctx *tgctx.MsgContext, query string) (any, bool) {
+	parsed, err := cmdparse.ParseLog(query, m.TimeParser(ctx))
+	if err != nil {
+		return nil, false
+	}
+	item, _, err := m.resolveActivity(ctx, parsed.Activity)
+	if err != nil {
+		if !errors.Is(err, models.ErrActivityNotFound) {
+			ctx.Log.Error().Err(err).Msg("inline activity lookup failed")
+		}
+		return nil, false
+	}
+
+	duration := formatReportDuration(parsed.End.Sub(parsed.Start))
+	name := activityLabel(item.Emoji, item.Name)
+	message := fmt.Sprintf("⏱ %s of %s", duration, name)
+	return inline.LogResult(item.ID, parsed.Start, parsed.End, duration, name, message), true
+}
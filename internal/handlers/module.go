@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -11,9 +12,12 @@ import (
 	"tracker-bot/internal/buttons/profile"
 	"tracker-bot/internal/buttons/subscription"
 	"tracker-bot/internal/buttons/track"
+	"tracker-bot/internal/metrics"
 	"tracker-bot/internal/models"
 	"tracker-bot/internal/service"
+	"tracker-bot/internal/utils/tgclient"
 	"tracker-bot/internal/utils/tgctx"
+	"tracker-bot/pkg/textbuilder"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog/log"
@@ -26,18 +30,33 @@ type Handler interface {
 }
 
 type Module struct {
-	bot             *tgbotapi.BotAPI
+	bot             tgclient.BotAPI
 	profilesvc      service.ProfileService
 	tracksvc        service.TrackerService
 	timersvc        service.TimerService
 	learningsvc     service.LearningService
 	subscriptionsvc service.SubscriptionService
 	entrysvc        service.EntryService
+	goalsvc         service.GoalService
+	digestsvc       service.DigestService
+	tagsvc          service.TagService
+	adminsvc        service.AdminService
+	broadcastsvc    service.BroadcastService
+	sharesvc        service.ShareService
+	teamsvc         service.TeamService
+	partnersvc      service.PartnerService
+	onboardingsvc   service.OnboardingService
+	pomodorosvc     service.PomodoroService
+	adminIDs        map[int64]bool
 	testTimerMin    int
 }
 
 // New creates handler module with all service dependencies.
-func New(bot *tgbotapi.BotAPI, entrysvc service.EntryService, profilesvc service.ProfileService, tracksvc service.TrackerService, timersvc service.TimerService, learningsvc service.LearningService, subscriptionsvc service.SubscriptionService, testTimerMin int) *Module {
+func New(bot tgclient.BotAPI, entrysvc service.EntryService, profilesvc service.ProfileService, tracksvc service.TrackerService, timersvc service.TimerService, learningsvc service.LearningService, subscriptionsvc service.SubscriptionService, goalsvc service.GoalService, digestsvc service.DigestService, tagsvc service.TagService, adminsvc service.AdminService, broadcastsvc service.BroadcastService, sharesvc service.ShareService, teamsvc service.TeamService, partnersvc service.PartnerService, onboardingsvc service.OnboardingService, pomodorosvc service.PomodoroService, adminIDs []int64, testTimerMin int) *Module {
+	admins := make(map[int64]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
 	return &Module{
 		bot:             bot,
 		profilesvc:      profilesvc,
@@ -46,6 +65,17 @@ func New(bot *tgbotapi.BotAPI, entrysvc service.EntryService, profilesvc service
 		learningsvc:     learningsvc,
 		subscriptionsvc: subscriptionsvc,
 		entrysvc:        entrysvc,
+		goalsvc:         goalsvc,
+		digestsvc:       digestsvc,
+		tagsvc:          tagsvc,
+		adminsvc:        adminsvc,
+		broadcastsvc:    broadcastsvc,
+		sharesvc:        sharesvc,
+		teamsvc:         teamsvc,
+		partnersvc:      partnersvc,
+		onboardingsvc:   onboardingsvc,
+		pomodorosvc:     pomodorosvc,
+		adminIDs:        admins,
 		testTimerMin:    testTimerMin,
 	}
 }
@@ -59,7 +89,7 @@ func (m *Module) ShowEntryMenu(ctx *tgctx.MsgContext) {
 	msg.ReplyMarkup = entry.EntryReplyMenu()
 
 	if _, err := m.bot.Send(msg); err != nil {
-		log.Error().Err(err).Msg("send entry menu failed")
+		ctx.Log.Error().Err(err).Msg("send entry menu failed")
 	}
 }
 
@@ -67,7 +97,7 @@ func (m *Module) ShowEntryMenu(ctx *tgctx.MsgContext) {
 func (m *Module) ShowProfileMenu(ctx *tgctx.MsgContext) {
 	stats, err := m.profilesvc.GetProfileStats(ctx.Ctx, ctx.UserID)
 	if err != nil {
-		log.Error().Err(err).Msg("GetProfile failed")
+		ctx.Log.Error().Err(err).Msg("GetProfile failed")
 		msg := tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load profile data. Please try again.")
 		_, _ = m.bot.Send(msg)
 		return
@@ -79,7 +109,7 @@ func (m *Module) ShowProfileMenu(ctx *tgctx.MsgContext) {
 	msg.ReplyMarkup = profile.ProfileEntryInlineMenu()
 
 	if _, err := m.bot.Send(msg); err != nil {
-		log.Error().Err(err).Msg("send profile menu failed")
+		ctx.Log.Error().Err(err).Msg("send profile menu failed")
 	}
 }
 
@@ -87,7 +117,7 @@ func (m *Module) ShowProfileMenu(ctx *tgctx.MsgContext) {
 func (m *Module) ShowTrackingMenu(ctx *tgctx.MsgContext) {
 	stats, err := m.tracksvc.GetMainStats(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("GetMainStats failed")
+		ctx.Log.Error().Err(err).Msg("GetMainStats failed")
 		msg := tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load tracking data. Please try again.")
 		_, _ = m.bot.Send(msg)
 		return
@@ -100,7 +130,7 @@ func (m *Module) ShowTrackingMenu(ctx *tgctx.MsgContext) {
 	msg.ReplyMarkup = track.TrackEntryInlineMenu()
 
 	if _, err := m.bot.Send(msg); err != nil {
-		log.Error().Err(err).Msg("send tracking menu failed")
+		ctx.Log.Error().Err(err).Msg("send tracking menu failed")
 	}
 }
 
@@ -123,7 +153,7 @@ func (m *Module) ShowReportsHub(ctx *tgctx.MsgContext, inPlace bool) {
 func (m *Module) ShowTodayChart(ctx *tgctx.MsgContext) {
 	stats, err := m.tracksvc.GetTodayReport(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("today chart failed")
+		ctx.Log.Error().Err(err).Msg("today chart failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load chart data."))
 		return
 	}
@@ -157,6 +187,7 @@ func (m *Module) ShowTodayChart(ctx *tgctx.MsgContext) {
 		percent := percentOf(a.Duration, total)
 		b.WriteString(fmt.Sprintf("%s\n%s %s (%s)\n\n", name, strings.Repeat("█", barLen), formatReportDuration(a.Duration), percent))
 	}
+	appendGoalProgressText(&b, stats.Goals)
 
 	msg := tgbotapi.NewMessage(ctx.ChatID, b.String())
 	msg.ReplyMarkup = track.TrackReportTodayInlineMenu()
@@ -170,6 +201,10 @@ func (m *Module) ShowPeriodMenu(ctx *tgctx.MsgContext, selected map[int64]bool,
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load activities for period."))
 		return
 	}
+	tags, err := m.tagsvc.ListTags(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list tags failed")
+	}
 	if month.IsZero() {
 		month = time.Now().UTC()
 	}
@@ -180,19 +215,19 @@ func (m *Module) ShowPeriodMenu(ctx *tgctx.MsgContext, selected map[int64]bool,
 			ctx.ChatID,
 			ctx.MessageID,
 			text,
-			track.TrackReportPeriodInlineMenu(items, selected, rangeLabel),
+			track.TrackReportPeriodInlineMenu(items, tags, selected, rangeLabel),
 		)
 		_, _ = m.bot.Send(edit)
 		return
 	}
 	msg := tgbotapi.NewMessage(ctx.ChatID, text)
-	msg.ReplyMarkup = track.TrackReportPeriodInlineMenu(items, selected, rangeLabel)
+	msg.ReplyMarkup = track.TrackReportPeriodInlineMenu(items, tags, selected, rangeLabel)
 	_, _ = m.bot.Send(msg)
 }
 
 // ShowPeriodTextReport builds and sends period report in text form.
 func (m *Module) ShowPeriodTextReport(ctx *tgctx.MsgContext, from, to time.Time, activityIDs []int64, selectedOnly bool) {
-	stats, err := m.tracksvc.GetPeriodReport(ctx.Ctx, ctx.DBUserID, from, to.Add(24*time.Hour), activityIDs)
+	stats, err := m.tracksvc.GetPeriodReport(ctx.Ctx, ctx.DBUserID, from, to.AddDate(0, 0, 1), activityIDs)
 	if err != nil {
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to build period report."))
 		return
@@ -218,13 +253,16 @@ func (m *Module) ShowPeriodTextReport(ctx *tgctx.MsgContext, from, to time.Time,
 			b.WriteString(fmt.Sprintf("%d) %s - %s (%s, %d)\n", i+1, name, formatReportDuration(a.Duration), percentOf(a.Duration, total), a.Sessions))
 		}
 	}
+	appendTagText(&b, stats.Tags, total)
+	appendPomodoroText(&b, stats.Pomodoros)
 	m.appendGranularityText(ctx, &b, from, to, activityIDs)
+	appendGoalCompletionText(&b, stats.Goals)
 	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, b.String()))
 }
 
 // ShowPeriodChartReport builds and sends period report in chart-like form.
 func (m *Module) ShowPeriodChartReport(ctx *tgctx.MsgContext, from, to time.Time, activityIDs []int64) {
-	stats, err := m.tracksvc.GetPeriodReport(ctx.Ctx, ctx.DBUserID, from, to.Add(24*time.Hour), activityIDs)
+	stats, err := m.tracksvc.GetPeriodReport(ctx.Ctx, ctx.DBUserID, from, to.AddDate(0, 0, 1), activityIDs)
 	if err != nil {
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to build period chart."))
 		return
@@ -254,7 +292,9 @@ func (m *Module) ShowPeriodChartReport(ctx *tgctx.MsgContext, from, to time.Time
 		}
 		b.WriteString(fmt.Sprintf("%s\n%s %s (%s, %d)\n\n", name, strings.Repeat("█", barLen), formatReportDuration(a.Duration), percentOf(a.Duration, total), a.Sessions))
 	}
+	appendTagText(&b, stats.Tags, total)
 	m.appendGranularityText(ctx, &b, from, to, activityIDs)
+	appendGoalCompletionText(&b, stats.Goals)
 	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, b.String()))
 }
 
@@ -286,7 +326,7 @@ func (m *Module) appendGranularityText(ctx *tgctx.MsgContext, b *strings.Builder
 		labelFmt = "15:00"
 	}
 
-	buckets, durs, err := m.tracksvc.GetPeriodBuckets(ctx.Ctx, ctx.DBUserID, from, to.Add(24*time.Hour), activityIDs, granularity)
+	buckets, durs, err := m.tracksvc.GetPeriodBuckets(ctx.Ctx, ctx.DBUserID, from, to.AddDate(0, 0, 1), activityIDs, granularity)
 	if err != nil || len(buckets) == 0 {
 		return
 	}
@@ -305,6 +345,58 @@ func (m *Module) appendGranularityText(ctx *tgctx.MsgContext, b *strings.Builder
 	}
 }
 
+// appendTagText appends totals grouped by tag to period report.
+func appendTagText(b *strings.Builder, tags []models.TagDurationStat, total time.Duration) {
+	if len(tags) == 0 {
+		return
+	}
+	b.WriteString("\nBy tags:\n")
+	for _, t := range tags {
+		b.WriteString(fmt.Sprintf("- %s: %s (%s)\n", t.Path, formatReportDuration(t.Duration), percentOf(t.Duration, total)))
+	}
+}
+
+// appendPomodoroText appends completed pomodoros per day to period report.
+func appendPomodoroText(b *strings.Builder, days []models.DayCountStat) {
+	if len(days) == 0 {
+		return
+	}
+	total := 0
+	for _, d := range days {
+		total += d.Count
+	}
+	b.WriteString(fmt.Sprintf("\n🍅 Pomodoros: %d\n", total))
+	for _, d := range days {
+		b.WriteString(fmt.Sprintf("- %s: %d\n", d.Day.Format("2006-01-02"), d.Count))
+	}
+}
+
+// appendGoalProgressText appends current goal progress lines to report.
+func appendGoalProgressText(b *strings.Builder, goals []models.GoalProgress) {
+	if len(goals) == 0 {
+		return
+	}
+	b.WriteString("\nGoals:\n")
+	for _, g := range goals {
+		name := g.Goal.ActivityName
+		if g.Goal.Emoji != "" {
+			name = g.Goal.Emoji + " " + name
+		}
+		b.WriteString(fmt.Sprintf("- %s (%s)\n  %s\n", name, g.Goal.Period, track.GoalProgressText(g)))
+	}
+}
+
+// appendGoalCompletionText appends goal completion summary to period report.
+func appendGoalCompletionText(b *strings.Builder, goals []models.GoalCompletion) {
+	if len(goals) == 0 {
+		return
+	}
+	b.WriteString("\nGoals:\n")
+	for _, g := range goals {
+		b.WriteString("- " + track.GoalCompletionText(g) + "\n")
+	}
+}
+
 // ShowTodayReport is an alias to chart-style today report.
 func (m *Module) ShowTodayReport(ctx *tgctx.MsgContext) {
 	m.ShowTodayChart(ctx)
@@ -340,7 +432,7 @@ func (m *Module) ShowTodaySelectActivities(ctx *tgctx.MsgContext, selected map[i
 
 func (m *Module) renderTodayReport(ctx *tgctx.MsgContext, stats models.ReportTodayStats, err error, title string) {
 	if err != nil {
-		log.Error().Err(err).Msg("today report failed")
+		ctx.Log.Error().Err(err).Msg("today report failed")
 		if ctx.MessageID > 0 {
 			edit := tgbotapi.NewEditMessageText(ctx.ChatID, ctx.MessageID, "⚠️ Failed to load today report.")
 			_, _ = m.bot.Send(edit)
@@ -350,10 +442,32 @@ func (m *Module) renderTodayReport(ctx *tgctx.MsgContext, stats models.ReportTod
 		return
 	}
 
+	text := todayReportText(stats, title)
+	if ctx.MessageID > 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(
+			ctx.ChatID,
+			ctx.MessageID,
+			text,
+			track.TrackReportTodayInlineMenu(),
+		)
+		_, _ = m.bot.Send(edit)
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = track.TrackReportTodayInlineMenu()
+	_, _ = m.bot.Send(msg)
+}
+
+// todayReportText formats today report body.
+func todayReportText(stats models.ReportTodayStats, title string) string {
 	var b strings.Builder
 	b.WriteString(title + "\n\n")
 	b.WriteString(fmt.Sprintf("Total: %s\n", formatReportDuration(stats.TotalTracked)))
-	b.WriteString(fmt.Sprintf("Sessions: %d\n\n", stats.TotalSessions))
+	b.WriteString(fmt.Sprintf("Sessions: %d\n", stats.TotalSessions))
+	if stats.Pomodoros > 0 {
+		b.WriteString(fmt.Sprintf("🍅 Pomodoros: %d\n", stats.Pomodoros))
+	}
+	b.WriteString("\n")
 	if len(stats.TopActivities) == 0 {
 		b.WriteString("Top activities: none yet")
 	} else {
@@ -366,20 +480,8 @@ func (m *Module) renderTodayReport(ctx *tgctx.MsgContext, stats models.ReportTod
 			b.WriteString(fmt.Sprintf("%d) %s - %s (%d)\n", i+1, name, formatReportDuration(item.Duration), item.Sessions))
 		}
 	}
-
-	if ctx.MessageID > 0 {
-		edit := tgbotapi.NewEditMessageTextAndMarkup(
-			ctx.ChatID,
-			ctx.MessageID,
-			b.String(),
-			track.TrackReportTodayInlineMenu(),
-		)
-		_, _ = m.bot.Send(edit)
-		return
-	}
-	msg := tgbotapi.NewMessage(ctx.ChatID, b.String())
-	msg.ReplyMarkup = track.TrackReportTodayInlineMenu()
-	_, _ = m.bot.Send(msg)
+	appendGoalProgressText(&b, stats.Goals)
+	return b.String()
 }
 
 // PromptCreateActivity asks user to type a new activity name.
@@ -390,7 +492,7 @@ func (m *Module) PromptCreateActivity(ctx *tgctx.MsgContext) {
 	msg.ReplyMarkup = track.TrackActivityManageReplyMenu()
 
 	if _, err := m.bot.Send(msg); err != nil {
-		log.Error().Err(err).Msg("send create activity prompt failed")
+		ctx.Log.Error().Err(err).Msg("send create activity prompt failed")
 	}
 }
 
@@ -409,7 +511,7 @@ func (m *Module) ProcessCreateActivity(ctx *tgctx.MsgContext) bool {
 			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity already exists."))
 			return false
 		}
-		log.Error().Err(err).Msg("create activity failed")
+		ctx.Log.Error().Err(err).Msg("create activity failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to create activity."))
 		return false
 	}
@@ -424,7 +526,7 @@ func (m *Module) ProcessCreateActivity(ctx *tgctx.MsgContext) bool {
 func (m *Module) ShowTrackActivitySelectionMenu(ctx *tgctx.MsgContext) {
 	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("list activities failed")
+		ctx.Log.Error().Err(err).Msg("list activities failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load activities."))
 		return
 	}
@@ -458,14 +560,14 @@ func (m *Module) HandleTrackToggleCallback(ctx *tgctx.MsgContext) {
 	}
 
 	if err := m.tracksvc.ToggleSelectedActivity(ctx.Ctx, ctx.DBUserID, activityID); err != nil {
-		log.Error().Err(err).Msg("toggle activity failed")
+		ctx.Log.Error().Err(err).Msg("toggle activity failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to update activity selection."))
 		return
 	}
 
 	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("reload activities failed")
+		ctx.Log.Error().Err(err).Msg("reload activities failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to refresh activities."))
 		return
 	}
@@ -480,28 +582,145 @@ func (m *Module) HandleTrackToggleCallback(ctx *tgctx.MsgContext) {
 	)
 	edit.ParseMode = "HTML"
 	if _, err := m.bot.Send(edit); err != nil {
-		log.Error().Err(err).Msg("edit activity list failed")
+		ctx.Log.Error().Err(err).Msg("edit activity list failed")
 	}
 }
 
-// DeleteSelectedActivities removes all currently selected activities.
+// DeleteSelectedActivities moves selected activities to trash and offers a timed undo.
 func (m *Module) DeleteSelectedActivities(ctx *tgctx.MsgContext) {
-	deleted, err := m.tracksvc.DeleteSelectedActivities(ctx.Ctx, ctx.DBUserID)
+	deleted, deletedAt, err := m.tracksvc.DeleteSelectedActivities(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("delete selected activities failed")
+		ctx.Log.Error().Err(err).Msg("delete selected activities failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to delete selected activities."))
 		return
 	}
+	if deleted == 0 {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Nothing selected to delete."))
+		return
+	}
 
-	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("🗑 Deleted: %d", deleted)))
+	msg := tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf(track.TrackMsgDeletedUndo, deleted, int(service.DeleteUndoWindow.Seconds())))
+	msg.ReplyMarkup = track.TrackUndoDeleteInlineMenu(deletedAt)
+	sent, err := m.bot.Send(msg)
+	if err == nil {
+		// Drop the button once undo expires; UndoDeleteActivities also checks the window.
+		chatID := ctx.ChatID
+		time.AfterFunc(service.DeleteUndoWindow, func() {
+			edit := tgbotapi.NewEditMessageReplyMarkup(chatID, sent.MessageID, tgbotapi.NewInlineKeyboardMarkup())
+			_, _ = m.bot.Send(edit)
+		})
+	}
+	m.ShowTrackActivitySelectionMenu(ctx)
+}
+
+// UndoDeleteActivities restores activities from callback payload with deletion time.
+func (m *Module) UndoDeleteActivities(ctx *tgctx.MsgContext) {
+	micros, err := strconv.ParseInt(strings.TrimPrefix(ctx.Text, track.TrackCBTrashUndo), 10, 64)
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Invalid undo payload."))
+		return
+	}
+	restored, err := m.tracksvc.UndoDeleteActivities(ctx.Ctx, ctx.DBUserID, time.UnixMicro(micros))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUndoExpired):
+			edit := tgbotapi.NewEditMessageText(ctx.ChatID, ctx.MessageID, "Undo expired — restore from 🗑 Trash in Archive.")
+			_, _ = m.bot.Send(edit)
+		case errors.Is(err, models.ErrActivityExists):
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "An activity with the same name already exists. Rename it, then restore from 🗑 Trash."))
+		default:
+			ctx.Log.Error().Err(err).Msg("undo delete failed")
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to undo deletion."))
+		}
+		return
+	}
+	edit := tgbotapi.NewEditMessageText(ctx.ChatID, ctx.MessageID, fmt.Sprintf("↩️ Restored: %d", restored))
+	_, _ = m.bot.Send(edit)
 	m.ShowTrackActivitySelectionMenu(ctx)
 }
 
+// ShowTrashMenu renders soft-deleted activities in place.
+func (m *Module) ShowTrashMenu(ctx *tgctx.MsgContext) {
+	items, err := m.tracksvc.ListTrashedActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list trashed activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load trash."))
+		return
+	}
+	text := track.TrackMsgTrashTitle + "\n\n" + track.TrackMsgTrashHint
+	if len(items) == 0 {
+		text = track.TrackMsgTrashTitle + "\n\n" + track.TrackMsgTrashEmpty
+	}
+	markup := track.TrackTrashInlineMenu(items)
+	if ctx.MessageID > 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, markup)
+		_, _ = m.bot.Send(edit)
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = markup
+	_, _ = m.bot.Send(msg)
+}
+
+// RestoreTrashedActivity moves one activity from trash back to active list.
+func (m *Module) RestoreTrashedActivity(ctx *tgctx.MsgContext) {
+	activityID, err := strconv.ParseInt(strings.TrimPrefix(ctx.Text, track.TrackCBTrashRestore), 10, 64)
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Invalid activity."))
+		return
+	}
+	activityName := m.findInactiveActivityName(ctx, activityID)
+	if err := m.tracksvc.RestoreTrashedActivity(ctx.Ctx, ctx.DBUserID, activityID); err != nil {
+		if errors.Is(err, models.ErrActivityExists) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "An activity with the same name already exists. Rename it first."))
+			return
+		}
+		ctx.Log.Error().Err(err).Msg("restore trashed activity failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to restore activity."))
+		return
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("♻ Activity restored: %s", activityName)))
+	m.ShowTrashMenu(ctx)
+}
+
+// ConfirmPurgeActivity asks to delete archived or trashed activity with all history.
+func (m *Module) ConfirmPurgeActivity(ctx *tgctx.MsgContext, activityID int64) {
+	cancelCB := track.TrackCBTrashOpen
+	if m.isArchivedActivity(ctx, activityID) {
+		cancelCB = track.TrackCBOpenArchive
+	}
+	text := fmt.Sprintf(track.TrackMsgPurgeConfirm, m.findInactiveActivityName(ctx, activityID))
+	edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, track.TrackPurgeConfirmInlineMenu(activityID, cancelCB))
+	_, _ = m.bot.Send(edit)
+}
+
+// PurgeActivity deletes activity and its sessions after confirmation.
+func (m *Module) PurgeActivity(ctx *tgctx.MsgContext, activityID int64) {
+	activityName := m.findInactiveActivityName(ctx, activityID)
+	fromArchive := m.isArchivedActivity(ctx, activityID)
+	sessions, err := m.tracksvc.PurgeActivity(ctx.Ctx, ctx.DBUserID, activityID)
+	if err != nil {
+		if errors.Is(err, models.ErrActivityNotFound) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity not found."))
+			return
+		}
+		ctx.Log.Error().Err(err).Msg("purge activity failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to delete activity with history."))
+		return
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("🔥 Deleted with history: %s (%d sessions)", activityName, sessions)))
+	if fromArchive {
+		m.ShowArchiveMenuInPlace(ctx)
+		return
+	}
+	m.ShowTrashMenu(ctx)
+}
+
 // ArchiveSelectedActivities moves selected activities to archive.
 func (m *Module) ArchiveSelectedActivities(ctx *tgctx.MsgContext) {
 	archived, err := m.tracksvc.ArchiveSelectedActivities(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("archive selected activities failed")
+		ctx.Log.Error().Err(err).Msg("archive selected activities failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to archive selected activities."))
 		return
 	}
@@ -522,7 +741,7 @@ func (m *Module) ArchiveSelectedActivities(ctx *tgctx.MsgContext) {
 func (m *Module) ArchiveSelectedActivitiesInPlace(ctx *tgctx.MsgContext) {
 	archived, err := m.tracksvc.ArchiveSelectedActivities(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("archive selected activities failed")
+		ctx.Log.Error().Err(err).Msg("archive selected activities failed")
 		edit := tgbotapi.NewEditMessageText(ctx.ChatID, ctx.MessageID, "⚠️ Failed to archive selected activities.")
 		_, _ = m.bot.Send(edit)
 		return
@@ -562,7 +781,7 @@ func (m *Module) ShowArchiveMenuInPlace(ctx *tgctx.MsgContext) {
 func (m *Module) renderArchiveMenu(ctx *tgctx.MsgContext, edit bool) {
 	items, err := m.tracksvc.ListArchivedActivities(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("list archive failed")
+		ctx.Log.Error().Err(err).Msg("list archive failed")
 		if edit && ctx.MessageID > 0 {
 			msg := tgbotapi.NewEditMessageText(ctx.ChatID, ctx.MessageID, "⚠️ Failed to load archive.")
 			_, _ = m.bot.Send(msg)
@@ -615,7 +834,7 @@ func (m *Module) ShowTrackActivitySelectionMenuInPlace(ctx *tgctx.MsgContext) {
 
 	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("list activities failed")
+		ctx.Log.Error().Err(err).Msg("list activities failed")
 		edit := tgbotapi.NewEditMessageText(ctx.ChatID, ctx.MessageID, "⚠️ Failed to load activities.")
 		_, _ = m.bot.Send(edit)
 		return
@@ -650,7 +869,7 @@ func (m *Module) RestoreArchivedActivity(ctx *tgctx.MsgContext) {
 	activityName := m.findArchivedActivityName(ctx, activityID)
 
 	if err := m.tracksvc.RestoreArchivedActivity(ctx.Ctx, ctx.DBUserID, activityID); err != nil {
-		log.Error().Err(err).Msg("restore archived activity failed")
+		ctx.Log.Error().Err(err).Msg("restore archived activity failed")
 		edit := tgbotapi.NewEditMessageText(ctx.ChatID, ctx.MessageID, "⚠️ Failed to restore activity.")
 		_, _ = m.bot.Send(edit)
 		return
@@ -659,7 +878,8 @@ func (m *Module) RestoreArchivedActivity(ctx *tgctx.MsgContext) {
 	m.ShowArchiveMenuInPlace(ctx)
 }
 
-// DeleteArchivedForever permanently removes one archived activity.
+// DeleteArchivedForever permanently removes one archived activity without history.
+// Activities with sessions are offered deletion with all history instead.
 func (m *Module) DeleteArchivedForever(ctx *tgctx.MsgContext) {
 	idRaw := strings.TrimPrefix(ctx.Text, track.TrackCBArchiveDelete)
 	activityID, err := strconv.ParseInt(idRaw, 10, 64)
@@ -670,7 +890,11 @@ func (m *Module) DeleteArchivedForever(ctx *tgctx.MsgContext) {
 	activityName := m.findArchivedActivityName(ctx, activityID)
 
 	if err := m.tracksvc.DeleteArchivedForever(ctx.Ctx, ctx.DBUserID, activityID); err != nil {
-		log.Error().Err(err).Msg("delete archived forever failed")
+		if errors.Is(err, models.ErrActivityHasHistory) {
+			m.ConfirmPurgeActivity(ctx, activityID)
+			return
+		}
+		ctx.Log.Error().Err(err).Msg("delete archived forever failed")
 		edit := tgbotapi.NewEditMessageText(ctx.ChatID, ctx.MessageID, "⚠️ Failed to delete activity forever.")
 		_, _ = m.bot.Send(edit)
 		return
@@ -679,6 +903,33 @@ func (m *Module) DeleteArchivedForever(ctx *tgctx.MsgContext) {
 	m.ShowArchiveMenuInPlace(ctx)
 }
 
+// isArchivedActivity reports whether activity is in archive (not in trash).
+func (m *Module) isArchivedActivity(ctx *tgctx.MsgContext, activityID int64) bool {
+	items, err := m.tracksvc.ListArchivedActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		return false
+	}
+	for _, item := range items {
+		if item.ID == activityID {
+			return true
+		}
+	}
+	return false
+}
+
+// findInactiveActivityName resolves archived or trashed activity label.
+func (m *Module) findInactiveActivityName(ctx *tgctx.MsgContext, activityID int64) string {
+	items, err := m.tracksvc.ListTrashedActivities(ctx.Ctx, ctx.DBUserID)
+	if err == nil {
+		for _, item := range items {
+			if item.ID == activityID {
+				return activityDisplayName(item)
+			}
+		}
+	}
+	return m.findArchivedActivityName(ctx, activityID)
+}
+
 // findArchivedActivityName resolves archived activity label for confirmations.
 func (m *Module) findArchivedActivityName(ctx *tgctx.MsgContext, activityID int64) string {
 	items, err := m.tracksvc.ListArchivedActivities(ctx.Ctx, ctx.DBUserID)
@@ -693,6 +944,552 @@ func (m *Module) findArchivedActivityName(ctx *tgctx.MsgContext, activityID int6
 	return fmt.Sprintf("#%d", activityID)
 }
 
+// ShowGoalsMenu renders goals with progress and activities to set goals for.
+func (m *Module) ShowGoalsMenu(ctx *tgctx.MsgContext, inPlace bool) {
+	goals, err := m.goalsvc.ListProgress(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list goals failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load goals."))
+		return
+	}
+	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load activities."))
+		return
+	}
+
+	text := track.GoalsMenuText(goals)
+	markup := track.TrackGoalsInlineMenu(items, goals)
+	if inPlace && ctx.MessageID > 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, markup)
+		_, _ = m.bot.Send(edit)
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = markup
+	_, _ = m.bot.Send(msg)
+}
+
+// PromptGoalTarget asks user to type goal target for activity.
+func (m *Module) PromptGoalTarget(ctx *tgctx.MsgContext, activityID int64) {
+	name := m.findActivityName(ctx, activityID)
+	msg := tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf(track.TrackMsgGoalPrompt, name))
+	msg.ParseMode = "Markdown"
+	if _, err := m.bot.Send(msg); err != nil {
+		ctx.Log.Error().Err(err).Msg("send goal prompt failed")
+	}
+}
+
+// ProcessGoalInput saves goal typed by user; returns true when input is accepted.
+func (m *Module) ProcessGoalInput(ctx *tgctx.MsgContext, activityID int64) bool {
+	goal, err := m.goalsvc.SetGoal(ctx.Ctx, ctx.DBUserID, activityID, ctx.Text)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidGoal) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, track.TrackMsgGoalFormat))
+			return false
+		}
+		if errors.Is(err, models.ErrActivityNotFound) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity not found."))
+			return true
+		}
+		ctx.Log.Error().Err(err).Msg("set goal failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to save goal."))
+		return true
+	}
+
+	kind := "at least"
+	if goal.Kind == models.GoalKindMax {
+		kind = "at most"
+	}
+	text := fmt.Sprintf("🎯 Goal saved: %s %s per %s", kind, formatReportDuration(goal.Target), goal.Period)
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+	m.ShowGoalsMenu(ctx, false)
+	return true
+}
+
+// DeleteGoal removes goal chosen on goals screen.
+func (m *Module) DeleteGoal(ctx *tgctx.MsgContext) {
+	goalID, err := strconv.ParseInt(strings.TrimPrefix(ctx.Text, track.TrackCBGoalDelete), 10, 64)
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Invalid goal."))
+		return
+	}
+	if err := m.goalsvc.DeleteGoal(ctx.Ctx, ctx.DBUserID, goalID); err != nil {
+		ctx.Log.Error().Err(err).Msg("delete goal failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to remove goal."))
+		return
+	}
+	m.ShowGoalsMenu(ctx, true)
+}
+
+// ToggleGoalStreak switches streak mode of a daily goal.
+func (m *Module) ToggleGoalStreak(ctx *tgctx.MsgContext) {
+	goalID, err := strconv.ParseInt(strings.TrimPrefix(ctx.Text, track.TrackCBGoalStreak), 10, 64)
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Invalid goal."))
+		return
+	}
+	if err := m.goalsvc.ToggleStreak(ctx.Ctx, ctx.DBUserID, goalID); err != nil {
+		ctx.Log.Error().Err(err).Msg("toggle goal streak failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to update goal."))
+		return
+	}
+	m.ShowGoalsMenu(ctx, true)
+}
+
+// ShowDigestSettings renders digest opt-in toggles and delivery time.
+func (m *Module) ShowDigestSettings(ctx *tgctx.MsgContext, inPlace bool) {
+	settings, err := m.digestsvc.GetSettings(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get digest settings failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load digest settings."))
+		return
+	}
+	m.renderDigestSettings(ctx, settings, inPlace)
+}
+
+// ToggleDigest switches one digest kind from callback payload.
+func (m *Module) ToggleDigest(ctx *tgctx.MsgContext) {
+	kind := strings.TrimPrefix(ctx.Text, track.TrackCBDigestToggle)
+	settings, err := m.digestsvc.Toggle(ctx.Ctx, ctx.DBUserID, kind)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("toggle digest failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to update digest settings."))
+		return
+	}
+	m.renderDigestSettings(ctx, settings, true)
+}
+
+// SetDigestTime applies preset delivery time from callback payload.
+func (m *Module) SetDigestTime(ctx *tgctx.MsgContext) {
+	minutes, err := strconv.Atoi(strings.TrimPrefix(ctx.Text, track.TrackCBDigestTime))
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Invalid time."))
+		return
+	}
+	settings, err := m.digestsvc.SetSendAt(ctx.Ctx, ctx.DBUserID, minutes)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("set digest time failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to update digest settings."))
+		return
+	}
+	m.renderDigestSettings(ctx, settings, true)
+}
+
+// PromptDigestTime asks user to type custom delivery time.
+func (m *Module) PromptDigestTime(ctx *tgctx.MsgContext) {
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, track.TrackMsgDigestTimePrompt))
+}
+
+// ProcessDigestTimeInput saves typed "HH:MM"; returns true when input is accepted.
+func (m *Module) ProcessDigestTimeInput(ctx *tgctx.MsgContext) bool {
+	t, err := time.Parse("15:04", strings.TrimSpace(ctx.Text))
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, track.TrackMsgDigestTimeFormat))
+		return false
+	}
+	settings, err := m.digestsvc.SetSendAt(ctx.Ctx, ctx.DBUserID, t.Hour()*60+t.Minute())
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("set digest time failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to update digest settings."))
+		return true
+	}
+	m.renderDigestSettings(ctx, settings, false)
+	return true
+}
+
+func (m *Module) renderDigestSettings(ctx *tgctx.MsgContext, settings models.DigestSettings, inPlace bool) {
+	text := track.DigestSettingsText(settings)
+	markup := track.TrackDigestInlineMenu(settings)
+	if inPlace && ctx.MessageID > 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, markup)
+		_, _ = m.bot.Send(edit)
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = markup
+	_, _ = m.bot.Send(msg)
+}
+
+// SendDigest builds and sends one scheduled digest.
+func (m *Module) SendDigest(ctx context.Context, due models.DigestDue) error {
+	items, err := m.tracksvc.ListActivities(ctx, due.UserID)
+	if err != nil {
+		return fmt.Errorf("digest activities: %w", err)
+	}
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	// Period bounds are local midnights of the user, unlike today report which follows UTC day.
+	stats, err := m.tracksvc.GetPeriodReport(ctx, due.UserID, due.From, due.To, ids)
+	if err != nil {
+		return fmt.Errorf("digest report: %w", err)
+	}
+
+	var b strings.Builder
+	switch due.Kind {
+	case models.DigestKindDaily:
+		b.WriteString(fmt.Sprintf("🌙 Day summary · %s\n\n", due.From.Format("2006-01-02")))
+		b.WriteString(fmt.Sprintf("Total: %s\nSessions: %d\n", formatReportDuration(stats.TotalTracked), stats.TotalSessions))
+		for i, a := range stats.Activities {
+			name := a.Name
+			if a.Emoji != "" {
+				name = a.Emoji + " " + a.Name
+			}
+			b.WriteString(fmt.Sprintf("%d) %s - %s (%s)\n", i+1, name, formatReportDuration(a.Duration), percentOf(a.Duration, stats.TotalTracked)))
+		}
+		appendGoalCompletionText(&b, stats.Goals)
+
+	case models.DigestKindWeekly, models.DigestKindMonthly:
+		title := "📅 Weekly review"
+		if due.Kind == models.DigestKindMonthly {
+			title = "🗓 Monthly summary"
+		}
+		b.WriteString(fmt.Sprintf("%s · %s..%s\n\n", title, due.From.Format("2006-01-02"), due.To.AddDate(0, 0, -1).Format("2006-01-02")))
+		b.WriteString(fmt.Sprintf("Total: %s\nSessions: %d\n", formatReportDuration(stats.TotalTracked), stats.TotalSessions))
+		for i, a := range stats.Activities {
+			name := a.Name
+			if a.Emoji != "" {
+				name = a.Emoji + " " + a.Name
+			}
+			b.WriteString(fmt.Sprintf("%d) %s - %s (%s, %d)\n", i+1, name, formatReportDuration(a.Duration), percentOf(a.Duration, stats.TotalTracked), a.Sessions))
+		}
+		appendGoalCompletionText(&b, stats.Goals)
+
+	default:
+		return fmt.Errorf("send digest: unknown kind %q", due.Kind)
+	}
+
+	_, err = m.bot.SendScheduled(tgbotapi.NewMessage(due.TgUserID, b.String()))
+	if tgclient.IsUnreachable(err) {
+		m.SetUserActive(ctx, due.TgUserID, false)
+	}
+	return err
+}
+
+// SetUserActive records whether user can receive messages.
+// Deactivation also stops the timer and pomodoro so nothing more is scheduled.
+func (m *Module) SetUserActive(ctx context.Context, tgUserID int64, active bool) {
+	dbID, changed, err := m.entrysvc.SetActive(ctx, tgUserID, active)
+	if err != nil {
+		if !errors.Is(err, models.ErrUserNotFound) {
+			log.Ctx(ctx).Error().Err(err).Int64("tg_user_id", tgUserID).Msg("set user active failed")
+		}
+		return
+	}
+	if !changed {
+		return
+	}
+	log.Ctx(ctx).Info().Int64("user_id", dbID).Bool("active", active).Msg("user activity status changed")
+	if active {
+		return
+	}
+	if err := m.timersvc.Stop(ctx, dbID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int64("user_id", dbID).Msg("stop timer for inactive user failed")
+	}
+	if _, _, err := m.pomodorosvc.Stop(ctx, dbID); err != nil && !errors.Is(err, models.ErrPomodoroNotFound) {
+		log.Ctx(ctx).Error().Err(err).Int64("user_id", dbID).Msg("stop pomodoro for inactive user failed")
+	}
+}
+
+// ShowEditActivities renders activities in manual order with reorder buttons.
+func (m *Module) ShowEditActivities(ctx *tgctx.MsgContext) {
+	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load activities."))
+		return
+	}
+	text := track.TrackMsgEditTitle + "\n\n" + track.TrackMsgEditHint
+	markup := track.TrackEditActivitiesInlineMenu(items)
+	if ctx.MessageID > 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, markup)
+		_, _ = m.bot.Send(edit)
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = markup
+	_, _ = m.bot.Send(msg)
+}
+
+// ShowEditActivity renders edit actions for one activity.
+func (m *Module) ShowEditActivity(ctx *tgctx.MsgContext, activityID int64) {
+	text := track.TrackMsgEditTitle + "\n\n" + m.findActivityName(ctx, activityID)
+	markup := track.TrackEditActivityInlineMenu(activityID)
+	if ctx.MessageID > 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, markup)
+		_, _ = m.bot.Send(edit)
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = markup
+	_, _ = m.bot.Send(msg)
+}
+
+// MoveActivity changes activity position and refreshes edit list.
+func (m *Module) MoveActivity(ctx *tgctx.MsgContext, activityID int64, delta int) {
+	if err := m.tracksvc.MoveActivity(ctx.Ctx, ctx.DBUserID, activityID, delta); err != nil {
+		ctx.Log.Error().Err(err).Msg("move activity failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to reorder activities."))
+		return
+	}
+	m.ShowEditActivities(ctx)
+}
+
+// PromptRenameActivity asks user to type a new activity name.
+func (m *Module) PromptRenameActivity(ctx *tgctx.MsgContext, activityID int64) {
+	name := m.findActivityName(ctx, activityID)
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf(track.TrackMsgRenamePrompt, name)))
+}
+
+// ProcessRenameActivity renames activity from plain text input; returns true when done.
+func (m *Module) ProcessRenameActivity(ctx *tgctx.MsgContext, activityID int64) bool {
+	name := strings.TrimSpace(ctx.Text)
+	if name == "" {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity name cannot be empty."))
+		return false
+	}
+	if err := m.tracksvc.RenameActivity(ctx.Ctx, ctx.DBUserID, activityID, name); err != nil {
+		switch {
+		case errors.Is(err, models.ErrActivityExists):
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity already exists."))
+			return false
+		case errors.Is(err, models.ErrActivityNotFound):
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity not found."))
+			return true
+		}
+		ctx.Log.Error().Err(err).Msg("rename activity failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to rename activity."))
+		return true
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("✏️ Renamed to: %s", name)))
+	ctx.MessageID = 0
+	m.ShowEditActivities(ctx)
+	return true
+}
+
+// PromptActivityEmoji asks user to send a new emoji.
+func (m *Module) PromptActivityEmoji(ctx *tgctx.MsgContext, activityID int64) {
+	name := m.findActivityName(ctx, activityID)
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf(track.TrackMsgEmojiPrompt, name)))
+}
+
+// ProcessActivityEmoji saves emoji from plain text input; returns true when done.
+func (m *Module) ProcessActivityEmoji(ctx *tgctx.MsgContext, activityID int64) bool {
+	if err := m.tracksvc.SetActivityEmoji(ctx.Ctx, ctx.DBUserID, activityID, ctx.Text); err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidEmoji):
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Send a single emoji, or - to remove it."))
+			return false
+		case errors.Is(err, models.ErrActivityNotFound):
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity not found."))
+			return true
+		}
+		ctx.Log.Error().Err(err).Msg("set activity emoji failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to update emoji."))
+		return true
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("Updated: %s", m.findActivityName(ctx, activityID))))
+	ctx.MessageID = 0
+	m.ShowEditActivities(ctx)
+	return true
+}
+
+// ShowMergeTargets lists activities that can absorb the given one.
+func (m *Module) ShowMergeTargets(ctx *tgctx.MsgContext, fromID int64) {
+	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load activities."))
+		return
+	}
+	text := fmt.Sprintf(track.TrackMsgMergePick, m.findActivityName(ctx, fromID))
+	edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, track.TrackMergeTargetsInlineMenu(fromID, items))
+	_, _ = m.bot.Send(edit)
+}
+
+// ShowMergeConfirm asks for confirmation before merging activities.
+func (m *Module) ShowMergeConfirm(ctx *tgctx.MsgContext, fromID, toID int64) {
+	from := m.findActivityName(ctx, fromID)
+	text := fmt.Sprintf(track.TrackMsgMergeConfirm, from, m.findActivityName(ctx, toID), from)
+	edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, track.TrackMergeConfirmInlineMenu(fromID, toID))
+	_, _ = m.bot.Send(edit)
+}
+
+// MergeActivities moves all sessions of fromID into toID.
+func (m *Module) MergeActivities(ctx *tgctx.MsgContext, fromID, toID int64) {
+	from := m.findActivityName(ctx, fromID)
+	to := m.findActivityName(ctx, toID)
+	moved, err := m.tracksvc.MergeActivities(ctx.Ctx, ctx.DBUserID, fromID, toID)
+	if err != nil {
+		if errors.Is(err, models.ErrActivityNotFound) || errors.Is(err, models.ErrSameActivity) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity not found."))
+			return
+		}
+		ctx.Log.Error().Err(err).Msg("merge activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to merge activities."))
+		return
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("🔀 Merged %s into %s (%d sessions moved)", from, to, moved)))
+	m.ShowEditActivities(ctx)
+}
+
+// ShowTagsMenu renders user tags list.
+func (m *Module) ShowTagsMenu(ctx *tgctx.MsgContext, inPlace bool) {
+	tags, err := m.tagsvc.ListTags(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list tags failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load tags."))
+		return
+	}
+	text := track.TrackMsgTagsTitle
+	if len(tags) == 0 {
+		text += "\n\n" + track.TrackMsgTagsEmpty
+	}
+	markup := track.TrackTagsInlineMenu(tags)
+	if inPlace && ctx.MessageID > 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, markup)
+		_, _ = m.bot.Send(edit)
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = markup
+	_, _ = m.bot.Send(msg)
+}
+
+// PromptCreateTag asks user to type a new tag name.
+func (m *Module) PromptCreateTag(ctx *tgctx.MsgContext) {
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, track.TrackMsgTagPrompt))
+}
+
+// ProcessCreateTag creates tag from plain text input; returns true when done.
+func (m *Module) ProcessCreateTag(ctx *tgctx.MsgContext) bool {
+	if strings.TrimSpace(ctx.Text) == "" {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Tag name cannot be empty."))
+		return false
+	}
+	tag, err := m.tagsvc.CreateTag(ctx.Ctx, ctx.DBUserID, ctx.Text)
+	if err != nil {
+		if errors.Is(err, models.ErrTagExists) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Tag already exists."))
+			return false
+		}
+		ctx.Log.Error().Err(err).Msg("create tag failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to create tag."))
+		return false
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("🏷 Created tag: %s", tag.Name)))
+	m.renderTagMenu(ctx, tag.ID, false)
+	return true
+}
+
+// ShowTagMenu opens tag assignment screen from callback payload.
+func (m *Module) ShowTagMenu(ctx *tgctx.MsgContext) {
+	tagID, err := strconv.ParseInt(strings.TrimPrefix(ctx.Text, track.TrackCBTagOpen), 10, 64)
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Invalid tag."))
+		return
+	}
+	m.renderTagMenu(ctx, tagID, true)
+}
+
+// ToggleActivityTag assigns or unassigns tag for activity from callback payload.
+func (m *Module) ToggleActivityTag(ctx *tgctx.MsgContext) {
+	parts := strings.Split(strings.TrimPrefix(ctx.Text, track.TrackCBTagAssign), ":")
+	if len(parts) != 2 {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Invalid selection payload."))
+		return
+	}
+	tagID, err1 := strconv.ParseInt(parts[0], 10, 64)
+	activityID, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Invalid selection payload."))
+		return
+	}
+	if err := m.tagsvc.ToggleActivityTag(ctx.Ctx, ctx.DBUserID, activityID, tagID); err != nil {
+		ctx.Log.Error().Err(err).Msg("toggle activity tag failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to update tag."))
+		return
+	}
+	m.renderTagMenu(ctx, tagID, true)
+}
+
+// DeleteTag removes tag; activities and their history are kept.
+func (m *Module) DeleteTag(ctx *tgctx.MsgContext) {
+	tagID, err := strconv.ParseInt(strings.TrimPrefix(ctx.Text, track.TrackCBTagDelete), 10, 64)
+	if err != nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Invalid tag."))
+		return
+	}
+	if err := m.tagsvc.DeleteTag(ctx.Ctx, ctx.DBUserID, tagID); err != nil {
+		ctx.Log.Error().Err(err).Msg("delete tag failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to delete tag."))
+		return
+	}
+	m.ShowTagsMenu(ctx, true)
+}
+
+// ResolveTagActivityIDs returns activities of tag and its subtags for report filters.
+func (m *Module) ResolveTagActivityIDs(ctx *tgctx.MsgContext, tagID int64) ([]int64, bool) {
+	ids, err := m.tagsvc.ResolveActivityIDs(ctx.Ctx, ctx.DBUserID, tagID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("resolve tag activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load tag activities."))
+		return nil, false
+	}
+	return ids, true
+}
+
+// renderTagMenu renders activities with assignment state for one tag.
+func (m *Module) renderTagMenu(ctx *tgctx.MsgContext, tagID int64, inPlace bool) {
+	tags, err := m.tagsvc.ListTags(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list tags failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load tags."))
+		return
+	}
+	var current models.Tag
+	for _, t := range tags {
+		if t.ID == tagID {
+			current = t
+		}
+	}
+	if current.ID == 0 {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Tag not found."))
+		return
+	}
+
+	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load activities."))
+		return
+	}
+	ids, err := m.tagsvc.ListTagActivityIDs(ctx.Ctx, ctx.DBUserID, tagID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list tag activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load tag activities."))
+		return
+	}
+	assigned := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		assigned[id] = true
+	}
+
+	text := fmt.Sprintf("%s%s\n\n%s", track.TrackLabelTagItemPrefix, current.Path, track.TrackMsgTagAssignHint)
+	markup := track.TrackTagInlineMenu(tagID, items, assigned)
+	if inPlace && ctx.MessageID > 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, markup)
+		_, _ = m.bot.Send(edit)
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = markup
+	_, _ = m.bot.Send(msg)
+}
+
 // ShowTrackTimerMenu renders timer interval selector.
 func (m *Module) ShowTrackTimerMenu(ctx *tgctx.MsgContext) {
 	msg := tgbotapi.NewMessage(ctx.ChatID, "Select tracking interval:")
@@ -708,7 +1505,7 @@ func (m *Module) ActivateTrackTimer(ctx *tgctx.MsgContext, intervalMin int) {
 
 	items, err := m.tracksvc.ListSelectedActivities(ctx.Ctx, ctx.DBUserID)
 	if err != nil {
-		log.Error().Err(err).Msg("load selected activities failed")
+		ctx.Log.Error().Err(err).Msg("load selected activities failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load selected activities."))
 		return
 	}
@@ -718,12 +1515,16 @@ func (m *Module) ActivateTrackTimer(ctx *tgctx.MsgContext, intervalMin int) {
 	}
 
 	if err := m.timersvc.Activate(ctx.Ctx, ctx.DBUserID, intervalMin); err != nil {
-		log.Error().Err(err).Msg("activate timer failed")
+		ctx.Log.Error().Err(err).Msg("activate timer failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to activate timer."))
 		return
 	}
 
-	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("✅ Timer activated: every %d min", intervalMin)))
+	text := fmt.Sprintf("✅ Timer activated: every %d min", intervalMin)
+	if settings, err := m.timersvc.GetSettings(ctx.Ctx, ctx.DBUserID); err == nil && settings.Adaptive.Enabled {
+		text += fmt.Sprintf("\n🧠 Adaptive: %d-%d min depending on your answers", settings.Adaptive.MinMin, settings.Adaptive.MaxMin)
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
 	hide := tgbotapi.NewMessage(ctx.ChatID, " ")
 	hide.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
 	_, _ = m.bot.Send(hide)
@@ -733,13 +1534,53 @@ func (m *Module) ActivateTrackTimer(ctx *tgctx.MsgContext, intervalMin int) {
 // StopTrackTimer disables active tracking timer.
 func (m *Module) StopTrackTimer(ctx *tgctx.MsgContext) {
 	if err := m.timersvc.Stop(ctx.Ctx, ctx.DBUserID); err != nil {
-		log.Error().Err(err).Msg("stop timer failed")
+		ctx.Log.Error().Err(err).Msg("stop timer failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to stop timer."))
 		return
 	}
 	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⏹ Timer stopped"))
 }
 
+// ShowAdaptiveInterval shows adaptive interval switch, its bounds and current interval.
+func (m *Module) ShowAdaptiveInterval(ctx *tgctx.MsgContext, inPlace bool) {
+	settings, err := m.timersvc.GetSettings(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get timer settings failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load timer settings."))
+		return
+	}
+	text := track.TrackMsgAdaptiveTitle + "\n\n" + track.TrackMsgAdaptiveHint + "\n\n" +
+		fmt.Sprintf(track.TrackMsgAdaptiveCurrent, settings.IntervalMin)
+	m.sendOrEdit(ctx, inPlace, text, track.TrackAdaptiveInlineMenu(settings.Adaptive))
+}
+
+// CycleAdaptiveSetting toggles adaptive interval or switches one bound to its next preset value.
+func (m *Module) CycleAdaptiveSetting(ctx *tgctx.MsgContext, key string) {
+	settings, err := m.timersvc.GetSettings(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get timer settings failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load timer settings."))
+		return
+	}
+	adaptive := settings.Adaptive
+	switch key {
+	case track.AdaptiveSettingToggle:
+		adaptive.Enabled = !adaptive.Enabled
+	case track.AdaptiveSettingMin:
+		adaptive.MinMin = nextOf(track.AdaptiveMinChoices, adaptive.MinMin)
+	case track.AdaptiveSettingMax:
+		adaptive.MaxMin = nextOf(track.AdaptiveMaxChoices, adaptive.MaxMin)
+	default:
+		return
+	}
+	if err := m.timersvc.SetAdaptive(ctx.Ctx, ctx.DBUserID, adaptive); err != nil {
+		ctx.Log.Error().Err(err).Msg("set adaptive interval failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to save timer settings."))
+		return
+	}
+	m.ShowAdaptiveInterval(ctx, true)
+}
+
 // SendPromptMessage sends periodic "what are you doing now?" prompt.
 func (m *Module) SendPromptMessage(ctx context.Context, chatID int64, userID int64, intervalMin int) error {
 	items, err := m.tracksvc.ListSelectedActivities(ctx, userID)
@@ -752,7 +1593,10 @@ func (m *Module) SendPromptMessage(ctx context.Context, chatID int64, userID int
 
 	msg := tgbotapi.NewMessage(chatID, "What are you doing now?")
 	msg.ReplyMarkup = track.TrackPromptInlineMenu(items, intervalMin)
-	_, err = m.bot.Send(msg)
+	_, err = m.bot.SendScheduled(msg)
+	if tgclient.IsUnreachable(err) {
+		m.SetUserActive(ctx, chatID, false)
+	}
 	return err
 }
 
@@ -778,10 +1622,11 @@ func (m *Module) RecordPromptAnswer(ctx *tgctx.MsgContext) {
 	}
 
 	if err := m.timersvc.RecordPromptAnswerWithInterval(ctx.Ctx, ctx.DBUserID, activityID, intervalMin); err != nil {
-		log.Error().Err(err).Msg("record prompt answer failed")
+		ctx.Log.Error().Err(err).Msg("record prompt answer failed")
 		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to save activity."))
 		return
 	}
+	metrics.PromptAnswered()
 
 	if ctx.MessageID > 0 {
 		del := tgbotapi.NewDeleteMessage(ctx.ChatID, ctx.MessageID)
@@ -799,6 +1644,11 @@ func (m *Module) RecordPromptAnswer(ctx *tgctx.MsgContext) {
 		endAt.Format("15:04"),
 		intervalMin,
 	)
+	if next, changed, err := m.timersvc.AdaptInterval(ctx.Ctx, ctx.DBUserID, activityID); err != nil {
+		ctx.Log.Warn().Err(err).Msg("adapt interval failed")
+	} else if changed {
+		text += fmt.Sprintf("\n⏱ Next prompts every %d min", next)
+	}
 	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
 }
 
@@ -818,19 +1668,7 @@ func (m *Module) findActivityName(ctx *tgctx.MsgContext, activityID int64) strin
 
 // formatReportDuration formats duration as "Xh Ym".
 func formatReportDuration(d time.Duration) string {
-	if d < 0 {
-		d = 0
-	}
-	h := int(d.Hours())
-	m := int(d.Minutes()) % 60
-	switch {
-	case h > 0 && m > 0:
-		return fmt.Sprintf("%dh %dm", h, m)
-	case h > 0:
-		return fmt.Sprintf("%dh", h)
-	default:
-		return fmt.Sprintf("%dm", m)
-	}
+	return textbuilder.Duration(d)
 }
 
 // formatDateOrDash returns date in YYYY-MM-DD or dash for empty time.
@@ -854,7 +1692,7 @@ func percentOf(part, total time.Duration) string {
 func (m *Module) ShowLearningMenu(ctx *tgctx.MsgContext) {
 	stats, err := m.learningsvc.GetLearningStats(ctx.Ctx, ctx.UserID)
 	if err != nil {
-		log.Error().Err(err).Msg("GetLearningStats failed")
+		ctx.Log.Error().Err(err).Msg("GetLearningStats failed")
 		msg := tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load learning data. Please try again.")
 		_, _ = m.bot.Send(msg)
 		return
@@ -867,7 +1705,7 @@ func (m *Module) ShowLearningMenu(ctx *tgctx.MsgContext) {
 	msg.ReplyMarkup = learning.LearningEntryInlineMenu()
 
 	if _, err := m.bot.Send(msg); err != nil {
-		log.Error().Err(err).Msg("send learning menu failed")
+		ctx.Log.Error().Err(err).Msg("send learning menu failed")
 	}
 }
 
@@ -875,7 +1713,7 @@ func (m *Module) ShowLearningMenu(ctx *tgctx.MsgContext) {
 func (m *Module) ShowSubscriptionMenu(ctx *tgctx.MsgContext) {
 	stats, err := m.subscriptionsvc.GetSubscriptionStats(ctx.Ctx, ctx.UserID)
 	if err != nil {
-		log.Error().Err(err).Msg("GetSubscriptionStats failed")
+		ctx.Log.Error().Err(err).Msg("GetSubscriptionStats failed")
 		msg := tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load subscription data. Please try again.")
 		_, _ = m.bot.Send(msg)
 		return
@@ -888,7 +1726,7 @@ func (m *Module) ShowSubscriptionMenu(ctx *tgctx.MsgContext) {
 	msg.ReplyMarkup = subscription.SubscriptionEntryInlineMenu()
 
 	if _, err := m.bot.Send(msg); err != nil {
-		log.Error().Err(err).Msg("send subscription menu failed")
+		ctx.Log.Error().Err(err).Msg("send subscription menu failed")
 	}
 }
 
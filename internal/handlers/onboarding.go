@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"tracker-bot/internal/buttons/onboarding"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/utils/tgctx"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// onboardingSteps is number of wizard screens shown as "Step N/M".
+const onboardingSteps = 6
+
+// RecordStartPayload stores /start deep-link payload for attribution; failures are only logged.
+func (m *Module) RecordStartPayload(ctx *tgctx.MsgContext, payload string) {
+	if err := m.onboardingsvc.RecordStartPayload(ctx.Ctx, ctx.DBUserID, payload); err != nil {
+		ctx.Log.Error().Err(err).Msg("record start payload failed")
+	}
+}
+
+// ResumeOnboarding shows wizard step user stopped at; false when wizard is finished.
+func (m *Module) ResumeOnboarding(ctx *tgctx.MsgContext, inPlace bool) bool {
+	step, err := m.onboardingsvc.Step(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get onboarding step failed")
+		return false
+	}
+	if step == models.OnboardingStepDone {
+		return false
+	}
+	m.renderOnboardingStep(ctx, step, inPlace)
+	return true
+}
+
+// SetOnboardingLanguage stores chosen language and moves to time zone step.
+func (m *Module) SetOnboardingLanguage(ctx *tgctx.MsgContext, code string) {
+	if !slices.ContainsFunc(onboarding.Languages, func(c onboarding.Choice) bool { return c.Value == code }) {
+		return
+	}
+	if err := m.profilesvc.ChangeLanguage(ctx.Ctx, ctx.UserID, code); err != nil {
+		m.onboardingError(ctx, err, "change language failed")
+		return
+	}
+	m.advanceOnboarding(ctx, models.OnboardingStepLanguage, true)
+}
+
+// PromptOnboardingTimeZone asks to type IANA zone name.
+func (m *Module) PromptOnboardingTimeZone(ctx *tgctx.MsgContext) {
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Send your time zone, e.g. Europe/Paris or Asia/Tokyo."))
+}
+
+// SetOnboardingTimeZone stores zone picked by button or typed; false keeps waiting for valid input.
+func (m *Module) SetOnboardingTimeZone(ctx *tgctx.MsgContext, zone string, inPlace bool) bool {
+	zone = strings.TrimSpace(zone)
+	if _, err := time.LoadLocation(zone); err != nil || zone == "" || zone == "Local" {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Unknown time zone. Use a name like Europe/Paris or UTC."))
+		return false
+	}
+	if err := m.profilesvc.ChangeTimeZone(ctx.Ctx, ctx.UserID, zone); err != nil {
+		m.onboardingError(ctx, err, "change time zone failed")
+		return true
+	}
+	m.advanceOnboarding(ctx, models.OnboardingStepTimeZone, inPlace)
+	return true
+}
+
+// AddOnboardingActivity creates starter template activity and selects it for timer prompts.
+func (m *Module) AddOnboardingActivity(ctx *tgctx.MsgContext, index int) {
+	if index < 0 || index >= len(onboarding.StarterActivities) {
+		return
+	}
+	t := onboarding.StarterActivities[index]
+	if err := m.addOnboardingActivity(ctx, t.Name, t.Emoji); err != nil && !errors.Is(err, models.ErrActivityExists) {
+		m.onboardingError(ctx, err, "create starter activity failed")
+		return
+	}
+	m.renderOnboardingStep(ctx, models.OnboardingStepActivities, true)
+}
+
+// PromptOnboardingActivity asks to type own activity name.
+func (m *Module) PromptOnboardingActivity(ctx *tgctx.MsgContext) {
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Send activity name, e.g. Side project."))
+}
+
+// ProcessOnboardingActivity creates typed activity; false keeps waiting for valid name.
+func (m *Module) ProcessOnboardingActivity(ctx *tgctx.MsgContext) bool {
+	name := strings.TrimSpace(ctx.Text)
+	if name == "" {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity name cannot be empty."))
+		return false
+	}
+	if err := m.addOnboardingActivity(ctx, name, ""); err != nil {
+		if errors.Is(err, models.ErrActivityExists) {
+			_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Activity already exists. Send another name."))
+			return false
+		}
+		m.onboardingError(ctx, err, "create activity failed")
+		return true
+	}
+	m.renderOnboardingStep(ctx, models.OnboardingStepActivities, false)
+	return true
+}
+
+// FinishOnboardingActivities moves on once user has at least one activity.
+func (m *Module) FinishOnboardingActivities(ctx *tgctx.MsgContext) {
+	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.onboardingError(ctx, err, "list activities failed")
+		return
+	}
+	if len(items) == 0 {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Add at least one activity to track."))
+		return
+	}
+	m.advanceOnboarding(ctx, models.OnboardingStepActivities, true)
+}
+
+// SetOnboardingInterval stores default prompt interval without starting timer.
+func (m *Module) SetOnboardingInterval(ctx *tgctx.MsgContext, intervalMin int) {
+	if !slices.Contains(onboarding.Intervals, intervalMin) {
+		return
+	}
+	if err := m.timersvc.SaveDefaultInterval(ctx.Ctx, ctx.DBUserID, intervalMin); err != nil {
+		m.onboardingError(ctx, err, "save default interval failed")
+		return
+	}
+	m.advanceOnboarding(ctx, models.OnboardingStepInterval, true)
+}
+
+// SetOnboardingQuiet stores quiet hours; zero window turns them off.
+func (m *Module) SetOnboardingQuiet(ctx *tgctx.MsgContext, quiet models.QuietHours) {
+	if err := m.timersvc.SetQuietHours(ctx.Ctx, ctx.DBUserID, quiet); err != nil {
+		m.onboardingError(ctx, err, "set quiet hours failed")
+		return
+	}
+	m.advanceOnboarding(ctx, models.OnboardingStepQuiet, true)
+}
+
+// CompleteOnboarding finishes wizard and optionally starts timer with saved interval.
+func (m *Module) CompleteOnboarding(ctx *tgctx.MsgContext, startTimer bool) {
+	settings, err := m.timersvc.GetSettings(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.onboardingError(ctx, err, "get timer settings failed")
+		return
+	}
+	if err := m.onboardingsvc.Finish(ctx.Ctx, ctx.DBUserID); err != nil {
+		m.onboardingError(ctx, err, "finish onboarding failed")
+		return
+	}
+	m.sendOrEdit(ctx, true, "🎉 You are all set!", tgbotapi.NewInlineKeyboardMarkup())
+	if startTimer {
+		m.ActivateTrackTimer(ctx, settings.IntervalMin)
+		return
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Start the timer any time from 📈Track."))
+	m.ShowEntryMenu(ctx)
+}
+
+// SkipOnboarding ends wizard with defaults.
+func (m *Module) SkipOnboarding(ctx *tgctx.MsgContext) {
+	if err := m.onboardingsvc.Finish(ctx.Ctx, ctx.DBUserID); err != nil {
+		m.onboardingError(ctx, err, "skip onboarding failed")
+		return
+	}
+	m.sendOrEdit(ctx, true, "Setup skipped. You can change everything later in 👤 Profile and 📈Track.", tgbotapi.NewInlineKeyboardMarkup())
+	m.ShowEntryMenu(ctx)
+}
+
+func (m *Module) advanceOnboarding(ctx *tgctx.MsgContext, step string, inPlace bool) {
+	next, err := m.onboardingsvc.Advance(ctx.Ctx, ctx.DBUserID, step)
+	if err != nil {
+		m.onboardingError(ctx, err, "advance onboarding failed")
+		return
+	}
+	if next == models.OnboardingStepDone {
+		m.ShowEntryMenu(ctx)
+		return
+	}
+	m.renderOnboardingStep(ctx, next, inPlace)
+}
+
+func (m *Module) renderOnboardingStep(ctx *tgctx.MsgContext, step string, inPlace bool) {
+	var (
+		text   string
+		markup tgbotapi.InlineKeyboardMarkup
+	)
+	switch step {
+	case models.OnboardingStepLanguage:
+		text = fmt.Sprintf("👋 Welcome to Tracker Bot! Let's set you up, it takes a minute.\n\n"+
+			"Step 1/%d · Choose your language.", onboardingSteps)
+		markup = onboarding.LanguageInlineMenu()
+	case models.OnboardingStepTimeZone:
+		text = fmt.Sprintf("Step 2/%d · Where are you? Your time zone is used for reports, goals and quiet hours.", onboardingSteps)
+		markup = onboarding.TimeZoneInlineMenu()
+	case models.OnboardingStepActivities:
+		items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+		if err != nil {
+			m.onboardingError(ctx, err, "list activities failed")
+			return
+		}
+		added := make(map[string]bool, len(items))
+		for _, it := range items {
+			added[strings.ToLower(it.Name)] = true
+		}
+		text = fmt.Sprintf("Step 3/%d · What do you want to track?\n\n"+
+			"Tap templates to add them or type your own. Activities so far: %d.", onboardingSteps, len(items))
+		markup = onboarding.ActivitiesInlineMenu(added)
+	case models.OnboardingStepInterval:
+		text = fmt.Sprintf("Step 4/%d · How often should I ask what you are doing?", onboardingSteps)
+		markup = onboarding.IntervalInlineMenu()
+	case models.OnboardingStepQuiet:
+		text = fmt.Sprintf("Step 5/%d · Quiet hours: I won't ask during this time (your local time).", onboardingSteps)
+		markup = onboarding.QuietInlineMenu()
+	case models.OnboardingStepTimer:
+		settings, err := m.timersvc.GetSettings(ctx.Ctx, ctx.DBUserID)
+		if err != nil {
+			m.onboardingError(ctx, err, "get timer settings failed")
+			return
+		}
+		quiet := "no quiet hours"
+		if settings.Quiet.Enabled() {
+			quiet = fmt.Sprintf("quiet %s–%s", clockText(settings.Quiet.FromMin), clockText(settings.Quiet.ToMin))
+		}
+		text = fmt.Sprintf("Step 6/%d · Ready! I'll ask every %d min what you worked on (%s).\n\nStart the timer now?",
+			onboardingSteps, settings.IntervalMin, quiet)
+		markup = onboarding.TimerInlineMenu()
+	default:
+		return
+	}
+	m.sendOrEdit(ctx, inPlace, text, markup)
+}
+
+// addOnboardingActivity creates activity and selects it, so timer prompts offer it.
+func (m *Module) addOnboardingActivity(ctx *tgctx.MsgContext, name, emoji string) error {
+	activity, err := m.tracksvc.CreateActivity(ctx.Ctx, ctx.DBUserID, name, emoji)
+	if err != nil {
+		return err
+	}
+	return m.tracksvc.ToggleSelectedActivity(ctx.Ctx, ctx.DBUserID, activity.ID)
+}
+
+// clockText formats minutes after midnight as HH:MM.
+func clockText(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func (m *Module) onboardingError(ctx *tgctx.MsgContext, err error, logMsg string) {
+	ctx.Log.Error().Err(err).Msg(logMsg)
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Something went wrong. Please try again."))
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"tracker-bot/internal/buttons/partner"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/utils/tgctx"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ShowPartners lists user's accountability partners.
+func (m *Module) ShowPartners(ctx *tgctx.MsgContext, inPlace bool) {
+	partners, err := m.partnersvc.List(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list partners failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load partners."))
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("🤝 Partners\n\n")
+	if len(partners) == 0 {
+		b.WriteString("No partners yet. Invite a friend to keep each other on track: " +
+			"you both choose which goals the other sees and can nudge each other on idle days.")
+	}
+	for _, p := range partners {
+		b.WriteString(fmt.Sprintf("• %s — since %s, you share %d activities\n",
+			partner.Label(p.Name), p.Since.Format("2006-01-02"), len(p.ActivityIDs)))
+	}
+	m.sendOrEdit(ctx, inPlace, b.String(), partner.PartnerListInlineMenu(partners))
+}
+
+// CreatePartnerInvite sends one-time invite link to forward to a friend.
+func (m *Module) CreatePartnerInvite(ctx *tgctx.MsgContext) {
+	link, expiresAt, err := m.partnersvc.CreateInvite(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("create partner invite failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to create invite."))
+		return
+	}
+	text := fmt.Sprintf("Forward this link to your partner. It works once and expires %s UTC.\n\n%s",
+		expiresAt.Format("2006-01-02 15:04"), link)
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+}
+
+// ShowPartnerInvite asks invitee for consent before pairing.
+func (m *Module) ShowPartnerInvite(ctx *tgctx.MsgContext, token string) {
+	inv, err := m.partnersvc.GetInvite(ctx.Ctx, token)
+	if err != nil {
+		m.partnerError(ctx, err, "get partner invite failed")
+		return
+	}
+	if inv.UserID == ctx.DBUserID {
+		m.partnerError(ctx, models.ErrOwnInvite, "")
+		return
+	}
+
+	name := partner.Label(inv.InviterName)
+	text := fmt.Sprintf("🤝 %s invites you to be accountability partners.\n\n"+
+		"If you accept:\n"+
+		"• you choose which activities' goal progress %s sees (none by default)\n"+
+		"• you can nudge each other on days without tracked time\n"+
+		"• either of you can end the partnership any time", name, name)
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = partner.PartnerConsentInlineMenu(token)
+	_, _ = m.bot.Send(msg)
+}
+
+// AcceptPartnerInvite pairs users and tells inviter.
+func (m *Module) AcceptPartnerInvite(ctx *tgctx.MsgContext, token string) {
+	p, err := m.partnersvc.AcceptInvite(ctx.Ctx, token, ctx.DBUserID)
+	if err != nil {
+		m.partnerError(ctx, err, "accept partner invite failed")
+		return
+	}
+
+	m.closePartnerPrompt(ctx, fmt.Sprintf("🤝 You and %s are partners now.", partner.Label(p.Name)))
+	m.ShowPartnerSharing(ctx, p.PartnershipID, false)
+
+	if self, err := m.partnersvc.Get(ctx.Ctx, p.PartnershipID, p.UserID); err == nil {
+		text := fmt.Sprintf("🤝 %s accepted your invite. Choose what you share in /partners.", partner.Label(self.Name))
+		_, _ = m.bot.Send(tgbotapi.NewMessage(p.TgUserID, text))
+	}
+}
+
+// DeclinePartnerInvite leaves invite unused.
+func (m *Module) DeclinePartnerInvite(ctx *tgctx.MsgContext) {
+	m.closePartnerPrompt(ctx, "Invite declined.")
+}
+
+// ShowPartner shows partner's shared goal progress and today's status.
+func (m *Module) ShowPartner(ctx *tgctx.MsgContext, partnershipID int64, inPlace bool) {
+	progress, err := m.partnersvc.Progress(ctx.Ctx, partnershipID, ctx.DBUserID)
+	if err != nil {
+		m.partnerError(ctx, err, "partner progress failed")
+		return
+	}
+
+	name := partner.Label(progress.Partner.Name)
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🤝 %s · partners since %s\n\n", name, progress.Partner.Since.Format("2006-01-02")))
+	if progress.TrackedToday {
+		b.WriteString("Today: ✅ tracked\n")
+	} else {
+		b.WriteString("Today: 💤 nothing tracked yet\n")
+	}
+	if len(progress.Goals) == 0 {
+		b.WriteString(fmt.Sprintf("\n%s shares no goals with you yet.", name))
+	}
+	appendGoalProgressText(&b, progress.Goals)
+
+	m.sendOrEdit(ctx, inPlace, b.String(), partner.PartnerInlineMenu(progress))
+}
+
+// ShowPartnerSharing shows which own activities partner may see.
+func (m *Module) ShowPartnerSharing(ctx *tgctx.MsgContext, partnershipID int64, inPlace bool) {
+	p, err := m.partnersvc.Get(ctx.Ctx, partnershipID, ctx.DBUserID)
+	if err != nil {
+		m.partnerError(ctx, err, "get partner failed")
+		return
+	}
+	m.renderPartnerSharing(ctx, p, inPlace)
+}
+
+// TogglePartnerActivity shares or hides one activity's goals from partner.
+func (m *Module) TogglePartnerActivity(ctx *tgctx.MsgContext, partnershipID, activityID int64) {
+	p, err := m.partnersvc.ToggleActivity(ctx.Ctx, partnershipID, ctx.DBUserID, activityID)
+	if err != nil {
+		m.partnerError(ctx, err, "toggle partner activity failed")
+		return
+	}
+	m.renderPartnerSharing(ctx, p, true)
+}
+
+// NudgePartner sends one-tap reminder to partner with no tracked time today.
+func (m *Module) NudgePartner(ctx *tgctx.MsgContext, partnershipID int64) {
+	p, err := m.partnersvc.Nudge(ctx.Ctx, partnershipID, ctx.DBUserID)
+	if err != nil {
+		m.partnerError(ctx, err, "nudge partner failed")
+		return
+	}
+
+	from := "Your partner"
+	if self, err := m.partnersvc.Get(ctx.Ctx, partnershipID, p.UserID); err == nil && self.Name != "" {
+		from = "@" + self.Name
+	}
+	text := fmt.Sprintf("👋 %s nudges you: nothing tracked today yet. A short session still counts!", from)
+	if _, err := m.bot.Send(tgbotapi.NewMessage(p.TgUserID, text)); err != nil {
+		ctx.Log.Warn().Err(err).Msg("deliver nudge failed")
+		if err := m.partnersvc.ReleaseNudge(ctx.Ctx, p, ctx.DBUserID); err != nil {
+			ctx.Log.Error().Err(err).Msg("release nudge failed")
+		}
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Could not deliver nudge."))
+		return
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("👋 Nudge sent to %s.", partner.Label(p.Name))))
+}
+
+// EndPartnership removes partnership for both sides.
+func (m *Module) EndPartnership(ctx *tgctx.MsgContext, partnershipID int64) {
+	p, err := m.partnersvc.Get(ctx.Ctx, partnershipID, ctx.DBUserID)
+	if err == nil {
+		err = m.partnersvc.End(ctx.Ctx, partnershipID, ctx.DBUserID)
+	}
+	if err != nil && !errors.Is(err, models.ErrPartnerNotFound) {
+		ctx.Log.Error().Err(err).Msg("end partnership failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to end partnership."))
+		return
+	}
+	if err == nil {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(p.TgUserID, "💔 A partner ended your partnership. See /partners."))
+	}
+	m.ShowPartners(ctx, true)
+}
+
+func (m *Module) renderPartnerSharing(ctx *tgctx.MsgContext, p models.Partner, inPlace bool) {
+	activities, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load activities."))
+		return
+	}
+	text := fmt.Sprintf("🔒 What %s sees\n\nChecked activities show their goal progress to your partner. "+
+		"Whether you tracked anything today is always visible, so they can nudge you.", partner.Label(p.Name))
+	m.sendOrEdit(ctx, inPlace, text, partner.PartnerSharingInlineMenu(p, activities))
+}
+
+// closePartnerPrompt replaces consent message so buttons cannot be reused.
+func (m *Module) closePartnerPrompt(ctx *tgctx.MsgContext, text string) {
+	if ctx.MessageID <= 0 {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+		return
+	}
+	_, _ = m.bot.Send(tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, tgbotapi.NewInlineKeyboardMarkup()))
+}
+
+func (m *Module) partnerError(ctx *tgctx.MsgContext, err error, logMsg string) {
+	var text string
+	switch {
+	case errors.Is(err, models.ErrInviteNotFound):
+		text = "This invite is invalid, already used or expired. Ask for a new one."
+	case errors.Is(err, models.ErrOwnInvite):
+		text = "This is your own invite. Forward it to your partner."
+	case errors.Is(err, models.ErrAlreadyPartners):
+		text = "You are already partners. See /partners."
+	case errors.Is(err, models.ErrPartnerNotFound):
+		text = "This partnership has ended."
+	case errors.Is(err, models.ErrPartnerTracked):
+		text = "Your partner already tracked time today 💪"
+	case errors.Is(err, models.ErrAlreadyNudged):
+		text = "You already nudged your partner today."
+	default:
+		ctx.Log.Error().Err(err).Msg(logMsg)
+		text = "⚠️ Something went wrong. Please try again."
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tracker-bot/internal/buttons/track"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/utils/tgclient"
+	"tracker-bot/internal/utils/tgctx"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ShowPomodoro moves running pomodoro countdown to the bottom of chat,
+// otherwise shows settings and activities to start focus with.
+func (m *Module) ShowPomodoro(ctx *tgctx.MsgContext, inPlace bool) {
+	p, err := m.pomodorosvc.Get(ctx.Ctx, ctx.DBUserID)
+	if err == nil {
+		if p.MessageID > 0 {
+			_, _ = m.bot.Send(tgbotapi.NewEditMessageReplyMarkup(ctx.ChatID, p.MessageID, tgbotapi.NewInlineKeyboardMarkup()))
+		}
+		if err := m.showPomodoroCountdown(ctx.Ctx, ctx.ChatID, 0, p); err != nil {
+			ctx.Log.Error().Err(err).Msg("send pomodoro countdown failed")
+		}
+		return
+	}
+	if !errors.Is(err, models.ErrPomodoroNotFound) {
+		m.pomodoroError(ctx, err, "get pomodoro failed")
+		return
+	}
+	m.renderPomodoroSettings(ctx, inPlace)
+}
+
+// CyclePomodoroSetting switches one setting to its next preset value.
+func (m *Module) CyclePomodoroSetting(ctx *tgctx.MsgContext, key string) {
+	settings, err := m.pomodorosvc.GetSettings(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.pomodoroError(ctx, err, "get pomodoro settings failed")
+		return
+	}
+	switch key {
+	case track.PomodoroSettingFocus:
+		settings.FocusMin = nextOf(track.PomodoroFocusChoices, settings.FocusMin)
+	case track.PomodoroSettingShort:
+		settings.ShortBreakMin = nextOf(track.PomodoroShortChoices, settings.ShortBreakMin)
+	case track.PomodoroSettingLong:
+		settings.LongBreakMin = nextOf(track.PomodoroLongChoices, settings.LongBreakMin)
+	case track.PomodoroSettingEvery:
+		settings.LongBreakEvery = nextOf(track.PomodoroEveryChoices, settings.LongBreakEvery)
+	default:
+		return
+	}
+	if err := m.pomodorosvc.SaveSettings(ctx.Ctx, ctx.DBUserID, settings); err != nil {
+		m.pomodoroError(ctx, err, "save pomodoro settings failed")
+		return
+	}
+	m.renderPomodoroSettings(ctx, true)
+}
+
+// StartPomodoro begins focus for activity and turns settings message into countdown.
+// Interval prompts are stopped so they do not interrupt focus.
+func (m *Module) StartPomodoro(ctx *tgctx.MsgContext, activityID int64) {
+	p, err := m.pomodorosvc.Start(ctx.Ctx, ctx.DBUserID, activityID)
+	if err != nil {
+		m.pomodoroError(ctx, err, "start pomodoro failed")
+		return
+	}
+
+	if err := m.showPomodoroCountdown(ctx.Ctx, ctx.ChatID, ctx.MessageID, p); err != nil {
+		ctx.Log.Error().Err(err).Msg("send pomodoro countdown failed")
+	}
+
+	timer, err := m.timersvc.GetSettings(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Warn().Err(err).Msg("get timer settings failed")
+		return
+	}
+	if !timer.Enabled {
+		return
+	}
+	if err := m.timersvc.Stop(ctx.Ctx, ctx.DBUserID); err != nil {
+		ctx.Log.Error().Err(err).Msg("stop timer failed")
+		return
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, track.TrackMsgPomodoroTimerStopped))
+}
+
+// PomodoroCommand handles "/pomodoro" and "/pomodoro <activity>" to start focus at once.
+func (m *Module) PomodoroCommand(ctx *tgctx.MsgContext, args string) {
+	if args == "" {
+		m.ShowPomodoro(ctx, false)
+		return
+	}
+	item, ok := m.matchActivity(ctx, args)
+	if !ok {
+		return
+	}
+	ctx.MessageID = 0
+	m.StartPomodoro(ctx, item.ID)
+}
+
+// NextPomodoroFocus starts next focus block when a break is skipped or over,
+// turning the tapped message into its countdown.
+func (m *Module) NextPomodoroFocus(ctx *tgctx.MsgContext) {
+	p, err := m.pomodorosvc.NextFocus(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.pomodoroError(ctx, err, "start next pomodoro focus failed")
+		return
+	}
+	if p.MessageID > 0 && p.MessageID != ctx.MessageID {
+		_, _ = m.bot.Send(tgbotapi.NewEditMessageReplyMarkup(ctx.ChatID, p.MessageID, tgbotapi.NewInlineKeyboardMarkup()))
+	}
+	if err := m.showPomodoroCountdown(ctx.Ctx, ctx.ChatID, ctx.MessageID, p); err != nil {
+		ctx.Log.Error().Err(err).Msg("send pomodoro countdown failed")
+	}
+}
+
+// StopPomodoro ends pomodoro and reports focus time saved from the unfinished block.
+func (m *Module) StopPomodoro(ctx *tgctx.MsgContext) {
+	p, saved, err := m.pomodorosvc.Stop(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.pomodoroError(ctx, err, "stop pomodoro failed")
+		return
+	}
+
+	completed := p.Cycle
+	if p.Phase == models.PomodoroPhaseFocus {
+		completed--
+	}
+	text := fmt.Sprintf("⏹ Pomodoro stopped. Focus blocks completed: %d.", completed)
+	if saved > 0 {
+		text += fmt.Sprintf("\nUnfinished focus: %s of %s saved.", formatReportDuration(saved), activityLabel(p.ActivityEmoji, p.ActivityName))
+	}
+	if p.MessageID > 0 && p.MessageID != ctx.MessageID {
+		_, _ = m.bot.Send(tgbotapi.NewEditMessageReplyMarkup(ctx.ChatID, p.MessageID, tgbotapi.NewInlineKeyboardMarkup()))
+	}
+	m.sendOrEdit(ctx, true, text, tgbotapi.NewInlineKeyboardMarkup())
+}
+
+// SendPomodoroPhase closes countdown of finished phase and sends message of the next one,
+// so the user is notified about every break and when the next focus can be started.
+// Unreachable user is deactivated, which also stops the pomodoro.
+func (m *Module) SendPomodoroPhase(ctx context.Context, done, next models.Pomodoro) error {
+	if done.MessageID > 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(done.TgUserID, done.MessageID, track.PomodoroDoneText(done), tgbotapi.NewInlineKeyboardMarkup())
+		_, _ = m.bot.SendScheduled(edit)
+	}
+	err := m.showPomodoroCountdown(ctx, next.TgUserID, 0, next)
+	if tgclient.IsUnreachable(err) {
+		m.SetUserActive(ctx, next.TgUserID, false)
+	}
+	return err
+}
+
+// UpdatePomodoroCountdown edits countdown in place when minutes left changed since last render.
+func (m *Module) UpdatePomodoroCountdown(ctx context.Context, p models.Pomodoro, now time.Time) error {
+	if p.MessageID <= 0 || track.PomodoroMinutesLeft(p, now) == p.ShownMin {
+		return nil
+	}
+	err := m.showPomodoroCountdown(ctx, p.TgUserID, p.MessageID, p)
+	if tgclient.IsUnreachable(err) {
+		m.SetUserActive(ctx, p.TgUserID, false)
+	}
+	return err
+}
+
+// showPomodoroCountdown edits messageID into countdown of p or sends a new one when it is 0,
+// then remembers the message for live updates.
+func (m *Module) showPomodoroCountdown(ctx context.Context, chatID int64, messageID int, p models.Pomodoro) error {
+	now := time.Now()
+	text := track.PomodoroText(p, now)
+	markup := track.TrackPomodoroRunningInlineMenu(p.Phase)
+	if messageID > 0 {
+		if _, err := m.bot.SendScheduled(tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, markup)); err != nil {
+			// Remember minutes anyway, so a deleted message is retried once a minute, not every tick.
+			_ = m.pomodorosvc.SetShown(ctx, p, messageID, track.PomodoroMinutesLeft(p, now))
+			return fmt.Errorf("edit pomodoro countdown: %w", err)
+		}
+	} else {
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = markup
+		sent, err := m.bot.SendScheduled(msg)
+		if err != nil {
+			return fmt.Errorf("send pomodoro countdown: %w", err)
+		}
+		messageID = sent.MessageID
+	}
+	return m.pomodorosvc.SetShown(ctx, p, messageID, track.PomodoroMinutesLeft(p, now))
+}
+
+func (m *Module) renderPomodoroSettings(ctx *tgctx.MsgContext, inPlace bool) {
+	settings, err := m.pomodorosvc.GetSettings(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.pomodoroError(ctx, err, "get pomodoro settings failed")
+		return
+	}
+	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list activities failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load activities."))
+		return
+	}
+
+	text := track.TrackMsgPomodoroTitle + "\n\n" + track.TrackMsgPomodoroHint
+	if len(items) == 0 {
+		text += "\n\n" + track.TrackMsgPomodoroNoActivities
+	}
+	m.sendOrEdit(ctx, inPlace, text, track.TrackPomodoroInlineMenu(settings, items))
+}
+
+func (m *Module) pomodoroError(ctx *tgctx.MsgContext, err error, logMsg string) {
+	var text string
+	switch {
+	case errors.Is(err, models.ErrPomodoroRunning):
+		text = "A pomodoro is already running. Stop it first or open /pomodoro to see it."
+	case errors.Is(err, models.ErrPomodoroNotFound):
+		text = "No pomodoro is running. Start one with /pomodoro."
+	case errors.Is(err, models.ErrActivityNotFound):
+		text = "Activity not found or archived."
+	default:
+		ctx.Log.Error().Err(err).Msg(logMsg)
+		text = "⚠️ Something went wrong. Please try again."
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+}
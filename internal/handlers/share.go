@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"tracker-bot/internal/buttons/profile"
+	"tracker-bot/internal/buttons/track"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/reportcard"
+	"tracker-bot/internal/utils/tgctx"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ShareReportCard sends period report as image card; offers link when sharing is configured.
+func (m *Module) ShareReportCard(ctx *tgctx.MsgContext, from, to time.Time, activityIDs []int64) {
+	stats, err := m.tracksvc.GetPeriodReport(ctx.Ctx, ctx.DBUserID, from, to.AddDate(0, 0, 1), activityIDs)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("share report failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to build period report."))
+		return
+	}
+	stats.From, stats.To = from, to
+
+	card, err := reportcard.Render(stats, "Period report")
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("render report card failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to draw report card."))
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(ctx.ChatID, tgbotapi.FileBytes{Name: "report.png", Bytes: card})
+	photo.Caption = "Forward this card to share your progress."
+	if m.sharesvc.Enabled() {
+		photo.Caption += "\nOr create a read-only link for the same report."
+		photo.ReplyMarkup = track.TrackReportShareInlineMenu()
+	}
+	if _, err := m.bot.Send(photo); err != nil {
+		ctx.Log.Error().Err(err).Msg("send report card failed")
+	}
+}
+
+// CreateShareLink issues signed read-only link for period report.
+func (m *Module) CreateShareLink(ctx *tgctx.MsgContext, from, to time.Time, activityIDs []int64) {
+	if !m.sharesvc.Enabled() {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Links are not available."))
+		return
+	}
+	share, url, err := m.sharesvc.CreateLink(ctx.Ctx, ctx.DBUserID, from, to, activityIDs)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("create share link failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to create link."))
+		return
+	}
+
+	text := fmt.Sprintf("🔗 Read-only link #%d for %s..%s\nValid until %s UTC. Revoke it any time in 👤My account → %s.\n\n%s",
+		share.ID, from.Format("2006-01-02"), to.Format("2006-01-02"),
+		share.ExpiresAt.UTC().Format("2006-01-02 15:04"), profile.ProfileButtonShares, url)
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = track.TrackReportLinkInlineMenu(url)
+	_, _ = m.bot.Send(msg)
+}
+
+// ShowShares lists active report links with revoke buttons.
+func (m *Module) ShowShares(ctx *tgctx.MsgContext, inPlace bool) {
+	shares, err := m.sharesvc.ListActive(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list shares failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load links."))
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(profile.ProfileButtonShares + "\n\n")
+	if len(shares) == 0 {
+		b.WriteString("No active links. Create one from 📤 Share on a period report.")
+	}
+	for _, s := range shares {
+		b.WriteString(fmt.Sprintf("#%d %s..%s, expires %s UTC\n",
+			s.ID, s.From.Format("2006-01-02"), s.To.Format("2006-01-02"), s.ExpiresAt.UTC().Format("2006-01-02 15:04")))
+	}
+
+	markup := profile.ProfileSharesInlineMenu(shares)
+	if inPlace && ctx.MessageID > 0 {
+		_, _ = m.bot.Send(tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, b.String(), markup))
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, b.String())
+	msg.ReplyMarkup = markup
+	_, _ = m.bot.Send(msg)
+}
+
+// RevokeShare disables link and refreshes list.
+func (m *Module) RevokeShare(ctx *tgctx.MsgContext, id int64) {
+	if err := m.sharesvc.Revoke(ctx.Ctx, ctx.DBUserID, id); err != nil && !errors.Is(err, models.ErrShareNotFound) {
+		ctx.Log.Error().Err(err).Msg("revoke share failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to revoke link."))
+		return
+	}
+	m.ShowShares(ctx, true)
+}
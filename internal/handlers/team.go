@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"tracker-bot/internal/buttons/team"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/utils/tgctx"
+	"tracker-bot/pkg/textbuilder"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// teamPrivacyNote explains what group sees; shown in group and private chat.
+const teamPrivacyNote = "The group sees only your total time for activities you check, on /leaderboard and in weekly digests. Activity names and single sessions are never shown."
+
+// TeamAdded registers group as team and posts welcome message.
+func (m *Module) TeamAdded(ctx context.Context, chatID int64, title string) {
+	if _, err := m.teamsvc.Register(ctx, chatID, title); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int64("chat_id", chatID).Msg("register team failed")
+		return
+	}
+	text := fmt.Sprintf("👋 Hi, %s! Members can join the team to compare tracked time.\n\n"+
+		"Nothing is shared until a member opts in via /teams in private chat with me. %s\n\n"+
+		"/leaderboard — team ranking\n/team_settings — digest and leaderboard options (group admins)\n/leave — leave the team",
+		title, teamPrivacyNote)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = team.TeamJoinInlineMenu()
+	_, _ = m.bot.Send(msg)
+}
+
+// TeamRemoved deactivates team when bot leaves or is kicked from group.
+func (m *Module) TeamRemoved(ctx context.Context, chatID int64) {
+	if err := m.teamsvc.Deactivate(ctx, chatID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int64("chat_id", chatID).Msg("deactivate team failed")
+	}
+}
+
+// TeamChatMigrated keeps team when group is upgraded to supergroup.
+func (m *Module) TeamChatMigrated(ctx context.Context, fromChatID, toChatID int64) {
+	if err := m.teamsvc.MigrateChat(ctx, fromChatID, toChatID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int64("chat_id", fromChatID).Int64("to_chat_id", toChatID).Msg("migrate team chat failed")
+	}
+}
+
+// SyncTeam makes sure group has team, e.g. when bot was added before teams existed.
+func (m *Module) SyncTeam(ctx *tgctx.MsgContext, title string) bool {
+	if _, err := m.teamsvc.Register(ctx.Ctx, ctx.ChatID, title); err != nil {
+		ctx.Log.Error().Err(err).Msg("sync team failed")
+		return false
+	}
+	return true
+}
+
+// JoinTeam adds sender to group's team and sends privacy settings to private chat.
+func (m *Module) JoinTeam(ctx *tgctx.MsgContext, displayName string) {
+	t, joined, err := m.teamsvc.Join(ctx.Ctx, ctx.ChatID, ctx.DBUserID, displayName)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("join team failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to join team."))
+		return
+	}
+
+	dm := &tgctx.MsgContext{Ctx: ctx.Ctx, Log: ctx.Log, ChatID: ctx.UserID, UserID: ctx.UserID, DBUserID: ctx.DBUserID}
+	delivered := m.showTeamPrivacy(dm, t.ID, false)
+
+	text := fmt.Sprintf("✅ %s joined the team. Nothing is shared until they opt in.", displayName)
+	if !joined {
+		text = fmt.Sprintf("%s, you are already in the team.", displayName)
+	}
+	if !delivered {
+		text += "\nStart a private chat with me and send /teams to choose what to share."
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+}
+
+// LeaveGroupTeam removes sender from group's team.
+func (m *Module) LeaveGroupTeam(ctx *tgctx.MsgContext, displayName string) {
+	t, err := m.teamsvc.GetByChat(ctx.Ctx, ctx.ChatID)
+	if err == nil {
+		err = m.teamsvc.Leave(ctx.Ctx, t.ID, ctx.DBUserID)
+	}
+	switch {
+	case errors.Is(err, models.ErrNotTeamMember), errors.Is(err, models.ErrTeamNotFound):
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "You are not in the team."))
+	case err != nil:
+		ctx.Log.Error().Err(err).Msg("leave team failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to leave team."))
+	default:
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, fmt.Sprintf("👋 %s left the team.", displayName)))
+	}
+}
+
+// ShowLeaderboard posts opted-in members' totals for team's leaderboard window.
+func (m *Module) ShowLeaderboard(ctx *tgctx.MsgContext) {
+	t, err := m.teamsvc.GetByChat(ctx.Ctx, ctx.ChatID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get team failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load leaderboard."))
+		return
+	}
+	days := t.Settings.LeaderboardDays
+	to := time.Now()
+	entries, err := m.teamsvc.Leaderboard(ctx.Ctx, t.ID, to.AddDate(0, 0, -days), to)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("team leaderboard failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load leaderboard."))
+		return
+	}
+
+	title := fmt.Sprintf("🏆 %s · last %d days", t.Title, days)
+	msg := tgbotapi.NewMessage(ctx.ChatID, leaderboardText(title, entries))
+	msg.ReplyMarkup = team.TeamJoinInlineMenu()
+	_, _ = m.bot.Send(msg)
+}
+
+// ShowTeamSettings shows group settings; only group admins may open them.
+func (m *Module) ShowTeamSettings(ctx *tgctx.MsgContext) {
+	if !m.isChatAdmin(ctx) {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "Only group admins can change team settings."))
+		return
+	}
+	t, err := m.teamsvc.GetByChat(ctx.Ctx, ctx.ChatID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get team failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load team settings."))
+		return
+	}
+	m.renderTeamSettings(ctx, t, false)
+}
+
+// CycleTeamSetting switches one group setting; taps of non-admins are ignored.
+func (m *Module) CycleTeamSetting(ctx *tgctx.MsgContext, field string) {
+	if !m.isChatAdmin(ctx) {
+		return
+	}
+	t, err := m.teamsvc.GetByChat(ctx.Ctx, ctx.ChatID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get team failed")
+		return
+	}
+
+	s := t.Settings
+	switch field {
+	case team.TeamCBSetDigest:
+		s.DigestEnabled = !s.DigestEnabled
+	case team.TeamCBSetDigestDay:
+		s.DigestWeekday = nextOf(team.DigestWeekdays, s.DigestWeekday)
+	case team.TeamCBSetDigestHour:
+		s.DigestHour = nextOf(team.DigestHours, s.DigestHour)
+	case team.TeamCBSetLeaderboard:
+		s.LeaderboardDays = nextOf(team.LeaderboardDays, s.LeaderboardDays)
+	default:
+		return
+	}
+	if err := m.teamsvc.UpdateSettings(ctx.Ctx, t.ID, s); err != nil {
+		ctx.Log.Error().Err(err).Msg("update team settings failed")
+		return
+	}
+	t.Settings = s
+	m.renderTeamSettings(ctx, t, true)
+}
+
+// ShowTeams lists user's teams in private chat.
+func (m *Module) ShowTeams(ctx *tgctx.MsgContext, inPlace bool) {
+	memberships, err := m.teamsvc.ListMemberships(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list teams failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to load teams."))
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("👥 Teams\n\n")
+	if len(memberships) == 0 {
+		b.WriteString("You are not in any team yet. Add me to a group and tap «" + team.TeamButtonJoin + "» there.")
+	}
+	for _, ms := range memberships {
+		state := "not sharing"
+		if ms.Sharing {
+			state = fmt.Sprintf("sharing %d activities", len(ms.ActivityIDs))
+		}
+		b.WriteString(fmt.Sprintf("• %s — %s\n", ms.Team.Title, state))
+	}
+
+	m.sendOrEdit(ctx, inPlace, b.String(), team.TeamListInlineMenu(memberships))
+}
+
+// ShowTeamPrivacy shows what user shares with team.
+func (m *Module) ShowTeamPrivacy(ctx *tgctx.MsgContext, teamID int64, inPlace bool) {
+	m.showTeamPrivacy(ctx, teamID, inPlace)
+}
+
+// ToggleTeamSharing opts user in or out of team's leaderboard.
+func (m *Module) ToggleTeamSharing(ctx *tgctx.MsgContext, teamID int64) {
+	if _, err := m.teamsvc.ToggleSharing(ctx.Ctx, teamID, ctx.DBUserID); err != nil {
+		m.teamPrivacyError(ctx, err, "toggle team sharing failed")
+		return
+	}
+	m.showTeamPrivacy(ctx, teamID, true)
+}
+
+// ToggleTeamActivity shares or hides one activity from team.
+func (m *Module) ToggleTeamActivity(ctx *tgctx.MsgContext, teamID, activityID int64) {
+	if _, err := m.teamsvc.ToggleActivity(ctx.Ctx, teamID, ctx.DBUserID, activityID); err != nil {
+		m.teamPrivacyError(ctx, err, "toggle team activity failed")
+		return
+	}
+	m.showTeamPrivacy(ctx, teamID, true)
+}
+
+// LeaveTeam removes user from team from private chat.
+func (m *Module) LeaveTeam(ctx *tgctx.MsgContext, teamID int64) {
+	if err := m.teamsvc.Leave(ctx.Ctx, teamID, ctx.DBUserID); err != nil && !errors.Is(err, models.ErrNotTeamMember) {
+		ctx.Log.Error().Err(err).Msg("leave team failed")
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to leave team."))
+		return
+	}
+	m.ShowTeams(ctx, true)
+}
+
+// SendTeamDigest posts weekly leaderboard to group.
+func (m *Module) SendTeamDigest(ctx context.Context, due models.TeamDigestDue) error {
+	from := due.PeriodStart.AddDate(0, 0, -7)
+	entries, err := m.teamsvc.Leaderboard(ctx, due.Team.ID, from, due.PeriodStart)
+	if err != nil {
+		return fmt.Errorf("team digest leaderboard: %w", err)
+	}
+
+	var total time.Duration
+	for _, e := range entries {
+		total += e.Total
+	}
+	title := fmt.Sprintf("📬 Weekly team digest · %s\n%s..%s", due.Team.Title,
+		from.Format("2006-01-02"), due.PeriodStart.AddDate(0, 0, -1).Format("2006-01-02"))
+	text := leaderboardText(title, entries)
+	if total > 0 {
+		text += "\nTeam total: " + textbuilder.Duration(total)
+	}
+	if _, err := m.bot.Send(tgbotapi.NewMessage(due.Team.ChatID, text)); err != nil {
+		return fmt.Errorf("send team digest: %w", err)
+	}
+	return nil
+}
+
+// showTeamPrivacy renders privacy screen; false when it could not be delivered.
+func (m *Module) showTeamPrivacy(ctx *tgctx.MsgContext, teamID int64, inPlace bool) bool {
+	ms, err := m.teamsvc.GetMembership(ctx.Ctx, teamID, ctx.DBUserID)
+	if err != nil {
+		m.teamPrivacyError(ctx, err, "get team membership failed")
+		return false
+	}
+	activities, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("list activities failed")
+		return false
+	}
+
+	state := "off"
+	if ms.Sharing {
+		state = "on"
+	}
+	text := fmt.Sprintf("👥 %s\n\nSharing: %s\nShared activities: %d of %d\n\n%s",
+		ms.Team.Title, state, len(ms.ActivityIDs), len(activities), teamPrivacyNote)
+	return m.sendOrEdit(ctx, inPlace, text, team.TeamPrivacyInlineMenu(ms, activities))
+}
+
+func (m *Module) teamPrivacyError(ctx *tgctx.MsgContext, err error, logMsg string) {
+	if errors.Is(err, models.ErrNotTeamMember) {
+		m.ShowTeams(ctx, true)
+		return
+	}
+	ctx.Log.Error().Err(err).Msg(logMsg)
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "⚠️ Failed to update team settings."))
+}
+
+func (m *Module) renderTeamSettings(ctx *tgctx.MsgContext, t models.Team, inPlace bool) {
+	s := t.Settings
+	digest := "off"
+	if s.DigestEnabled {
+		digest = fmt.Sprintf("%s %02d:00 UTC", s.DigestWeekday, s.DigestHour)
+	}
+	text := fmt.Sprintf("⚙️ Team settings · %s\n\nWeekly digest: %s\nLeaderboard window: %d days",
+		t.Title, digest, s.LeaderboardDays)
+	m.sendOrEdit(ctx, inPlace, text, team.TeamSettingsInlineMenu(s))
+}
+
+// sendOrEdit edits message in place when possible, otherwise sends new one.
+func (m *Module) sendOrEdit(ctx *tgctx.MsgContext, inPlace bool, text string, markup tgbotapi.InlineKeyboardMarkup) bool {
+	if inPlace && ctx.MessageID > 0 {
+		_, err := m.bot.Send(tgbotapi.NewEditMessageTextAndMarkup(ctx.ChatID, ctx.MessageID, text, markup))
+		return err == nil
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, text)
+	msg.ReplyMarkup = markup
+	if _, err := m.bot.Send(msg); err != nil {
+		ctx.Log.Warn().Err(err).Int64("to_chat_id", ctx.ChatID).Msg("send message failed")
+		return false
+	}
+	return true
+}
+
+// isChatAdmin checks sender's status in current group via getChatMember.
+func (m *Module) isChatAdmin(ctx *tgctx.MsgContext) bool {
+	resp, err := m.bot.Request(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: ctx.ChatID, UserID: ctx.UserID},
+	})
+	if err != nil {
+		ctx.Log.Error().Err(err).Msg("get chat member failed")
+		return false
+	}
+	var member tgbotapi.ChatMember
+	if err := json.Unmarshal(resp.Result, &member); err != nil {
+		ctx.Log.Error().Err(err).Msg("decode chat member failed")
+		return false
+	}
+	return member.IsCreator() || member.IsAdministrator()
+}
+
+// leaderboardText formats ranking; names only, no activity breakdown.
+func leaderboardText(title string, entries []models.LeaderboardEntry) string {
+	var b strings.Builder
+	b.WriteString(title + "\n\n")
+	if len(entries) == 0 {
+		b.WriteString("No one shares their time yet. Join the team and opt in via /teams in private chat.\n")
+		return b.String()
+	}
+	medals := []string{"🥇", "🥈", "🥉"}
+	for i, e := range entries {
+		place := fmt.Sprintf("%d.", i+1)
+		if i < len(medals) {
+			place = medals[i]
+		}
+		b.WriteString(fmt.Sprintf("%s %s — %s\n", place, e.Name, textbuilder.Duration(e.Total)))
+	}
+	b.WriteString("\nOnly members who opted in are shown.\n")
+	return b.String()
+}
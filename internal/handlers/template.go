@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"tracker-bot/internal/buttons/track"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/service"
+	"tracker-bot/internal/utils/tgctx"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ShowTemplates renders built-in packs and template export/import actions.
+func (m *Module) ShowTemplates(ctx *tgctx.MsgContext, inPlace bool) {
+	text := track.TrackMsgTemplatesTitle + "\n\n" + track.TrackMsgTemplatesHint
+	m.sendOrEdit(ctx, inPlace, text, track.TrackTemplatesInlineMenu(service.ActivityPacks))
+}
+
+// ShowTemplatePack previews pack and marks activities user already has.
+func (m *Module) ShowTemplatePack(ctx *tgctx.MsgContext, key string) {
+	pack, err := m.tracksvc.ActivityPack(key)
+	if err != nil {
+		m.templateError(ctx, err, "get activity pack failed")
+		return
+	}
+	existing, ok := m.activityNames(ctx)
+	if !ok {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s\n\n", pack.Name))
+	missing := 0
+	for _, t := range pack.Activities {
+		mark := "✅"
+		if !existing[strings.ToLower(t.Name)] {
+			mark = "➕"
+			missing++
+		}
+		b.WriteString(fmt.Sprintf("%s %s %s\n", mark, t.Emoji, t.Name))
+	}
+	b.WriteString("\n" + track.TrackMsgTemplatePackHint)
+	m.sendOrEdit(ctx, true, b.String(), track.TrackTemplatePackInlineMenu(pack.Key, missing))
+}
+
+// AddTemplatePack creates all pack activities user does not have yet.
+func (m *Module) AddTemplatePack(ctx *tgctx.MsgContext, key string) {
+	pack, err := m.tracksvc.ActivityPack(key)
+	if err != nil {
+		m.templateError(ctx, err, "get activity pack failed")
+		return
+	}
+	res, err := m.tracksvc.CreateActivities(ctx.Ctx, ctx.DBUserID, pack.Activities)
+	if err != nil {
+		m.templateError(ctx, err, "create pack activities failed")
+		return
+	}
+	m.sendOrEdit(ctx, true, bulkCreateText(res), track.TrackTemplatePackInlineMenu(pack.Key, 0))
+}
+
+// ExportTemplate sends user's active activities as JSON template to copy.
+func (m *Module) ExportTemplate(ctx *tgctx.MsgContext) {
+	items, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.templateError(ctx, err, "list activities failed")
+		return
+	}
+	if len(items) == 0 {
+		_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, "No activities to export yet."))
+		return
+	}
+	doc, err := m.tracksvc.ExportActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.templateError(ctx, err, "export activities failed")
+		return
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, html.EscapeString(track.TrackMsgTemplateExported)+"\n\n<pre>"+html.EscapeString(string(doc))+"</pre>")
+	msg.ParseMode = "HTML"
+	if _, err := m.bot.Send(msg); err != nil {
+		ctx.Log.Error().Err(err).Msg("send template export failed")
+	}
+}
+
+// PromptImportTemplate asks user to paste template document.
+func (m *Module) PromptImportTemplate(ctx *tgctx.MsgContext) {
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, track.TrackMsgTemplateImportPrompt))
+}
+
+// ProcessImportTemplate creates activities from pasted JSON/YAML template; false keeps waiting for valid input.
+func (m *Module) ProcessImportTemplate(ctx *tgctx.MsgContext) bool {
+	pack, err := m.tracksvc.ParseActivityPack([]byte(ctx.Text))
+	if err != nil {
+		m.templateError(ctx, err, "parse template failed")
+		return false
+	}
+	res, err := m.tracksvc.CreateActivities(ctx.Ctx, ctx.DBUserID, pack.Activities)
+	if err != nil {
+		m.templateError(ctx, err, "import template failed")
+		return true
+	}
+	msg := tgbotapi.NewMessage(ctx.ChatID, bulkCreateText(res))
+	msg.ReplyMarkup = track.TrackCreateSuccessInlineMenu()
+	_, _ = m.bot.Send(msg)
+	return true
+}
+
+// activityNames returns lower-cased names of active and archived activities;
+// both block creating another activity with the same name.
+func (m *Module) activityNames(ctx *tgctx.MsgContext) (map[string]bool, bool) {
+	active, err := m.tracksvc.ListActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.templateError(ctx, err, "list activities failed")
+		return nil, false
+	}
+	archived, err := m.tracksvc.ListArchivedActivities(ctx.Ctx, ctx.DBUserID)
+	if err != nil {
+		m.templateError(ctx, err, "list archived activities failed")
+		return nil, false
+	}
+	names := make(map[string]bool, len(active)+len(archived))
+	for _, it := range active {
+		names[strings.ToLower(it.Name)] = true
+	}
+	for _, it := range archived {
+		names[strings.ToLower(it.Name)] = true
+	}
+	return names, true
+}
+
+// bulkCreateText summarizes created and skipped names.
+func bulkCreateText(res models.BulkCreateResult) string {
+	var b strings.Builder
+	if len(res.Created) == 0 {
+		b.WriteString("Nothing new to add.")
+	} else {
+		b.WriteString(fmt.Sprintf("📦 Added %d: %s", len(res.Created), strings.Join(res.Created, ", ")))
+	}
+	if len(res.Skipped) > 0 {
+		b.WriteString(fmt.Sprintf("\nSkipped %d already in your list or archive: %s", len(res.Skipped), strings.Join(res.Skipped, ", ")))
+	}
+	return b.String()
+}
+
+func (m *Module) templateError(ctx *tgctx.MsgContext, err error, logMsg string) {
+	var text string
+	switch {
+	case errors.Is(err, models.ErrInvalidTemplate):
+		text = track.TrackMsgTemplateInvalid
+	case errors.Is(err, models.ErrTemplateTooLarge):
+		text = track.TrackMsgTemplateTooLarge
+	case errors.Is(err, models.ErrPackNotFound):
+		text = "This pack is no longer available."
+	default:
+		ctx.Log.Error().Err(err).Msg(logMsg)
+		text = "⚠️ Something went wrong. Please try again."
+	}
+	_, _ = m.bot.Send(tgbotapi.NewMessage(ctx.ChatID, text))
+}
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"tracker-bot/internal/utils/tgclient"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tracker"
+
+// Registry holds all bot metrics; it is served by the admin HTTP server.
+var Registry = prometheus.NewRegistry()
+
+var (
+	updatesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_total",
+		Help:      "Telegram updates received, by update type.",
+	}, []string{"type"})
+
+	updateDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "update_duration_seconds",
+		Help:      "Time spent handling one update, by update type.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"type"})
+
+	schedulerLag = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scheduler_lag_seconds",
+		Help:      "Delay between prompt due time (next_ping_at) and its delivery.",
+		Buckets:   []float64{.1, .5, 1, 2, 5, 10, 30, 60, 120, 300},
+	})
+
+	promptsSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "prompts_sent_total",
+		Help:      "Timer prompts delivered to users.",
+	})
+
+	promptsAnswered = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "prompts_answered_total",
+		Help:      "Timer prompts answered with an activity.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		updatesTotal,
+		updateDuration,
+		schedulerLag,
+		promptsSent,
+		promptsAnswered,
+	)
+}
+
+// Handler serves Registry in Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveUpdate counts one handled update and its handling time.
+func ObserveUpdate(updateType string, took time.Duration) {
+	updatesTotal.WithLabelValues(updateType).Inc()
+	updateDuration.WithLabelValues(updateType).Observe(took.Seconds())
+}
+
+// PromptSent records delivered prompt and how late it was relative to due time.
+func PromptSent(dueAt, sentAt time.Time) {
+	promptsSent.Inc()
+	schedulerLag.Observe(max(sentAt.Sub(dueAt), 0).Seconds())
+}
+
+// PromptAnswered records one answered prompt.
+func PromptAnswered() {
+	promptsAnswered.Inc()
+}
+
+// RegisterSender exposes Telegram sender counters.
+func RegisterSender(s *tgclient.Sender) {
+	Registry.MustRegister(&senderCollector{sender: s})
+}
+
+// RegisterPool exposes pgxpool statistics.
+func RegisterPool(pool *pgxpool.Pool) {
+	Registry.MustRegister(&poolCollector{pool: pool})
+}
+
+var (
+	senderSentDesc      = prometheus.NewDesc(namespace+"_telegram_sent_total", "Telegram requests completed successfully.", nil, nil)
+	senderFailedDesc    = prometheus.NewDesc(namespace+"_telegram_failed_total", "Telegram requests failed after retries.", nil, nil)
+	senderRetriedDesc   = prometheus.NewDesc(namespace+"_telegram_retried_total", "Telegram requests retried after 429.", nil, nil)
+	senderDroppedDesc   = prometheus.NewDesc(namespace+"_telegram_dropped_total", "Scheduled messages dropped without a send slot.", nil, nil)
+	senderThrottledDesc = prometheus.NewDesc(namespace+"_telegram_throttled_total", "Requests that waited for rate limiter.", nil, nil)
+	senderErrorsDesc    = prometheus.NewDesc(namespace+"_telegram_errors_total", "Telegram API errors by error code.", []string{"code"}, nil)
+)
+
+// senderCollector reads Sender.Stats on scrape.
+type senderCollector struct {
+	sender *tgclient.Sender
+}
+
+func (c *senderCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- senderSentDesc
+	ch <- senderFailedDesc
+	ch <- senderRetriedDesc
+	ch <- senderDroppedDesc
+	ch <- senderThrottledDesc
+	ch <- senderErrorsDesc
+}
+
+func (c *senderCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.sender.Stats()
+	ch <- prometheus.MustNewConstMetric(senderSentDesc, prometheus.CounterValue, float64(st.Sent))
+	ch <- prometheus.MustNewConstMetric(senderFailedDesc, prometheus.CounterValue, float64(st.Failed))
+	ch <- prometheus.MustNewConstMetric(senderRetriedDesc, prometheus.CounterValue, float64(st.Retried))
+	ch <- prometheus.MustNewConstMetric(senderDroppedDesc, prometheus.CounterValue, float64(st.Dropped))
+	ch <- prometheus.MustNewConstMetric(senderThrottledDesc, prometheus.CounterValue, float64(st.Throttled))
+	for code, n := range st.ErrorsByAPI {
+		ch <- prometheus.MustNewConstMetric(senderErrorsDesc, prometheus.CounterValue, float64(n), strconv.Itoa(code))
+	}
+}
+
+var (
+	poolTotalDesc        = prometheus.NewDesc(namespace+"_pgxpool_total_conns", "Connections currently in pool.", nil, nil)
+	poolAcquiredDesc     = prometheus.NewDesc(namespace+"_pgxpool_acquired_conns", "Connections currently acquired.", nil, nil)
+	poolIdleDesc         = prometheus.NewDesc(namespace+"_pgxpool_idle_conns", "Idle connections in pool.", nil, nil)
+	poolMaxDesc          = prometheus.NewDesc(namespace+"_pgxpool_max_conns", "Maximum pool size.", nil, nil)
+	poolAcquiresDesc     = prometheus.NewDesc(namespace+"_pgxpool_acquires_total", "Successful connection acquires.", nil, nil)
+	poolEmptyAcquireDesc = prometheus.NewDesc(namespace+"_pgxpool_empty_acquires_total", "Acquires that waited because pool was empty.", nil, nil)
+	poolAcquireTimeDesc  = prometheus.NewDesc(namespace+"_pgxpool_acquire_seconds_total", "Total time spent acquiring connections.", nil, nil)
+)
+
+// poolCollector reads pgxpool.Stat on scrape.
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolTotalDesc
+	ch <- poolAcquiredDesc
+	ch <- poolIdleDesc
+	ch <- poolMaxDesc
+	ch <- poolAcquiresDesc
+	ch <- poolEmptyAcquireDesc
+	ch <- poolAcquireTimeDesc
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolTotalDesc, prometheus.GaugeValue, float64(st.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquiredDesc, prometheus.GaugeValue, float64(st.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleDesc, prometheus.GaugeValue, float64(st.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxDesc, prometheus.GaugeValue, float64(st.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquiresDesc, prometheus.CounterValue, float64(st.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquireDesc, prometheus.CounterValue, float64(st.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireTimeDesc, prometheus.CounterValue, st.AcquireDuration().Seconds())
+}
@@ -28,6 +28,8 @@ type MainStats struct {
 	TodayTracked        time.Duration
 	TodaySessions       int
 	StreakDays          int
+	StreakByGoal        bool
+	Goals               []GoalProgress
 }
 
 // TrackActivityItem is an activity row used in selection UIs.
@@ -43,6 +45,51 @@ type TimerDueUser struct {
 	DBUserID    int64
 	TgUserID    int64
 	IntervalMin int
+	ScheduledAt time.Time
+	TimeZone    string
+	Quiet       QuietHours
+}
+
+// QuietHours is local window without prompts in minutes after midnight; it may wrap past midnight.
+// Zero value (From == To) means no quiet hours.
+type QuietHours struct {
+	FromMin int
+	ToMin   int
+}
+
+// Enabled reports whether window is set.
+func (q QuietHours) Enabled() bool {
+	return q.FromMin != q.ToMin
+}
+
+// TimerSettings is user's prompt configuration.
+type TimerSettings struct {
+	IntervalMin int
+	Enabled     bool
+	Quiet       QuietHours
+	Adaptive    AdaptiveInterval
+}
+
+// AdaptiveInterval bounds automatic interval changes made from prompt answers.
+type AdaptiveInterval struct {
+	Enabled bool
+	MinMin  int
+	MaxMin  int
+}
+
+// AdaptiveState is prompt answer history the next interval is derived from.
+type AdaptiveState struct {
+	IntervalMin      int
+	Enabled          bool
+	Adaptive         AdaptiveInterval
+	LastActivityID   int64
+	SameAnswerStreak int
+}
+
+// TimerSchedule is next prompt time of one enabled timer.
+type TimerSchedule struct {
+	DBUserID   int64
+	NextPingAt time.Time
 }
 
 // ActivityDurationStat is one activity aggregate line in reports.
@@ -54,11 +101,74 @@ type ActivityDurationStat struct {
 	Sessions   int
 }
 
+// Goal periods and kinds.
+const (
+	GoalPeriodDay  = "day"
+	GoalPeriodWeek = "week"
+
+	GoalKindMin = "min"
+	GoalKindMax = "max"
+)
+
+// Goal is a per-activity time target for a day or a week.
+// Kind "min" means "at least", kind "max" means "no more than".
+type Goal struct {
+	ID           int64
+	ActivityID   int64
+	ActivityName string
+	Emoji        string
+	Period       string
+	Kind         string
+	Target       time.Duration
+	UseForStreak bool
+}
+
+// GoalProgress is a goal with time tracked in the current period.
+type GoalProgress struct {
+	Goal    Goal
+	Tracked time.Duration
+}
+
+// Met reports whether tracked time satisfies the goal.
+func (p GoalProgress) Met() bool {
+	if p.Goal.Kind == GoalKindMax {
+		return p.Tracked <= p.Goal.Target
+	}
+	return p.Tracked >= p.Goal.Target
+}
+
+// GoalCompletion is a goal summary over a report range.
+type GoalCompletion struct {
+	Goal         Goal
+	PeriodsMet   int
+	PeriodsTotal int
+}
+
+// Tag is a user category for activities; ParentID is 0 for top-level tags.
+type Tag struct {
+	ID       int64
+	ParentID int64
+	Name     string
+	Path     string
+}
+
+// TagDurationStat is one tag aggregate line in reports.
+// Duration includes activities of all descendant tags.
+type TagDurationStat struct {
+	TagID    int64
+	Path     string
+	Duration time.Duration
+	Sessions int
+}
+
 // ReportTodayStats is a daily aggregate report.
 type ReportTodayStats struct {
 	TotalTracked  time.Duration
 	TotalSessions int
 	TopActivities []ActivityDurationStat
+	Goals         []GoalProgress
+	// Pomodoros counts focus blocks completed today.
+	Pomodoros int
 }
 
 // ReportPeriodStats is an aggregate report for arbitrary date range.
@@ -69,6 +179,10 @@ type ReportPeriodStats struct {
 	TotalSessions int
 	Activities    []ActivityDurationStat
 	Monthly       []MonthDurationStat
+	Goals         []GoalCompletion
+	Tags          []TagDurationStat
+	// Pomodoros counts completed focus blocks per day; days without any are omitted.
+	Pomodoros []DayCountStat
 }
 
 // MonthDurationStat stores total duration for one month bucket.
@@ -77,6 +191,35 @@ type MonthDurationStat struct {
 	Duration time.Duration
 }
 
+// Digest kinds.
+const (
+	DigestKindDaily   = "daily"
+	DigestKindWeekly  = "weekly"
+	DigestKindMonthly = "monthly"
+)
+
+// DigestSettings stores user's opt-in for automatic report digests.
+type DigestSettings struct {
+	UserID    int64
+	TgUserID  int64
+	TimeZone  string
+	Daily     bool
+	Weekly    bool
+	Monthly   bool
+	SendAtMin int
+}
+
+// DigestDue is one digest that should be delivered now.
+// PeriodStart identifies the period; [From, To) is the reported range.
+type DigestDue struct {
+	UserID      int64
+	TgUserID    int64
+	Kind        string
+	PeriodStart time.Time
+	From        time.Time
+	To          time.Time
+}
+
 // LearningStats contains values for learning dashboard.
 type LearningStats struct {
 	Language     string
@@ -91,3 +234,257 @@ type SubscriptionStats struct {
 	ActivePlan string
 	DaysEnd    int
 }
+
+// AdminStats is an operator overview of the whole bot.
+type AdminStats struct {
+	Users          int
+	InactiveUsers  int
+	ActiveTimers   int
+	SessionsToday  int
+	TrackedToday   time.Duration
+	ActiveUsers24h int
+}
+
+// AdminUserInfo is an operator view of one user.
+type AdminUserInfo struct {
+	DBUserID      int64
+	TgUserID      int64
+	UserName      string
+	Language      string
+	TimeZone      string
+	IsActive      bool
+	CreatedAt     time.Time
+	TimerEnabled  bool
+	IntervalMin   int
+	NextPingAt    *time.Time
+	Activities    int
+	Sessions      int
+	LastSessionAt *time.Time
+}
+
+// TimerBacklog describes enabled timers that are due but not yet claimed.
+type TimerBacklog struct {
+	Enabled  int
+	Overdue  int
+	Oldest   *time.Time
+	Upcoming []TimerSchedule
+}
+
+// Broadcast statuses.
+const (
+	BroadcastStatusDraft     = "draft"
+	BroadcastStatusScheduled = "scheduled"
+	BroadcastStatusSending   = "sending"
+	BroadcastStatusDone      = "done"
+	BroadcastStatusCancelled = "cancelled"
+)
+
+// Broadcast recipient statuses.
+const (
+	RecipientStatusPending = "pending"
+	RecipientStatusSending = "sending"
+	RecipientStatusSent    = "sent"
+	RecipientStatusFailed  = "failed"
+	RecipientStatusBlocked = "blocked"
+)
+
+// BroadcastButton is one URL button under announcement.
+type BroadcastButton struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// BroadcastSegment selects recipients; zero values mean "any".
+type BroadcastSegment struct {
+	Language   string
+	Plan       string
+	ActiveDays int
+}
+
+// Broadcast is an operator announcement with its targeting and state.
+type Broadcast struct {
+	ID          int64
+	CreatedBy   int64
+	Text        string
+	PhotoFileID string
+	Buttons     []BroadcastButton
+	Segment     BroadcastSegment
+	Status      string
+	ScheduledAt *time.Time
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
+
+// BroadcastRecipient is one queued delivery.
+type BroadcastRecipient struct {
+	BroadcastID int64
+	UserID      int64
+	ChatID      int64
+}
+
+// BroadcastStats counts recipients by delivery status.
+type BroadcastStats struct {
+	Pending int
+	Sent    int
+	Failed  int
+	Blocked int
+}
+
+// ReportShare is a read-only link to user's period report.
+// From and To are local midnights of the first and last day in TimeZone.
+type ReportShare struct {
+	ID          int64
+	UserID      int64
+	From        time.Time
+	To          time.Time
+	TimeZone    string
+	ActivityIDs []int64
+	ExpiresAt   time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// TeamSettings are per-group options changed by group admins.
+type TeamSettings struct {
+	DigestEnabled bool
+	// DigestWeekday and DigestHour set weekly digest time in UTC.
+	DigestWeekday   time.Weekday
+	DigestHour      int
+	LeaderboardDays int
+}
+
+// Team is a group chat the bot was added to.
+type Team struct {
+	ID           int64
+	ChatID       int64
+	Title        string
+	Active       bool
+	Settings     TeamSettings
+	LastDigestAt *time.Time
+	CreatedAt    time.Time
+}
+
+// TeamMembership is user's membership with privacy choices.
+type TeamMembership struct {
+	Team        Team
+	DisplayName string
+	Sharing     bool
+	ActivityIDs []int64
+}
+
+// LeaderboardEntry is one opted-in member's total over shared activities.
+type LeaderboardEntry struct {
+	UserID   int64
+	Name     string
+	Total    time.Duration
+	Sessions int
+}
+
+// TeamDigestDue is weekly team digest that should be sent now.
+type TeamDigestDue struct {
+	Team        Team
+	PeriodStart time.Time
+}
+
+// PartnerInvite is pending invitation to become accountability partners.
+type PartnerInvite struct {
+	Token       string
+	UserID      int64
+	InviterName string
+	ExpiresAt   time.Time
+}
+
+// Partner is the other side of user's partnership.
+type Partner struct {
+	PartnershipID int64
+	UserID        int64
+	TgUserID      int64
+	Name          string
+	TimeZone      string
+	// ActivityIDs are viewer's own activities shared with this partner.
+	ActivityIDs []int64
+	Since       time.Time
+}
+
+// PartnerProgress is what viewer sees about partner.
+type PartnerProgress struct {
+	Partner      Partner
+	Goals        []GoalProgress
+	TrackedToday bool
+}
+
+// Onboarding wizard steps stored in users.onboarding_step, in order.
+const (
+	OnboardingStepLanguage   = "language"
+	OnboardingStepTimeZone   = "timezone"
+	OnboardingStepActivities = "activities"
+	OnboardingStepInterval   = "interval"
+	OnboardingStepQuiet      = "quiet"
+	OnboardingStepTimer      = "timer"
+	OnboardingStepDone       = "done"
+)
+
+// ActivityTemplate is suggested activity user can add with one tap.
+type ActivityTemplate struct {
+	Name  string `json:"name" yaml:"name"`
+	Emoji string `json:"emoji,omitempty" yaml:"emoji,omitempty"`
+}
+
+// ActivityPack is named set of activity templates: built-in pack or user's exported activities.
+type ActivityPack struct {
+	Key        string             `json:"-" yaml:"-"`
+	Name       string             `json:"name" yaml:"name"`
+	Activities []ActivityTemplate `json:"activities" yaml:"activities"`
+}
+
+// BulkCreateResult reports names created and names skipped because they already exist.
+type BulkCreateResult struct {
+	Created []string
+	Skipped []string
+}
+
+// Pomodoro phases stored in pomodoros.phase; waiting follows a break until the user starts next focus.
+const (
+	PomodoroPhaseFocus      = "focus"
+	PomodoroPhaseShortBreak = "short_break"
+	PomodoroPhaseLongBreak  = "long_break"
+	PomodoroPhaseWaiting    = "waiting"
+)
+
+// Session sources written by pomodoro: full focus blocks and focus stopped early.
+const (
+	SessionSourcePomodoro        = "pomodoro"
+	SessionSourcePomodoroPartial = "pomodoro_partial"
+)
+
+// PomodoroSettings stores user's phase lengths in minutes.
+type PomodoroSettings struct {
+	FocusMin       int
+	ShortBreakMin  int
+	LongBreakMin   int
+	LongBreakEvery int
+}
+
+// Pomodoro is user's running focus/break cycle.
+type Pomodoro struct {
+	UserID        int64
+	TgUserID      int64
+	ActivityID    int64
+	ActivityName  string
+	ActivityEmoji string
+	Phase         string
+	// Cycle is number of the current or last focus block, starting at 1.
+	Cycle          int
+	PhaseStartedAt time.Time
+	PhaseEndsAt    time.Time
+	MessageID      int
+	ShownMin       int
+	Settings       PomodoroSettings
+}
+
+// DayCountStat stores count for one day bucket.
+type DayCountStat struct {
+	Day   time.Time
+	Count int
+}
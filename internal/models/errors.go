@@ -4,9 +4,50 @@ import "errors"
 
 var (
 	// Activity domain errors.
-	ErrActivityExists   = errors.New("activity already exists")
-	ErrActivityNotFound = errors.New("activity not found")
-	ErrForbidden        = errors.New("forbidden")
+	ErrActivityExists     = errors.New("activity already exists")
+	ErrActivityNotFound   = errors.New("activity not found")
+	ErrForbidden          = errors.New("forbidden")
+	ErrSameActivity       = errors.New("cannot merge activity into itself")
+	ErrInvalidEmoji       = errors.New("invalid emoji")
+	ErrActivityHasHistory = errors.New("activity has tracked history")
+	ErrUndoExpired        = errors.New("undo period expired")
+
+	// Activity template errors.
+	ErrPackNotFound     = errors.New("activity pack not found")
+	ErrInvalidTemplate  = errors.New("invalid activity template")
+	ErrTemplateTooLarge = errors.New("too many activities in template")
+
+	// Goal domain errors.
+	ErrGoalNotFound = errors.New("goal not found")
+	ErrInvalidGoal  = errors.New("invalid goal")
+
+	// Tag domain errors.
+	ErrTagExists   = errors.New("tag already exists")
+	ErrTagNotFound = errors.New("tag not found")
+
+	// Broadcast domain errors.
+	ErrBroadcastNotFound = errors.New("broadcast not found")
+	ErrInvalidBroadcast  = errors.New("invalid broadcast")
+
+	// Report share domain errors.
+	ErrShareNotFound = errors.New("share link not found")
+	ErrShareExpired  = errors.New("share link expired or revoked")
+
+	// Team domain errors.
+	ErrTeamNotFound  = errors.New("team not found")
+	ErrNotTeamMember = errors.New("not a team member")
+
+	// Partner domain errors.
+	ErrInviteNotFound  = errors.New("invite not found or expired")
+	ErrOwnInvite       = errors.New("cannot accept own invite")
+	ErrAlreadyPartners = errors.New("already partners")
+	ErrPartnerNotFound = errors.New("partner not found")
+	ErrPartnerTracked  = errors.New("partner already tracked time today")
+	ErrAlreadyNudged   = errors.New("partner already nudged today")
+
+	// Pomodoro domain errors.
+	ErrPomodoroRunning  = errors.New("pomodoro already running")
+	ErrPomodoroNotFound = errors.New("pomodoro not running")
 
 	// User domain errors.
 	ErrUserExists   = errors.New("user already exists")
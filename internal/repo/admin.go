@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AdminRepository runs cross-user queries for operator commands.
+type AdminRepository interface {
+	Stats(ctx context.Context, dayStart time.Time) (models.AdminStats, error)
+	GetUserInfo(ctx context.Context, tgUserID int64) (models.AdminUserInfo, error)
+	TimerBacklog(ctx context.Context, now time.Time, limit int) (models.TimerBacklog, error)
+}
+
+type adminRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAdminRepository creates repository backed by pgx pool.
+func NewAdminRepository(db *pgxpool.Pool) AdminRepository {
+	return &adminRepository{db: db}
+}
+
+// Stats counts users, enabled timers and sessions started since dayStart.
+func (r *adminRepository) Stats(ctx context.Context, dayStart time.Time) (models.AdminStats, error) {
+	q := `
+	SELECT
+		(SELECT count(*) FROM users),
+		(SELECT count(*) FROM users WHERE is_active = FALSE),
+		(SELECT count(*) FROM user_timer_settings WHERE enabled = TRUE),
+		(SELECT count(*) FROM activity_sessions WHERE start_at >= $1),
+		(SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (end_at - start_at))), 0)::bigint
+		   FROM activity_sessions WHERE start_at >= $1 AND end_at IS NOT NULL),
+		(SELECT count(DISTINCT user_id) FROM activity_sessions WHERE start_at >= now() - interval '24 hours');
+	`
+	var st models.AdminStats
+	var trackedSec int64
+	err := r.db.QueryRow(ctx, q, dayStart).Scan(
+		&st.Users, &st.InactiveUsers, &st.ActiveTimers, &st.SessionsToday, &trackedSec, &st.ActiveUsers24h,
+	)
+	if err != nil {
+		return models.AdminStats{}, fmt.Errorf("admin stats: %w", err)
+	}
+	st.TrackedToday = time.Duration(trackedSec) * time.Second
+	return st, nil
+}
+
+// GetUserInfo loads profile, timer and activity counters of user by Telegram id.
+func (r *adminRepository) GetUserInfo(ctx context.Context, tgUserID int64) (models.AdminUserInfo, error) {
+	if tgUserID <= 0 {
+		return models.AdminUserInfo{}, fmt.Errorf("admin user info: invalid tgUserID")
+	}
+	q := `
+	SELECT u.id, u.tg_user_id, COALESCE(u.username::text, ''), u.language, u.timezone, u.is_active, u.created_at,
+	       COALESCE(t.enabled, FALSE), COALESCE(t.interval_min, 0), t.next_ping_at,
+	       (SELECT count(*) FROM activities a WHERE a.user_id = u.id AND a.deleted_at IS NULL),
+	       (SELECT count(*) FROM activity_sessions s WHERE s.user_id = u.id),
+	       (SELECT max(s.start_at) FROM activity_sessions s WHERE s.user_id = u.id)
+	FROM users u
+	LEFT JOIN user_timer_settings t ON t.user_id = u.id
+	WHERE u.tg_user_id = $1;
+	`
+	var info models.AdminUserInfo
+	err := r.db.QueryRow(ctx, q, tgUserID).Scan(
+		&info.DBUserID, &info.TgUserID, &info.UserName, &info.Language, &info.TimeZone, &info.IsActive, &info.CreatedAt,
+		&info.TimerEnabled, &info.IntervalMin, &info.NextPingAt,
+		&info.Activities, &info.Sessions, &info.LastSessionAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.AdminUserInfo{}, models.ErrUserNotFound
+		}
+		return models.AdminUserInfo{}, fmt.Errorf("admin user info: %w", err)
+	}
+	return info, nil
+}
+
+// TimerBacklog counts enabled and overdue timers and returns the nearest ones.
+func (r *adminRepository) TimerBacklog(ctx context.Context, now time.Time, limit int) (models.TimerBacklog, error) {
+	q := `
+	SELECT count(*),
+	       count(*) FILTER (WHERE next_ping_at <= $1),
+	       min(next_ping_at) FILTER (WHERE next_ping_at <= $1)
+	FROM user_timer_settings
+	WHERE enabled = TRUE AND next_ping_at IS NOT NULL;
+	`
+	var b models.TimerBacklog
+	if err := r.db.QueryRow(ctx, q, now).Scan(&b.Enabled, &b.Overdue, &b.Oldest); err != nil {
+		return models.TimerBacklog{}, fmt.Errorf("timer backlog: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+	SELECT user_id, next_ping_at
+	FROM user_timer_settings
+	WHERE enabled = TRUE AND next_ping_at IS NOT NULL
+	ORDER BY next_ping_at
+	LIMIT $1;
+	`, limit)
+	if err != nil {
+		return models.TimerBacklog{}, fmt.Errorf("timer backlog upcoming: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.TimerSchedule
+		if err := rows.Scan(&item.DBUserID, &item.NextPingAt); err != nil {
+			return models.TimerBacklog{}, fmt.Errorf("timer backlog scan: %w", err)
+		}
+		b.Upcoming = append(b.Upcoming, item)
+	}
+	if err := rows.Err(); err != nil {
+		return models.TimerBacklog{}, fmt.Errorf("timer backlog rows: %w", err)
+	}
+	return b, nil
+}
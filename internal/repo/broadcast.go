@@ -0,0 +1,327 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BroadcastRepository stores announcements and their delivery queue.
+type BroadcastRepository interface {
+	Create(ctx context.Context, b models.Broadcast) (int64, error)
+	Get(ctx context.Context, id int64) (models.Broadcast, error)
+	ListRecent(ctx context.Context, limit int) ([]models.Broadcast, error)
+	UpdateSegment(ctx context.Context, id int64, seg models.BroadcastSegment) error
+	CountSegment(ctx context.Context, seg models.BroadcastSegment) (int, error)
+	// Schedule moves draft to scheduled state.
+	Schedule(ctx context.Context, id int64, at time.Time) error
+	Cancel(ctx context.Context, id int64) error
+	// ListDue returns scheduled broadcasts whose time has come and the ones already sending.
+	ListDue(ctx context.Context, now time.Time) ([]models.Broadcast, error)
+	// StartSending switches scheduled broadcast to sending and enqueues matching users once.
+	StartSending(ctx context.Context, id int64) error
+	// ClaimRecipients marks next pending recipients as sending; rows stuck in sending
+	// since before staleBefore are retried.
+	ClaimRecipients(ctx context.Context, id int64, limit int, staleBefore time.Time) ([]models.BroadcastRecipient, error)
+	MarkRecipient(ctx context.Context, r models.BroadcastRecipient, status, errText string) error
+	// FinishIfDone marks sending broadcast as done when queue is empty.
+	FinishIfDone(ctx context.Context, id int64) (bool, error)
+	Stats(ctx context.Context, id int64) (models.BroadcastStats, error)
+}
+
+type broadcastRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewBroadcastRepository creates repository backed by pgx pool.
+func NewBroadcastRepository(db *pgxpool.Pool) BroadcastRepository {
+	return &broadcastRepository{db: db}
+}
+
+const broadcastColumns = `
+	id, created_by, text, COALESCE(photo_file_id, ''), buttons,
+	COALESCE(segment_language, ''), COALESCE(segment_plan, ''), segment_active_days,
+	status, scheduled_at, created_at, started_at, finished_at`
+
+// segmentFilter matches users by segment parameters $1 language, $2 plan, $3 active days.
+const segmentFilter = `
+	u.is_active = TRUE
+	AND ($1 = '' OR u.language = $1)
+	AND ($2 = '' OR u.plan = $2)
+	AND ($3 = 0 OR EXISTS (
+		SELECT 1 FROM activity_sessions s
+		WHERE s.user_id = u.id AND s.start_at >= now() - make_interval(days => $3)
+	))`
+
+func (r *broadcastRepository) Create(ctx context.Context, b models.Broadcast) (int64, error) {
+	buttons, err := json.Marshal(b.Buttons)
+	if err != nil {
+		return 0, fmt.Errorf("create broadcast: %w", err)
+	}
+	if b.Buttons == nil {
+		buttons = []byte("[]")
+	}
+	q := `
+	INSERT INTO broadcasts (created_by, text, photo_file_id, buttons)
+	VALUES ($1, $2, NULLIF($3, ''), $4)
+	RETURNING id;
+	`
+	var id int64
+	if err := r.db.QueryRow(ctx, q, b.CreatedBy, b.Text, b.PhotoFileID, buttons).Scan(&id); err != nil {
+		return 0, fmt.Errorf("create broadcast: %w", err)
+	}
+	return id, nil
+}
+
+func (r *broadcastRepository) Get(ctx context.Context, id int64) (models.Broadcast, error) {
+	q := `SELECT ` + broadcastColumns + ` FROM broadcasts WHERE id = $1;`
+	b, err := scanBroadcast(r.db.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Broadcast{}, models.ErrBroadcastNotFound
+		}
+		return models.Broadcast{}, fmt.Errorf("get broadcast: %w", err)
+	}
+	return b, nil
+}
+
+func (r *broadcastRepository) ListRecent(ctx context.Context, limit int) ([]models.Broadcast, error) {
+	q := `SELECT ` + broadcastColumns + ` FROM broadcasts ORDER BY id DESC LIMIT $1;`
+	return r.list(ctx, "list recent broadcasts", q, limit)
+}
+
+func (r *broadcastRepository) UpdateSegment(ctx context.Context, id int64, seg models.BroadcastSegment) error {
+	q := `
+	UPDATE broadcasts
+	SET segment_language = NULLIF($2, ''), segment_plan = NULLIF($3, ''), segment_active_days = $4
+	WHERE id = $1 AND status = 'draft';
+	`
+	tag, err := r.db.Exec(ctx, q, id, seg.Language, seg.Plan, seg.ActiveDays)
+	if err != nil {
+		return fmt.Errorf("update broadcast segment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrBroadcastNotFound
+	}
+	return nil
+}
+
+func (r *broadcastRepository) CountSegment(ctx context.Context, seg models.BroadcastSegment) (int, error) {
+	q := `SELECT count(*) FROM users u WHERE ` + segmentFilter + `;`
+	var n int
+	if err := r.db.QueryRow(ctx, q, seg.Language, seg.Plan, seg.ActiveDays).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count broadcast segment: %w", err)
+	}
+	return n, nil
+}
+
+func (r *broadcastRepository) Schedule(ctx context.Context, id int64, at time.Time) error {
+	q := `
+	UPDATE broadcasts
+	SET status = 'scheduled', scheduled_at = $2
+	WHERE id = $1 AND status = 'draft';
+	`
+	tag, err := r.db.Exec(ctx, q, id, at)
+	if err != nil {
+		return fmt.Errorf("schedule broadcast: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrBroadcastNotFound
+	}
+	return nil
+}
+
+func (r *broadcastRepository) Cancel(ctx context.Context, id int64) error {
+	q := `
+	UPDATE broadcasts
+	SET status = 'cancelled', finished_at = now()
+	WHERE id = $1 AND status IN ('draft', 'scheduled', 'sending');
+	`
+	tag, err := r.db.Exec(ctx, q, id)
+	if err != nil {
+		return fmt.Errorf("cancel broadcast: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrBroadcastNotFound
+	}
+	return nil
+}
+
+func (r *broadcastRepository) ListDue(ctx context.Context, now time.Time) ([]models.Broadcast, error) {
+	q := `
+	SELECT ` + broadcastColumns + `
+	FROM broadcasts
+	WHERE status = 'sending' OR (status = 'scheduled' AND scheduled_at <= $1)
+	ORDER BY scheduled_at, id;
+	`
+	return r.list(ctx, "list due broadcasts", q, now)
+}
+
+func (r *broadcastRepository) StartSending(ctx context.Context, id int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("start broadcast: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var seg models.BroadcastSegment
+	q := `
+	UPDATE broadcasts
+	SET status = 'sending', started_at = now()
+	WHERE id = $1 AND status = 'scheduled'
+	RETURNING COALESCE(segment_language, ''), COALESCE(segment_plan, ''), segment_active_days;
+	`
+	err = tx.QueryRow(ctx, q, id).Scan(&seg.Language, &seg.Plan, &seg.ActiveDays)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Already started by another instance or cancelled.
+			return nil
+		}
+		return fmt.Errorf("start broadcast: %w", err)
+	}
+
+	enqueue := `
+	INSERT INTO broadcast_recipients (broadcast_id, user_id, chat_id)
+	SELECT $4, u.id, u.tg_user_id
+	FROM users u
+	WHERE ` + segmentFilter + `
+	ON CONFLICT DO NOTHING;
+	`
+	if _, err := tx.Exec(ctx, enqueue, seg.Language, seg.Plan, seg.ActiveDays, id); err != nil {
+		return fmt.Errorf("start broadcast: enqueue: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("start broadcast: commit: %w", err)
+	}
+	return nil
+}
+
+func (r *broadcastRepository) ClaimRecipients(ctx context.Context, id int64, limit int, staleBefore time.Time) ([]models.BroadcastRecipient, error) {
+	q := `
+	WITH next AS (
+		SELECT br.broadcast_id, br.user_id
+		FROM broadcast_recipients br
+		JOIN broadcasts b ON b.id = br.broadcast_id AND b.status = 'sending'
+		WHERE br.broadcast_id = $1
+		  AND (br.status = 'pending' OR (br.status = 'sending' AND br.claimed_at < $3))
+		ORDER BY br.user_id
+		LIMIT $2
+		FOR UPDATE OF br SKIP LOCKED
+	)
+	UPDATE broadcast_recipients br
+	SET status = 'sending', claimed_at = now(), attempts = br.attempts + 1
+	FROM next
+	WHERE br.broadcast_id = next.broadcast_id AND br.user_id = next.user_id
+	RETURNING br.broadcast_id, br.user_id, br.chat_id;
+	`
+	rows, err := r.db.Query(ctx, q, id, limit, staleBefore)
+	if err != nil {
+		return nil, fmt.Errorf("claim broadcast recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var out []models.BroadcastRecipient
+	for rows.Next() {
+		var rc models.BroadcastRecipient
+		if err := rows.Scan(&rc.BroadcastID, &rc.UserID, &rc.ChatID); err != nil {
+			return nil, fmt.Errorf("claim broadcast recipients scan: %w", err)
+		}
+		out = append(out, rc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("claim broadcast recipients rows: %w", err)
+	}
+	return out, nil
+}
+
+func (r *broadcastRepository) MarkRecipient(ctx context.Context, rc models.BroadcastRecipient, status, errText string) error {
+	q := `
+	UPDATE broadcast_recipients
+	SET status = $3,
+	    error = NULLIF($4, ''),
+	    sent_at = CASE WHEN $3 = 'sent' THEN now() ELSE sent_at END
+	WHERE broadcast_id = $1 AND user_id = $2;
+	`
+	if _, err := r.db.Exec(ctx, q, rc.BroadcastID, rc.UserID, status, errText); err != nil {
+		return fmt.Errorf("mark broadcast recipient: %w", err)
+	}
+	return nil
+}
+
+func (r *broadcastRepository) FinishIfDone(ctx context.Context, id int64) (bool, error) {
+	q := `
+	UPDATE broadcasts b
+	SET status = 'done', finished_at = now()
+	WHERE b.id = $1 AND b.status = 'sending'
+	  AND NOT EXISTS (
+		SELECT 1 FROM broadcast_recipients br
+		WHERE br.broadcast_id = b.id AND br.status IN ('pending', 'sending')
+	  );
+	`
+	tag, err := r.db.Exec(ctx, q, id)
+	if err != nil {
+		return false, fmt.Errorf("finish broadcast: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *broadcastRepository) Stats(ctx context.Context, id int64) (models.BroadcastStats, error) {
+	q := `
+	SELECT count(*) FILTER (WHERE status IN ('pending', 'sending')),
+	       count(*) FILTER (WHERE status = 'sent'),
+	       count(*) FILTER (WHERE status = 'failed'),
+	       count(*) FILTER (WHERE status = 'blocked')
+	FROM broadcast_recipients
+	WHERE broadcast_id = $1;
+	`
+	var st models.BroadcastStats
+	if err := r.db.QueryRow(ctx, q, id).Scan(&st.Pending, &st.Sent, &st.Failed, &st.Blocked); err != nil {
+		return models.BroadcastStats{}, fmt.Errorf("broadcast stats: %w", err)
+	}
+	return st, nil
+}
+
+func (r *broadcastRepository) list(ctx context.Context, op, q string, args ...any) ([]models.Broadcast, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var out []models.Broadcast
+	for rows.Next() {
+		b, err := scanBroadcast(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		out = append(out, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s rows: %w", op, err)
+	}
+	return out, nil
+}
+
+func scanBroadcast(row pgx.Row) (models.Broadcast, error) {
+	var b models.Broadcast
+	var buttons []byte
+	err := row.Scan(
+		&b.ID, &b.CreatedBy, &b.Text, &b.PhotoFileID, &buttons,
+		&b.Segment.Language, &b.Segment.Plan, &b.Segment.ActiveDays,
+		&b.Status, &b.ScheduledAt, &b.CreatedAt, &b.StartedAt, &b.FinishedAt,
+	)
+	if err != nil {
+		return models.Broadcast{}, err
+	}
+	if err := json.Unmarshal(buttons, &b.Buttons); err != nil {
+		return models.Broadcast{}, fmt.Errorf("decode buttons: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DigestRepository stores digest settings and delivery records.
+type DigestRepository interface {
+	Get(ctx context.Context, userID int64) (models.DigestSettings, bool, error)
+	Upsert(ctx context.Context, settings models.DigestSettings) error
+	ListEnabled(ctx context.Context) ([]models.DigestSettings, error)
+	// Claim records delivery for period; returns false if it was already recorded.
+	Claim(ctx context.Context, userID int64, kind string, periodStart time.Time) (bool, error)
+	Release(ctx context.Context, userID int64, kind string, periodStart time.Time) error
+}
+
+type digestRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDigestRepository creates digest repository backed by pgx pool.
+func NewDigestRepository(db *pgxpool.Pool) DigestRepository {
+	return &digestRepository{db: db}
+}
+
+func (r *digestRepository) Get(ctx context.Context, userID int64) (models.DigestSettings, bool, error) {
+	if userID <= 0 {
+		return models.DigestSettings{}, false, fmt.Errorf("get digest settings: invalid userID")
+	}
+	q := `
+	SELECT uds.user_id, u.tg_user_id, u.timezone, uds.daily, uds.weekly, uds.monthly, uds.send_at_min
+	FROM user_digest_settings uds
+	JOIN users u ON u.id = uds.user_id
+	WHERE uds.user_id = $1;
+	`
+	var s models.DigestSettings
+	err := r.db.QueryRow(ctx, q, userID).Scan(&s.UserID, &s.TgUserID, &s.TimeZone, &s.Daily, &s.Weekly, &s.Monthly, &s.SendAtMin)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.DigestSettings{}, false, nil
+	}
+	if err != nil {
+		return models.DigestSettings{}, false, fmt.Errorf("get digest settings query: %w", err)
+	}
+	return s, true, nil
+}
+
+func (r *digestRepository) Upsert(ctx context.Context, settings models.DigestSettings) error {
+	if settings.UserID <= 0 {
+		return fmt.Errorf("upsert digest settings: invalid userID")
+	}
+	q := `
+	INSERT INTO user_digest_settings (user_id, daily, weekly, monthly, send_at_min, updated_at)
+	VALUES ($1, $2, $3, $4, $5, now())
+	ON CONFLICT (user_id)
+	DO UPDATE SET
+		daily = EXCLUDED.daily,
+		weekly = EXCLUDED.weekly,
+		monthly = EXCLUDED.monthly,
+		send_at_min = EXCLUDED.send_at_min,
+		updated_at = now();
+	`
+	if _, err := r.db.Exec(ctx, q, settings.UserID, settings.Daily, settings.Weekly, settings.Monthly, settings.SendAtMin); err != nil {
+		return fmt.Errorf("upsert digest settings: %w", err)
+	}
+	return nil
+}
+
+// ListEnabled returns active users with at least one digest enabled.
+func (r *digestRepository) ListEnabled(ctx context.Context) ([]models.DigestSettings, error) {
+	q := `
+	SELECT uds.user_id, u.tg_user_id, u.timezone, uds.daily, uds.weekly, uds.monthly, uds.send_at_min
+	FROM user_digest_settings uds
+	JOIN users u ON u.id = uds.user_id
+	WHERE (uds.daily OR uds.weekly OR uds.monthly)
+	  AND u.is_active = TRUE
+	ORDER BY uds.user_id;
+	`
+	rows, err := r.db.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("list digest settings query: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]models.DigestSettings, 0, 64)
+	for rows.Next() {
+		var s models.DigestSettings
+		if err := rows.Scan(&s.UserID, &s.TgUserID, &s.TimeZone, &s.Daily, &s.Weekly, &s.Monthly, &s.SendAtMin); err != nil {
+			return nil, fmt.Errorf("list digest settings scan: %w", err)
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list digest settings rows: %w", err)
+	}
+	return out, nil
+}
+
+// Claim inserts delivery row; the primary key makes concurrent claims exclusive.
+func (r *digestRepository) Claim(ctx context.Context, userID int64, kind string, periodStart time.Time) (bool, error) {
+	q := `
+	INSERT INTO digest_deliveries (user_id, kind, period_start)
+	VALUES ($1, $2, $3)
+	ON CONFLICT DO NOTHING;
+	`
+	tag, err := r.db.Exec(ctx, q, userID, kind, periodStart)
+	if err != nil {
+		return false, fmt.Errorf("claim digest: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Release removes delivery row so the digest is retried on next run.
+func (r *digestRepository) Release(ctx context.Context, userID int64, kind string, periodStart time.Time) error {
+	q := `
+	DELETE FROM digest_deliveries
+	WHERE user_id = $1 AND kind = $2 AND period_start = $3;
+	`
+	if _, err := r.db.Exec(ctx, q, userID, kind, periodStart); err != nil {
+		return fmt.Errorf("release digest: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"errors"
+	"fmt"
 	"tracker-bot/internal/models"
 
 	"github.com/jackc/pgx/v5"
@@ -13,6 +14,8 @@ type EntryRepository interface {
 	GetByID(ctx context.Context, id int64) (*models.User, error)
 	GetDBIDByTgUserID(ctx context.Context, tgUserID int64) (int64, error)
 	Create(ctx context.Context, stats *models.UserInput) (int64, error)
+	// SetActive marks user active/inactive; returns db id and whether the flag changed.
+	SetActive(ctx context.Context, tgUserID int64, active bool) (int64, bool, error)
 }
 type entryRepository struct {
 	db *pgxpool.Pool
@@ -88,3 +91,27 @@ func (r *entryRepository) Create(ctx context.Context, user *models.UserInput) (i
 	}
 	return id, nil
 }
+
+func (r *entryRepository) SetActive(ctx context.Context, tgUserID int64, active bool) (int64, bool, error) {
+	q := `
+	WITH prev AS (
+		SELECT id, is_active FROM users WHERE tg_user_id = $1 FOR UPDATE
+	)
+	UPDATE users u
+	SET is_active = $2,
+	    deactivated_at = CASE WHEN $2 THEN NULL ELSE now() END
+	FROM prev
+	WHERE u.id = prev.id
+	RETURNING u.id, prev.is_active <> $2;
+	`
+	var id int64
+	var changed bool
+	err := r.db.QueryRow(ctx, q, tgUserID, active).Scan(&id, &changed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, models.ErrUserNotFound
+		}
+		return 0, false, fmt.Errorf("set user active: %w", err)
+	}
+	return id, changed, nil
+}
@@ -0,0 +1,177 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GoalRepository stores per-activity goals and reads totals to evaluate them.
+type GoalRepository interface {
+	Upsert(ctx context.Context, userID, activityID int64, period, kind string, targetMin int) error
+	Delete(ctx context.Context, userID, goalID int64) error
+	ListByUser(ctx context.Context, userID int64) ([]models.Goal, error)
+	SetUseForStreak(ctx context.Context, userID, goalID int64, enabled bool) error
+	// GetDailyTotals returns tracked time per activity per day of loc ("2006-01-02") in [from, to).
+	GetDailyTotals(ctx context.Context, userID int64, activityIDs []int64, from, to time.Time, loc *time.Location) (map[int64]map[string]time.Duration, error)
+}
+
+type goalRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewGoalRepository creates goal repository backed by pgx pool.
+func NewGoalRepository(db *pgxpool.Pool) GoalRepository {
+	return &goalRepository{db: db}
+}
+
+// Upsert creates or replaces the goal of one activity for a period.
+func (r *goalRepository) Upsert(ctx context.Context, userID, activityID int64, period, kind string, targetMin int) error {
+	if userID <= 0 || activityID <= 0 || targetMin <= 0 {
+		return fmt.Errorf("upsert goal: invalid input")
+	}
+
+	q := `
+	INSERT INTO activity_goals (user_id, activity_id, period, kind, target_min)
+	SELECT $1, $2, $3, $4, $5
+	WHERE EXISTS (
+		SELECT 1
+		FROM activities
+		WHERE id = $2 AND user_id = $1 AND is_archived = FALSE
+	)
+	ON CONFLICT (activity_id, period)
+	DO UPDATE SET
+		kind = EXCLUDED.kind,
+		target_min = EXCLUDED.target_min,
+		updated_at = now();
+	`
+	// INSERT ... WHERE EXISTS prevents goals for foreign or archived activities.
+	tag, err := r.db.Exec(ctx, q, userID, activityID, period, kind, targetMin)
+	if err != nil {
+		return fmt.Errorf("upsert goal exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrActivityNotFound
+	}
+	return nil
+}
+
+// Delete removes one goal owned by user.
+func (r *goalRepository) Delete(ctx context.Context, userID, goalID int64) error {
+	if userID <= 0 || goalID <= 0 {
+		return fmt.Errorf("delete goal: invalid input")
+	}
+	q := `DELETE FROM activity_goals WHERE id = $1 AND user_id = $2;`
+	tag, err := r.db.Exec(ctx, q, goalID, userID)
+	if err != nil {
+		return fmt.Errorf("delete goal exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrGoalNotFound
+	}
+	return nil
+}
+
+// ListByUser returns goals of user's active activities.
+func (r *goalRepository) ListByUser(ctx context.Context, userID int64) ([]models.Goal, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("list goals: invalid userID")
+	}
+
+	q := `
+	SELECT g.id, g.activity_id, a.name, COALESCE(a.emoji, ''), g.period, g.kind, g.target_min, g.use_for_streak
+	FROM activity_goals g
+	JOIN activities a ON a.id = g.activity_id
+	WHERE g.user_id = $1
+	  AND a.is_archived = FALSE
+	ORDER BY a.sort_order, lower(a.name), g.period;
+	`
+	rows, err := r.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list goals query: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]models.Goal, 0, 8)
+	for rows.Next() {
+		var g models.Goal
+		var targetMin int
+		if err := rows.Scan(&g.ID, &g.ActivityID, &g.ActivityName, &g.Emoji, &g.Period, &g.Kind, &targetMin, &g.UseForStreak); err != nil {
+			return nil, fmt.Errorf("list goals scan: %w", err)
+		}
+		g.Target = time.Duration(targetMin) * time.Minute
+		out = append(out, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list goals rows: %w", err)
+	}
+	return out, nil
+}
+
+// SetUseForStreak marks whether the goal drives streak calculation.
+func (r *goalRepository) SetUseForStreak(ctx context.Context, userID, goalID int64, enabled bool) error {
+	if userID <= 0 || goalID <= 0 {
+		return fmt.Errorf("set goal streak: invalid input")
+	}
+	q := `
+	UPDATE activity_goals
+	SET use_for_streak = $3, updated_at = now()
+	WHERE id = $1 AND user_id = $2;
+	`
+	tag, err := r.db.Exec(ctx, q, goalID, userID, enabled)
+	if err != nil {
+		return fmt.Errorf("set goal streak exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrGoalNotFound
+	}
+	return nil
+}
+
+func (r *goalRepository) GetDailyTotals(ctx context.Context, userID int64, activityIDs []int64, from, to time.Time, loc *time.Location) (map[int64]map[string]time.Duration, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("goal daily totals: invalid userID")
+	}
+	out := make(map[int64]map[string]time.Duration, len(activityIDs))
+	if len(activityIDs) == 0 {
+		return out, nil
+	}
+
+	q := `
+	SELECT s.activity_id,
+	       (s.start_at AT TIME ZONE $5)::date AS day,
+	       COALESCE(SUM(s.end_at - s.start_at), interval '0') AS total_dur
+	FROM activity_sessions s
+	WHERE s.user_id = $1
+	  AND s.end_at IS NOT NULL
+	  AND s.start_at >= $2
+	  AND s.start_at < $3
+	  AND s.activity_id = ANY($4)
+	GROUP BY s.activity_id, day;
+	`
+	rows, err := r.db.Query(ctx, q, userID, from.UTC(), to.UTC(), activityIDs, loc.String())
+	if err != nil {
+		return nil, fmt.Errorf("goal daily totals query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var activityID int64
+		var day time.Time
+		var dur time.Duration
+		if err := rows.Scan(&activityID, &day, &dur); err != nil {
+			return nil, fmt.Errorf("goal daily totals scan: %w", err)
+		}
+		if out[activityID] == nil {
+			out[activityID] = make(map[string]time.Duration)
+		}
+		out[activityID][day.Format("2006-01-02")] += dur
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("goal daily totals rows: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OnboardingRepository stores first-run wizard progress and start attribution.
+type OnboardingRepository interface {
+	GetStep(ctx context.Context, userID int64) (string, error)
+	SetStep(ctx context.Context, userID int64, step string) error
+	// RecordStartPayload keeps the first /start payload; returns false when one is already stored.
+	RecordStartPayload(ctx context.Context, userID int64, payload string) (bool, error)
+}
+
+type onboardingRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOnboardingRepository creates onboarding repository backed by pgx pool.
+func NewOnboardingRepository(db *pgxpool.Pool) OnboardingRepository {
+	return &onboardingRepository{db: db}
+}
+
+func (r *onboardingRepository) GetStep(ctx context.Context, userID int64) (string, error) {
+	var step string
+	err := r.db.QueryRow(ctx, `SELECT onboarding_step FROM users WHERE id = $1;`, userID).Scan(&step)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", models.ErrUserNotFound
+		}
+		return "", fmt.Errorf("get onboarding step: %w", err)
+	}
+	return step, nil
+}
+
+func (r *onboardingRepository) SetStep(ctx context.Context, userID int64, step string) error {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET onboarding_step = $2 WHERE id = $1;`, userID, step)
+	if err != nil {
+		return fmt.Errorf("set onboarding step: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *onboardingRepository) RecordStartPayload(ctx context.Context, userID int64, payload string) (bool, error) {
+	q := `
+	UPDATE users
+	SET start_payload = $2, start_payload_at = now()
+	WHERE id = $1 AND start_payload IS NULL;
+	`
+	tag, err := r.db.Exec(ctx, q, userID, payload)
+	if err != nil {
+		return false, fmt.Errorf("record start payload: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
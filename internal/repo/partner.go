@@ -0,0 +1,259 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PartnerRepository stores partner invites, partnerships and nudges.
+type PartnerRepository interface {
+	CreateInvite(ctx context.Context, token string, userID int64, expiresAt time.Time) error
+	// GetInvite returns unused invite that has not expired at now.
+	GetInvite(ctx context.Context, token string, now time.Time) (models.PartnerInvite, error)
+	// AcceptInvite pairs invitee with inviter and marks invite used; returns partnership id.
+	AcceptInvite(ctx context.Context, token string, userID int64, now time.Time) (int64, error)
+	List(ctx context.Context, userID int64) ([]models.Partner, error)
+	// Get returns partner of userID in partnership; ErrPartnerNotFound for foreign partnerships.
+	Get(ctx context.Context, partnershipID, userID int64) (models.Partner, error)
+	// SharedActivityIDs returns activities userID shares in partnership.
+	SharedActivityIDs(ctx context.Context, partnershipID, userID int64) ([]int64, error)
+	ToggleActivity(ctx context.Context, partnershipID, userID, activityID int64) error
+	Delete(ctx context.Context, partnershipID, userID int64) error
+	// HasTrackedSince reports whether user has a session ending after since or a running one.
+	HasTrackedSince(ctx context.Context, userID int64, since time.Time) (bool, error)
+	// ClaimNudge records nudge for day; returns false if sender already nudged that day.
+	ClaimNudge(ctx context.Context, partnershipID, fromUserID int64, day time.Time) (bool, error)
+	ReleaseNudge(ctx context.Context, partnershipID, fromUserID int64, day time.Time) error
+}
+
+type partnerRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPartnerRepository creates partner repository backed by pgx pool.
+func NewPartnerRepository(db *pgxpool.Pool) PartnerRepository {
+	return &partnerRepository{db: db}
+}
+
+func (r *partnerRepository) CreateInvite(ctx context.Context, token string, userID int64, expiresAt time.Time) error {
+	q := `INSERT INTO partner_invites (token, user_id, expires_at) VALUES ($1, $2, $3);`
+	if _, err := r.db.Exec(ctx, q, token, userID, expiresAt); err != nil {
+		return fmt.Errorf("create partner invite: %w", err)
+	}
+	return nil
+}
+
+func (r *partnerRepository) GetInvite(ctx context.Context, token string, now time.Time) (models.PartnerInvite, error) {
+	q := `
+	SELECT i.token, i.user_id, COALESCE(u.username::text, ''), i.expires_at
+	FROM partner_invites i
+	JOIN users u ON u.id = i.user_id
+	WHERE i.token = $1 AND i.used_at IS NULL AND i.expires_at > $2;
+	`
+	var inv models.PartnerInvite
+	err := r.db.QueryRow(ctx, q, token, now).Scan(&inv.Token, &inv.UserID, &inv.InviterName, &inv.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.PartnerInvite{}, models.ErrInviteNotFound
+		}
+		return models.PartnerInvite{}, fmt.Errorf("get partner invite: %w", err)
+	}
+	return inv, nil
+}
+
+func (r *partnerRepository) AcceptInvite(ctx context.Context, token string, userID int64, now time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("accept invite begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var inviterID int64
+	q := `
+	SELECT user_id FROM partner_invites
+	WHERE token = $1 AND used_at IS NULL AND expires_at > $2
+	FOR UPDATE;
+	`
+	if err := tx.QueryRow(ctx, q, token, now).Scan(&inviterID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, models.ErrInviteNotFound
+		}
+		return 0, fmt.Errorf("accept invite lock: %w", err)
+	}
+	if inviterID == userID {
+		return 0, models.ErrOwnInvite
+	}
+
+	var id int64
+	q = `
+	INSERT INTO partnerships (user_a, user_b)
+	VALUES (LEAST($1::bigint, $2::bigint), GREATEST($1::bigint, $2::bigint))
+	ON CONFLICT (user_a, user_b) DO NOTHING
+	RETURNING id;
+	`
+	if err := tx.QueryRow(ctx, q, inviterID, userID).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, models.ErrAlreadyPartners
+		}
+		return 0, fmt.Errorf("accept invite insert: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE partner_invites SET used_at = $2 WHERE token = $1;`, token, now); err != nil {
+		return 0, fmt.Errorf("accept invite mark used: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("accept invite commit: %w", err)
+	}
+	return id, nil
+}
+
+// partnerSelect resolves the other side of partnership for viewer $1.
+const partnerSelect = `
+	SELECT p.id, u.id, u.tg_user_id, COALESCE(u.username::text, ''), u.timezone, p.created_at,
+		COALESCE((
+			SELECT array_agg(sa.activity_id ORDER BY sa.activity_id)
+			FROM partner_shared_activities sa
+			WHERE sa.partnership_id = p.id AND sa.user_id = $1
+		), '{}')
+	FROM partnerships p
+	JOIN users u ON u.id = CASE WHEN p.user_a = $1 THEN p.user_b ELSE p.user_a END
+	WHERE (p.user_a = $1 OR p.user_b = $1)
+	`
+
+func (r *partnerRepository) List(ctx context.Context, userID int64) ([]models.Partner, error) {
+	rows, err := r.db.Query(ctx, partnerSelect+` ORDER BY p.created_at;`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list partners: %w", err)
+	}
+	defer rows.Close()
+
+	var out []models.Partner
+	for rows.Next() {
+		p, err := scanPartner(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list partners scan: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list partners rows: %w", err)
+	}
+	return out, nil
+}
+
+func (r *partnerRepository) Get(ctx context.Context, partnershipID, userID int64) (models.Partner, error) {
+	p, err := scanPartner(r.db.QueryRow(ctx, partnerSelect+` AND p.id = $2;`, userID, partnershipID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Partner{}, models.ErrPartnerNotFound
+		}
+		return models.Partner{}, fmt.Errorf("get partner: %w", err)
+	}
+	return p, nil
+}
+
+func (r *partnerRepository) SharedActivityIDs(ctx context.Context, partnershipID, userID int64) ([]int64, error) {
+	q := `
+	SELECT activity_id FROM partner_shared_activities
+	WHERE partnership_id = $1 AND user_id = $2
+	ORDER BY activity_id;
+	`
+	rows, err := r.db.Query(ctx, q, partnershipID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("partner shared activities: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("partner shared activities scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("partner shared activities rows: %w", err)
+	}
+	return ids, nil
+}
+
+// ToggleActivity deletes share row if present, otherwise inserts it for user's own activity.
+func (r *partnerRepository) ToggleActivity(ctx context.Context, partnershipID, userID, activityID int64) error {
+	q := `
+	WITH del AS (
+		DELETE FROM partner_shared_activities
+		WHERE partnership_id = $1 AND user_id = $2 AND activity_id = $3
+		RETURNING 1
+	)
+	INSERT INTO partner_shared_activities (partnership_id, user_id, activity_id)
+	SELECT $1, $2, a.id
+	FROM activities a
+	JOIN partnerships p ON p.id = $1 AND (p.user_a = $2 OR p.user_b = $2)
+	WHERE a.id = $3 AND a.user_id = $2
+	  AND NOT EXISTS (SELECT 1 FROM del);
+	`
+	if _, err := r.db.Exec(ctx, q, partnershipID, userID, activityID); err != nil {
+		return fmt.Errorf("toggle partner activity: %w", err)
+	}
+	return nil
+}
+
+func (r *partnerRepository) Delete(ctx context.Context, partnershipID, userID int64) error {
+	q := `DELETE FROM partnerships WHERE id = $1 AND (user_a = $2 OR user_b = $2);`
+	tag, err := r.db.Exec(ctx, q, partnershipID, userID)
+	if err != nil {
+		return fmt.Errorf("delete partnership: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrPartnerNotFound
+	}
+	return nil
+}
+
+func (r *partnerRepository) HasTrackedSince(ctx context.Context, userID int64, since time.Time) (bool, error) {
+	q := `
+	SELECT EXISTS (
+		SELECT 1 FROM activity_sessions
+		WHERE user_id = $1 AND (end_at IS NULL OR end_at > $2)
+	);
+	`
+	var ok bool
+	if err := r.db.QueryRow(ctx, q, userID, since).Scan(&ok); err != nil {
+		return false, fmt.Errorf("has tracked since: %w", err)
+	}
+	return ok, nil
+}
+
+func (r *partnerRepository) ClaimNudge(ctx context.Context, partnershipID, fromUserID int64, day time.Time) (bool, error) {
+	q := `
+	INSERT INTO partner_nudges (partnership_id, from_user_id, day)
+	VALUES ($1, $2, $3)
+	ON CONFLICT DO NOTHING;
+	`
+	tag, err := r.db.Exec(ctx, q, partnershipID, fromUserID, day)
+	if err != nil {
+		return false, fmt.Errorf("claim nudge: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *partnerRepository) ReleaseNudge(ctx context.Context, partnershipID, fromUserID int64, day time.Time) error {
+	q := `DELETE FROM partner_nudges WHERE partnership_id = $1 AND from_user_id = $2 AND day = $3;`
+	if _, err := r.db.Exec(ctx, q, partnershipID, fromUserID, day); err != nil {
+		return fmt.Errorf("release nudge: %w", err)
+	}
+	return nil
+}
+
+func scanPartner(row pgx.Row) (models.Partner, error) {
+	var p models.Partner
+	err := row.Scan(&p.PartnershipID, &p.UserID, &p.TgUserID, &p.Name, &p.TimeZone, &p.Since, &p.ActivityIDs)
+	return p, err
+}
@@ -0,0 +1,281 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PomodoroRepository stores pomodoro settings and running focus/break cycles.
+type PomodoroRepository interface {
+	// GetSettings returns saved settings; false when user never changed them.
+	GetSettings(ctx context.Context, userID int64) (models.PomodoroSettings, bool, error)
+	SaveSettings(ctx context.Context, userID int64, s models.PomodoroSettings) error
+	// Start saves settings if missing and inserts running pomodoro for user's active activity.
+	Start(ctx context.Context, p models.Pomodoro) error
+	Get(ctx context.Context, userID int64) (models.Pomodoro, error)
+	// ListRunning returns pomodoros of active users that are in focus or break.
+	ListRunning(ctx context.Context) ([]models.Pomodoro, error)
+	// Advance moves pomodoro to next phase only if it is still in cur's phase and, when cur is
+	// focus, saves it as session in the same transaction; false means another instance or
+	// user action got there first.
+	Advance(ctx context.Context, cur, next models.Pomodoro) (bool, error)
+	// SetShown remembers countdown message and minutes left rendered for current phase.
+	SetShown(ctx context.Context, userID int64, phaseEndsAt time.Time, messageID, shownMin int) error
+	// Stop removes running pomodoro and returns it as it was; focus spent until now is saved
+	// in the same transaction when it is at least a minute, and its length is returned.
+	Stop(ctx context.Context, userID int64, now time.Time) (models.Pomodoro, time.Duration, error)
+}
+
+type pomodoroRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPomodoroRepository creates pomodoro repository backed by pgx pool.
+func NewPomodoroRepository(db *pgxpool.Pool) PomodoroRepository {
+	return &pomodoroRepository{db: db}
+}
+
+func (r *pomodoroRepository) GetSettings(ctx context.Context, userID int64) (models.PomodoroSettings, bool, error) {
+	q := `
+	SELECT focus_min, short_break_min, long_break_min, long_break_every
+	FROM pomodoro_settings
+	WHERE user_id = $1;
+	`
+	var s models.PomodoroSettings
+	err := r.db.QueryRow(ctx, q, userID).Scan(&s.FocusMin, &s.ShortBreakMin, &s.LongBreakMin, &s.LongBreakEvery)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.PomodoroSettings{}, false, nil
+		}
+		return models.PomodoroSettings{}, false, fmt.Errorf("get pomodoro settings: %w", err)
+	}
+	return s, true, nil
+}
+
+func (r *pomodoroRepository) SaveSettings(ctx context.Context, userID int64, s models.PomodoroSettings) error {
+	q := `
+	INSERT INTO pomodoro_settings (user_id, focus_min, short_break_min, long_break_min, long_break_every)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (user_id)
+	DO UPDATE SET
+		focus_min = EXCLUDED.focus_min,
+		short_break_min = EXCLUDED.short_break_min,
+		long_break_min = EXCLUDED.long_break_min,
+		long_break_every = EXCLUDED.long_break_every;
+	`
+	if _, err := r.db.Exec(ctx, q, userID, s.FocusMin, s.ShortBreakMin, s.LongBreakMin, s.LongBreakEvery); err != nil {
+		return fmt.Errorf("save pomodoro settings: %w", err)
+	}
+	return nil
+}
+
+func (r *pomodoroRepository) Start(ctx context.Context, p models.Pomodoro) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("start pomodoro begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := `
+	INSERT INTO pomodoro_settings (user_id, focus_min, short_break_min, long_break_min, long_break_every)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (user_id) DO NOTHING;
+	`
+	s := p.Settings
+	if _, err := tx.Exec(ctx, q, p.UserID, s.FocusMin, s.ShortBreakMin, s.LongBreakMin, s.LongBreakEvery); err != nil {
+		return fmt.Errorf("start pomodoro settings: %w", err)
+	}
+
+	var running bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pomodoros WHERE user_id = $1);`, p.UserID).Scan(&running); err != nil {
+		return fmt.Errorf("start pomodoro check: %w", err)
+	}
+	if running {
+		return models.ErrPomodoroRunning
+	}
+
+	q = `
+	INSERT INTO pomodoros (user_id, activity_id, phase, cycle, phase_started_at, phase_ends_at)
+	SELECT $1, a.id, $3, $4, $5, $6
+	FROM activities a
+	WHERE a.id = $2 AND a.user_id = $1 AND a.is_archived = FALSE
+	ON CONFLICT (user_id) DO NOTHING;
+	`
+	tag, err := tx.Exec(ctx, q, p.UserID, p.ActivityID, p.Phase, p.Cycle, p.PhaseStartedAt, p.PhaseEndsAt)
+	if err != nil {
+		return fmt.Errorf("start pomodoro insert: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrActivityNotFound
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("start pomodoro commit: %w", err)
+	}
+	return nil
+}
+
+// pomodoroSelect reads pomodoro rows of relation p with activity, owner and settings.
+const pomodoroSelect = `
+	SELECT p.user_id, u.tg_user_id, p.activity_id, a.name, COALESCE(a.emoji, ''),
+		p.phase, p.cycle, p.phase_started_at, p.phase_ends_at, p.message_id, p.shown_min,
+		ps.focus_min, ps.short_break_min, ps.long_break_min, ps.long_break_every
+	FROM %s p
+	JOIN users u ON u.id = p.user_id
+	JOIN activities a ON a.id = p.activity_id
+	JOIN pomodoro_settings ps ON ps.user_id = p.user_id
+	`
+
+func (r *pomodoroRepository) Get(ctx context.Context, userID int64) (models.Pomodoro, error) {
+	q := fmt.Sprintf(pomodoroSelect, "pomodoros") + ` WHERE p.user_id = $1;`
+	p, err := scanPomodoro(r.db.QueryRow(ctx, q, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Pomodoro{}, models.ErrPomodoroNotFound
+		}
+		return models.Pomodoro{}, fmt.Errorf("get pomodoro: %w", err)
+	}
+	return p, nil
+}
+
+func (r *pomodoroRepository) ListRunning(ctx context.Context) ([]models.Pomodoro, error) {
+	q := fmt.Sprintf(pomodoroSelect, "pomodoros") + `
+	WHERE u.is_active = TRUE AND p.phase <> $1
+	ORDER BY p.phase_ends_at;
+	`
+	rows, err := r.db.Query(ctx, q, models.PomodoroPhaseWaiting)
+	if err != nil {
+		return nil, fmt.Errorf("list pomodoros: %w", err)
+	}
+	defer rows.Close()
+
+	var out []models.Pomodoro
+	for rows.Next() {
+		p, err := scanPomodoro(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list pomodoros scan: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list pomodoros rows: %w", err)
+	}
+	return out, nil
+}
+
+func (r *pomodoroRepository) Advance(ctx context.Context, cur, next models.Pomodoro) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("advance pomodoro begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := `
+	UPDATE pomodoros
+	SET phase = $4, cycle = $5, phase_started_at = $6, phase_ends_at = $7, message_id = 0, shown_min = 0
+	WHERE user_id = $1 AND phase = $2 AND phase_ends_at = $3;
+	`
+	tag, err := tx.Exec(ctx, q, cur.UserID, cur.Phase, cur.PhaseEndsAt,
+		next.Phase, next.Cycle, next.PhaseStartedAt, next.PhaseEndsAt)
+	if err != nil {
+		return false, fmt.Errorf("advance pomodoro: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	if cur.Phase == models.PomodoroPhaseFocus {
+		if _, err := insertPomodoroSession(ctx, tx, cur, cur.PhaseEndsAt, models.SessionSourcePomodoro); err != nil {
+			return false, fmt.Errorf("advance pomodoro session: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("advance pomodoro commit: %w", err)
+	}
+	return true, nil
+}
+
+func (r *pomodoroRepository) SetShown(ctx context.Context, userID int64, phaseEndsAt time.Time, messageID, shownMin int) error {
+	q := `
+	UPDATE pomodoros
+	SET message_id = $3, shown_min = $4
+	WHERE user_id = $1 AND phase_ends_at = $2;
+	`
+	if _, err := r.db.Exec(ctx, q, userID, phaseEndsAt, messageID, shownMin); err != nil {
+		return fmt.Errorf("set pomodoro shown: %w", err)
+	}
+	return nil
+}
+
+func (r *pomodoroRepository) Stop(ctx context.Context, userID int64, now time.Time) (models.Pomodoro, time.Duration, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return models.Pomodoro{}, 0, fmt.Errorf("stop pomodoro begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := `WITH del AS (DELETE FROM pomodoros WHERE user_id = $1 RETURNING *)` + fmt.Sprintf(pomodoroSelect, "del") + `;`
+	p, err := scanPomodoro(tx.QueryRow(ctx, q, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Pomodoro{}, 0, models.ErrPomodoroNotFound
+		}
+		return models.Pomodoro{}, 0, fmt.Errorf("stop pomodoro: %w", err)
+	}
+
+	var spent time.Duration
+	if p.Phase == models.PomodoroPhaseFocus {
+		endAt := now
+		if endAt.After(p.PhaseEndsAt) {
+			endAt = p.PhaseEndsAt
+		}
+		spent = endAt.Sub(p.PhaseStartedAt).Truncate(time.Minute)
+	}
+	if spent < time.Minute {
+		spent = 0
+	} else {
+		saved, err := insertPomodoroSession(ctx, tx, p, p.PhaseStartedAt.Add(spent), models.SessionSourcePomodoroPartial)
+		if err != nil {
+			return models.Pomodoro{}, 0, fmt.Errorf("stop pomodoro session: %w", err)
+		}
+		if !saved {
+			spent = 0
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.Pomodoro{}, 0, fmt.Errorf("stop pomodoro commit: %w", err)
+	}
+	return p, spent, nil
+}
+
+// insertPomodoroSession saves focus of p from its phase start to endAt; false when the
+// activity was archived meanwhile, then nothing is saved.
+func insertPomodoroSession(ctx context.Context, tx pgx.Tx, p models.Pomodoro, endAt time.Time, source string) (bool, error) {
+	q := `
+	INSERT INTO activity_sessions (user_id, activity_id, start_at, end_at, planned_min, source)
+	SELECT $1, $2, $3, $4, GREATEST(1, CEIL(EXTRACT(EPOCH FROM ($4::timestamptz - $3::timestamptz)) / 60))::int, $5
+	WHERE EXISTS (
+		SELECT 1
+		FROM activities
+		WHERE id = $2 AND user_id = $1 AND is_archived = FALSE
+	);
+	`
+	tag, err := tx.Exec(ctx, q, p.UserID, p.ActivityID, p.PhaseStartedAt.UTC(), endAt.UTC(), source)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func scanPomodoro(row pgx.Row) (models.Pomodoro, error) {
+	var p models.Pomodoro
+	s := &p.Settings
+	err := row.Scan(&p.UserID, &p.TgUserID, &p.ActivityID, &p.ActivityName, &p.ActivityEmoji,
+		&p.Phase, &p.Cycle, &p.PhaseStartedAt, &p.PhaseEndsAt, &p.MessageID, &p.ShownMin,
+		&s.FocusMin, &s.ShortBreakMin, &s.LongBreakMin, &s.LongBreakEvery)
+	return p, err
+}
@@ -68,7 +68,7 @@ func (repo *profileRepository) Update(ctx context.Context, id int64, stats *mode
 	q := `
 		UPDATE users
 		SET language = $2, timezone = $3
-		WHERE id = $1
+		WHERE tg_user_id = $1
 	`
 
 	res, err := repo.db.Exec(ctx, q, id, stats.Language, stats.TimeZone)
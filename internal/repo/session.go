@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"fmt"
+	"time"
 	errlocal "tracker-bot/internal/models"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,6 +13,11 @@ import (
 type SessionRepository interface {
 	// CreateRetroSession saves one session ending "now" with duration intervalMin.
 	CreateRetroSession(ctx context.Context, userID, activityID int64, intervalMin int, source string) error
+	// CreateSession saves one closed session with explicit bounds.
+	CreateSession(ctx context.Context, userID, activityID int64, startAt, endAt time.Time, source string) error
+	// CreateSessionOnce is CreateSession keyed by idempotency key; false when session
+	// with this key was already saved.
+	CreateSessionOnce(ctx context.Context, userID, activityID int64, startAt, endAt time.Time, source, key string) (bool, error)
 }
 
 type sessionRepository struct {
@@ -54,3 +60,62 @@ func (r *sessionRepository) CreateRetroSession(ctx context.Context, userID, acti
 	}
 	return nil
 }
+
+// CreateSession writes a closed session only for user's active activity.
+func (r *sessionRepository) CreateSession(ctx context.Context, userID, activityID int64, startAt, endAt time.Time, source string) error {
+	if userID <= 0 || activityID <= 0 || !endAt.After(startAt) {
+		return fmt.Errorf("create session: invalid input")
+	}
+
+	q := `
+	INSERT INTO activity_sessions (user_id, activity_id, start_at, end_at, planned_min, source)
+	SELECT $1, $2, $3, $4, GREATEST(1, CEIL(EXTRACT(EPOCH FROM ($4::timestamptz - $3::timestamptz)) / 60))::int, $5
+	WHERE EXISTS (
+		SELECT 1
+		FROM activities
+		WHERE id = $2 AND user_id = $1 AND is_archived = FALSE
+	);
+	`
+	tag, err := r.db.Exec(ctx, q, userID, activityID, startAt.UTC(), endAt.UTC(), source)
+	if err != nil {
+		return fmt.Errorf("create session exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errlocal.ErrActivityNotFound
+	}
+	return nil
+}
+
+func (r *sessionRepository) CreateSessionOnce(ctx context.Context, userID, activityID int64, startAt, endAt time.Time, source, key string) (bool, error) {
+	if userID <= 0 || activityID <= 0 || !endAt.After(startAt) || key == "" {
+		return false, fmt.Errorf("create session once: invalid input")
+	}
+
+	q := `
+	INSERT INTO activity_sessions (user_id, activity_id, start_at, end_at, planned_min, source, idempotency_key)
+	SELECT $1, $2, $3, $4, GREATEST(1, CEIL(EXTRACT(EPOCH FROM ($4::timestamptz - $3::timestamptz)) / 60))::int, $5, $6
+	WHERE EXISTS (
+		SELECT 1
+		FROM activities
+		WHERE id = $2 AND user_id = $1 AND is_archived = FALSE
+	)
+	ON CONFLICT (user_id, idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING;
+	`
+	tag, err := r.db.Exec(ctx, q, userID, activityID, startAt.UTC(), endAt.UTC(), source, key)
+	if err != nil {
+		return false, fmt.Errorf("create session once exec: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return true, nil
+	}
+
+	var saved bool
+	q = `SELECT EXISTS (SELECT 1 FROM activity_sessions WHERE user_id = $1 AND idempotency_key = $2);`
+	if err := r.db.QueryRow(ctx, q, userID, key).Scan(&saved); err != nil {
+		return false, fmt.Errorf("create session once check: %w", err)
+	}
+	if !saved {
+		return false, errlocal.ErrActivityNotFound
+	}
+	return false, nil
+}
@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ShareRepository stores read-only report links.
+type ShareRepository interface {
+	Create(ctx context.Context, s models.ReportShare) (int64, error)
+	Get(ctx context.Context, id int64) (models.ReportShare, error)
+	// ListActive returns user's links that are neither revoked nor expired at now.
+	ListActive(ctx context.Context, userID int64, now time.Time) ([]models.ReportShare, error)
+	// Revoke disables user's link; ErrShareNotFound if it is foreign or already revoked.
+	Revoke(ctx context.Context, userID, id int64) error
+}
+
+type shareRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewShareRepository creates repository backed by pgx pool.
+func NewShareRepository(db *pgxpool.Pool) ShareRepository {
+	return &shareRepository{db: db}
+}
+
+const shareColumns = `id, user_id, from_date, to_date, timezone, activity_ids, expires_at, revoked_at, created_at`
+
+func (r *shareRepository) Create(ctx context.Context, s models.ReportShare) (int64, error) {
+	ids := s.ActivityIDs
+	if ids == nil {
+		ids = []int64{}
+	}
+	q := `
+	INSERT INTO report_shares (user_id, from_date, to_date, timezone, activity_ids, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id;
+	`
+	// DATE columns keep the calendar day, so pass it explicitly rather than the UTC instant.
+	from, to := s.From.Format(time.DateOnly), s.To.Format(time.DateOnly)
+	var id int64
+	if err := r.db.QueryRow(ctx, q, s.UserID, from, to, s.TimeZone, ids, s.ExpiresAt).Scan(&id); err != nil {
+		return 0, fmt.Errorf("create share: %w", err)
+	}
+	return id, nil
+}
+
+func (r *shareRepository) Get(ctx context.Context, id int64) (models.ReportShare, error) {
+	q := `SELECT ` + shareColumns + ` FROM report_shares WHERE id = $1;`
+	s, err := scanShare(r.db.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ReportShare{}, models.ErrShareNotFound
+		}
+		return models.ReportShare{}, fmt.Errorf("get share: %w", err)
+	}
+	return s, nil
+}
+
+func (r *shareRepository) ListActive(ctx context.Context, userID int64, now time.Time) ([]models.ReportShare, error) {
+	q := `
+	SELECT ` + shareColumns + `
+	FROM report_shares
+	WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+	ORDER BY created_at DESC;
+	`
+	rows, err := r.db.Query(ctx, q, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("list shares: %w", err)
+	}
+	defer rows.Close()
+
+	var out []models.ReportShare
+	for rows.Next() {
+		s, err := scanShare(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list shares scan: %w", err)
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list shares rows: %w", err)
+	}
+	return out, nil
+}
+
+func (r *shareRepository) Revoke(ctx context.Context, userID, id int64) error {
+	q := `
+	UPDATE report_shares
+	SET revoked_at = now()
+	WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+	`
+	tag, err := r.db.Exec(ctx, q, id, userID)
+	if err != nil {
+		return fmt.Errorf("revoke share: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrShareNotFound
+	}
+	return nil
+}
+
+// scanShare reads share and turns its DATE bounds back into midnights of the stored zone.
+func scanShare(row pgx.Row) (models.ReportShare, error) {
+	var s models.ReportShare
+	err := row.Scan(&s.ID, &s.UserID, &s.From, &s.To, &s.TimeZone, &s.ActivityIDs, &s.ExpiresAt, &s.RevokedAt, &s.CreatedAt)
+	if err != nil {
+		return s, err
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	s.From = time.Date(s.From.Year(), s.From.Month(), s.From.Day(), 0, 0, 0, 0, loc)
+	s.To = time.Date(s.To.Year(), s.To.Month(), s.To.Day(), 0, 0, 0, 0, loc)
+	return s, nil
+}
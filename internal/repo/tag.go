@@ -0,0 +1,285 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TagRepository stores activity tags and tag-based aggregates.
+type TagRepository interface {
+	Create(ctx context.Context, userID int64, name string, parentID int64) (models.Tag, error)
+	GetByName(ctx context.Context, userID int64, name string) (models.Tag, error)
+	List(ctx context.Context, userID int64) ([]models.Tag, error)
+	Delete(ctx context.Context, userID, tagID int64) error
+	ToggleActivityTag(ctx context.Context, userID, activityID, tagID int64) error
+	ListTagActivityIDs(ctx context.Context, userID, tagID int64) ([]int64, error)
+	// ResolveActivityIDs returns active activities tagged with tagID or any of its descendants.
+	ResolveActivityIDs(ctx context.Context, userID, tagID int64) ([]int64, error)
+	GetPeriodTagTotals(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) ([]models.TagDurationStat, error)
+}
+
+type tagRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTagRepository creates tag repository backed by pgx pool.
+func NewTagRepository(db *pgxpool.Pool) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(ctx context.Context, userID int64, name string, parentID int64) (models.Tag, error) {
+	if userID <= 0 {
+		return models.Tag{}, fmt.Errorf("create tag: invalid userID")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Tag{}, fmt.Errorf("create tag: empty name")
+	}
+
+	var parent *int64
+	if parentID > 0 {
+		parent = &parentID
+	}
+
+	// Parent must belong to the same user.
+	q := `
+	INSERT INTO tags (user_id, parent_id, name)
+	SELECT $1, $2, $3
+	WHERE $2::bigint IS NULL OR EXISTS (
+		SELECT 1 FROM tags WHERE id = $2 AND user_id = $1
+	)
+	RETURNING id, COALESCE(parent_id, 0), name;
+	`
+	var t models.Tag
+	err := r.db.QueryRow(ctx, q, userID, parent, name).Scan(&t.ID, &t.ParentID, &t.Name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Tag{}, models.ErrTagNotFound
+		}
+		var pgErr *pgconn.PgError
+		// 23505 is PostgreSQL unique_violation.
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return models.Tag{}, models.ErrTagExists
+		}
+		return models.Tag{}, fmt.Errorf("create tag: %w", err)
+	}
+	return t, nil
+}
+
+func (r *tagRepository) GetByName(ctx context.Context, userID int64, name string) (models.Tag, error) {
+	q := `
+	SELECT id, COALESCE(parent_id, 0), name
+	FROM tags
+	WHERE user_id = $1 AND lower(name) = lower($2);
+	`
+	var t models.Tag
+	err := r.db.QueryRow(ctx, q, userID, strings.TrimSpace(name)).Scan(&t.ID, &t.ParentID, &t.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Tag{}, models.ErrTagNotFound
+	}
+	if err != nil {
+		return models.Tag{}, fmt.Errorf("get tag by name: %w", err)
+	}
+	return t, nil
+}
+
+func (r *tagRepository) List(ctx context.Context, userID int64) ([]models.Tag, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("list tags: invalid userID")
+	}
+	q := `
+	SELECT id, COALESCE(parent_id, 0), name
+	FROM tags
+	WHERE user_id = $1
+	ORDER BY lower(name), id;
+	`
+	rows, err := r.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list tags query: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]models.Tag, 0, 16)
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.ParentID, &t.Name); err != nil {
+			return nil, fmt.Errorf("list tags scan: %w", err)
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list tags rows: %w", err)
+	}
+	return out, nil
+}
+
+func (r *tagRepository) Delete(ctx context.Context, userID, tagID int64) error {
+	if userID <= 0 || tagID <= 0 {
+		return fmt.Errorf("delete tag: invalid input")
+	}
+	tag, err := r.db.Exec(ctx, `DELETE FROM tags WHERE id = $1 AND user_id = $2;`, tagID, userID)
+	if err != nil {
+		return fmt.Errorf("delete tag exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrTagNotFound
+	}
+	return nil
+}
+
+// ToggleActivityTag assigns tag to activity or removes existing assignment.
+// A transaction keeps ownership check and toggle operation atomic.
+func (r *tagRepository) ToggleActivityTag(ctx context.Context, userID, activityID, tagID int64) error {
+	if userID <= 0 || activityID <= 0 || tagID <= 0 {
+		return fmt.Errorf("toggle activity tag: invalid ids")
+	}
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("toggle activity tag begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ownQ := `
+	SELECT
+		EXISTS(SELECT 1 FROM activities WHERE id = $1 AND user_id = $3),
+		EXISTS(SELECT 1 FROM tags WHERE id = $2 AND user_id = $3);`
+	var activityOwned, tagOwned bool
+	if err := tx.QueryRow(ctx, ownQ, activityID, tagID, userID).Scan(&activityOwned, &tagOwned); err != nil {
+		return fmt.Errorf("toggle activity tag ownership: %w", err)
+	}
+	if !activityOwned {
+		return models.ErrActivityNotFound
+	}
+	if !tagOwned {
+		return models.ErrTagNotFound
+	}
+
+	delQ := `DELETE FROM activity_tags WHERE activity_id = $1 AND tag_id = $2;`
+	tag, err := tx.Exec(ctx, delQ, activityID, tagID)
+	if err != nil {
+		return fmt.Errorf("toggle activity tag delete: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return tx.Commit(ctx)
+	}
+
+	insQ := `
+	INSERT INTO activity_tags (activity_id, tag_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING;`
+	if _, err := tx.Exec(ctx, insQ, activityID, tagID); err != nil {
+		return fmt.Errorf("toggle activity tag insert: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// ListTagActivityIDs returns activities directly assigned to tag.
+func (r *tagRepository) ListTagActivityIDs(ctx context.Context, userID, tagID int64) ([]int64, error) {
+	q := `
+	SELECT at.activity_id
+	FROM activity_tags at
+	JOIN tags t ON t.id = at.tag_id
+	WHERE t.user_id = $1 AND at.tag_id = $2
+	ORDER BY at.activity_id;
+	`
+	return r.queryIDs(ctx, "tag activity ids", q, userID, tagID)
+}
+
+func (r *tagRepository) ResolveActivityIDs(ctx context.Context, userID, tagID int64) ([]int64, error) {
+	q := `
+	WITH RECURSIVE tree AS (
+		SELECT id FROM tags WHERE id = $2 AND user_id = $1
+		UNION
+		SELECT t.id FROM tags t JOIN tree ON t.parent_id = tree.id
+	)
+	SELECT DISTINCT a.id
+	FROM tree
+	JOIN activity_tags at ON at.tag_id = tree.id
+	JOIN activities a ON a.id = at.activity_id
+	WHERE a.user_id = $1 AND a.is_archived = FALSE
+	ORDER BY a.id;
+	`
+	return r.queryIDs(ctx, "resolve tag activities", q, userID, tagID)
+}
+
+// GetPeriodTagTotals sums sessions per tag including descendant tags;
+// a session is counted once per tag even if several subtags match.
+func (r *tagRepository) GetPeriodTagTotals(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) ([]models.TagDurationStat, error) {
+	if userID <= 0 || len(activityIDs) == 0 {
+		return nil, nil
+	}
+	q := `
+	WITH RECURSIVE tree AS (
+		SELECT id AS root_id, id AS tag_id FROM tags WHERE user_id = $1
+		UNION
+		SELECT tree.root_id, t.id FROM tags t JOIN tree ON t.parent_id = tree.tag_id
+	),
+	root_activities AS (
+		SELECT DISTINCT tree.root_id, at.activity_id
+		FROM tree
+		JOIN activity_tags at ON at.tag_id = tree.tag_id
+	)
+	SELECT ra.root_id,
+	       COALESCE(SUM(s.end_at - s.start_at), interval '0') AS total_dur,
+	       COUNT(*) AS sessions
+	FROM root_activities ra
+	JOIN activity_sessions s ON s.activity_id = ra.activity_id
+	JOIN activities a ON a.id = s.activity_id
+	WHERE s.user_id = $1
+	  AND a.is_archived = FALSE
+	  AND s.end_at IS NOT NULL
+	  AND s.start_at >= $2
+	  AND s.start_at < $3
+	  AND s.activity_id = ANY($4)
+	GROUP BY ra.root_id
+	ORDER BY total_dur DESC;
+	`
+	rows, err := r.db.Query(ctx, q, userID, from.UTC(), to.UTC(), activityIDs)
+	if err != nil {
+		return nil, fmt.Errorf("period tag totals query: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]models.TagDurationStat, 0, 16)
+	for rows.Next() {
+		var item models.TagDurationStat
+		if err := rows.Scan(&item.TagID, &item.Duration, &item.Sessions); err != nil {
+			return nil, fmt.Errorf("period tag totals scan: %w", err)
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("period tag totals rows: %w", err)
+	}
+	return out, nil
+}
+
+func (r *tagRepository) queryIDs(ctx context.Context, op, q string, args ...any) ([]int64, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s query: %w", op, err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0, 16)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s rows: %w", op, err)
+	}
+	return ids, nil
+}
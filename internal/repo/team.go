@@ -0,0 +1,317 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TeamRepository stores group teams, memberships and sharing choices.
+type TeamRepository interface {
+	// Upsert creates team for group chat or reactivates existing one.
+	Upsert(ctx context.Context, chatID int64, title string) (models.Team, error)
+	SetActive(ctx context.Context, chatID int64, active bool) error
+	// MigrateChat moves team to new chat id when group becomes supergroup.
+	MigrateChat(ctx context.Context, fromChatID, toChatID int64) error
+	GetByChat(ctx context.Context, chatID int64) (models.Team, error)
+	UpdateSettings(ctx context.Context, teamID int64, s models.TeamSettings) error
+	// Join adds member; returns false when user already was a member.
+	Join(ctx context.Context, teamID, userID int64, displayName string) (bool, error)
+	Leave(ctx context.Context, teamID, userID int64) error
+	// ListMemberships returns user's memberships in active teams.
+	ListMemberships(ctx context.Context, userID int64) ([]models.TeamMembership, error)
+	GetMembership(ctx context.Context, teamID, userID int64) (models.TeamMembership, error)
+	SetSharing(ctx context.Context, teamID, userID int64, sharing bool) error
+	// ToggleActivity shares or unshares user's own activity with team.
+	ToggleActivity(ctx context.Context, teamID, userID, activityID int64) error
+	// Leaderboard sums opted-in members' shared activities within [from, to).
+	Leaderboard(ctx context.Context, teamID int64, from, to time.Time) ([]models.LeaderboardEntry, error)
+	ListDigestEnabled(ctx context.Context) ([]models.Team, error)
+	// ClaimDigest marks digest for period as sent; returns false if it already was.
+	ClaimDigest(ctx context.Context, teamID int64, periodStart time.Time) (bool, error)
+	ReleaseDigest(ctx context.Context, teamID int64, periodStart time.Time) error
+}
+
+type teamRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTeamRepository creates team repository backed by pgx pool.
+func NewTeamRepository(db *pgxpool.Pool) TeamRepository {
+	return &teamRepository{db: db}
+}
+
+const teamColumns = `t.id, t.chat_id, t.title, t.is_active, t.digest_enabled, t.digest_weekday, t.digest_hour, t.leaderboard_days, t.last_digest_at, t.created_at`
+
+func (r *teamRepository) Upsert(ctx context.Context, chatID int64, title string) (models.Team, error) {
+	q := `
+	INSERT INTO teams AS t (chat_id, title)
+	VALUES ($1, $2)
+	ON CONFLICT (chat_id)
+	DO UPDATE SET title = EXCLUDED.title, is_active = TRUE
+	RETURNING ` + teamColumns + `;
+	`
+	team, err := scanTeam(r.db.QueryRow(ctx, q, chatID, title))
+	if err != nil {
+		return models.Team{}, fmt.Errorf("upsert team: %w", err)
+	}
+	return team, nil
+}
+
+func (r *teamRepository) SetActive(ctx context.Context, chatID int64, active bool) error {
+	q := `UPDATE teams SET is_active = $2 WHERE chat_id = $1;`
+	if _, err := r.db.Exec(ctx, q, chatID, active); err != nil {
+		return fmt.Errorf("set team active: %w", err)
+	}
+	return nil
+}
+
+func (r *teamRepository) MigrateChat(ctx context.Context, fromChatID, toChatID int64) error {
+	q := `UPDATE teams SET chat_id = $2 WHERE chat_id = $1;`
+	if _, err := r.db.Exec(ctx, q, fromChatID, toChatID); err != nil {
+		return fmt.Errorf("migrate team chat: %w", err)
+	}
+	return nil
+}
+
+func (r *teamRepository) GetByChat(ctx context.Context, chatID int64) (models.Team, error) {
+	q := `SELECT ` + teamColumns + ` FROM teams t WHERE t.chat_id = $1 AND t.is_active = TRUE;`
+	team, err := scanTeam(r.db.QueryRow(ctx, q, chatID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Team{}, models.ErrTeamNotFound
+		}
+		return models.Team{}, fmt.Errorf("get team: %w", err)
+	}
+	return team, nil
+}
+
+func (r *teamRepository) UpdateSettings(ctx context.Context, teamID int64, s models.TeamSettings) error {
+	q := `
+	UPDATE teams
+	SET digest_enabled = $2, digest_weekday = $3, digest_hour = $4, leaderboard_days = $5
+	WHERE id = $1;
+	`
+	tag, err := r.db.Exec(ctx, q, teamID, s.DigestEnabled, int(s.DigestWeekday), s.DigestHour, s.LeaderboardDays)
+	if err != nil {
+		return fmt.Errorf("update team settings: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrTeamNotFound
+	}
+	return nil
+}
+
+func (r *teamRepository) Join(ctx context.Context, teamID, userID int64, displayName string) (bool, error) {
+	q := `
+	INSERT INTO team_members (team_id, user_id, display_name)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (team_id, user_id) DO UPDATE SET display_name = EXCLUDED.display_name
+	RETURNING (xmax = 0);
+	`
+	var inserted bool
+	if err := r.db.QueryRow(ctx, q, teamID, userID, displayName).Scan(&inserted); err != nil {
+		return false, fmt.Errorf("join team: %w", err)
+	}
+	return inserted, nil
+}
+
+func (r *teamRepository) Leave(ctx context.Context, teamID, userID int64) error {
+	q := `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2;`
+	tag, err := r.db.Exec(ctx, q, teamID, userID)
+	if err != nil {
+		return fmt.Errorf("leave team: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrNotTeamMember
+	}
+	return nil
+}
+
+const membershipSelect = `
+	SELECT ` + teamColumns + `, tm.display_name, tm.sharing,
+		COALESCE((
+			SELECT array_agg(sa.activity_id ORDER BY sa.activity_id)
+			FROM team_shared_activities sa
+			WHERE sa.team_id = tm.team_id AND sa.user_id = tm.user_id
+		), '{}')
+	FROM team_members tm
+	JOIN teams t ON t.id = tm.team_id
+	`
+
+func (r *teamRepository) ListMemberships(ctx context.Context, userID int64) ([]models.TeamMembership, error) {
+	q := membershipSelect + `
+	WHERE tm.user_id = $1 AND t.is_active = TRUE
+	ORDER BY tm.joined_at;
+	`
+	rows, err := r.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var out []models.TeamMembership
+	for rows.Next() {
+		m, err := scanMembership(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list memberships scan: %w", err)
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list memberships rows: %w", err)
+	}
+	return out, nil
+}
+
+func (r *teamRepository) GetMembership(ctx context.Context, teamID, userID int64) (models.TeamMembership, error) {
+	q := membershipSelect + `WHERE tm.team_id = $1 AND tm.user_id = $2 AND t.is_active = TRUE;`
+	m, err := scanMembership(r.db.QueryRow(ctx, q, teamID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.TeamMembership{}, models.ErrNotTeamMember
+		}
+		return models.TeamMembership{}, fmt.Errorf("get membership: %w", err)
+	}
+	return m, nil
+}
+
+func (r *teamRepository) SetSharing(ctx context.Context, teamID, userID int64, sharing bool) error {
+	q := `UPDATE team_members SET sharing = $3 WHERE team_id = $1 AND user_id = $2;`
+	tag, err := r.db.Exec(ctx, q, teamID, userID, sharing)
+	if err != nil {
+		return fmt.Errorf("set team sharing: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrNotTeamMember
+	}
+	return nil
+}
+
+// ToggleActivity deletes share row if present, otherwise inserts it for member's own activity.
+func (r *teamRepository) ToggleActivity(ctx context.Context, teamID, userID, activityID int64) error {
+	q := `
+	WITH del AS (
+		DELETE FROM team_shared_activities
+		WHERE team_id = $1 AND user_id = $2 AND activity_id = $3
+		RETURNING 1
+	)
+	INSERT INTO team_shared_activities (team_id, user_id, activity_id)
+	SELECT $1, $2, a.id
+	FROM activities a
+	JOIN team_members tm ON tm.team_id = $1 AND tm.user_id = $2
+	WHERE a.id = $3 AND a.user_id = $2
+	  AND NOT EXISTS (SELECT 1 FROM del);
+	`
+	if _, err := r.db.Exec(ctx, q, teamID, userID, activityID); err != nil {
+		return fmt.Errorf("toggle team activity: %w", err)
+	}
+	return nil
+}
+
+func (r *teamRepository) Leaderboard(ctx context.Context, teamID int64, from, to time.Time) ([]models.LeaderboardEntry, error) {
+	q := `
+	SELECT tm.user_id, tm.display_name,
+		COALESCE(SUM(EXTRACT(EPOCH FROM (LEAST(s.end_at, $3) - GREATEST(s.start_at, $2)))), 0)::bigint AS secs,
+		COUNT(s.id)
+	FROM team_members tm
+	LEFT JOIN team_shared_activities sa ON sa.team_id = tm.team_id AND sa.user_id = tm.user_id
+	LEFT JOIN activity_sessions s ON s.user_id = tm.user_id
+		AND s.activity_id = sa.activity_id
+		AND s.end_at IS NOT NULL
+		AND s.end_at > $2 AND s.start_at < $3
+	WHERE tm.team_id = $1 AND tm.sharing = TRUE
+	GROUP BY tm.user_id, tm.display_name
+	ORDER BY secs DESC, tm.display_name;
+	`
+	rows, err := r.db.Query(ctx, q, teamID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("team leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var out []models.LeaderboardEntry
+	for rows.Next() {
+		var e models.LeaderboardEntry
+		var secs int64
+		if err := rows.Scan(&e.UserID, &e.Name, &secs, &e.Sessions); err != nil {
+			return nil, fmt.Errorf("team leaderboard scan: %w", err)
+		}
+		e.Total = time.Duration(secs) * time.Second
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("team leaderboard rows: %w", err)
+	}
+	return out, nil
+}
+
+func (r *teamRepository) ListDigestEnabled(ctx context.Context) ([]models.Team, error) {
+	q := `SELECT ` + teamColumns + ` FROM teams t WHERE t.is_active = TRUE AND t.digest_enabled = TRUE ORDER BY t.id;`
+	rows, err := r.db.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("list team digests: %w", err)
+	}
+	defer rows.Close()
+
+	var out []models.Team
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list team digests scan: %w", err)
+		}
+		out = append(out, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list team digests rows: %w", err)
+	}
+	return out, nil
+}
+
+// ClaimDigest is conditional update, so concurrent claims for one period are exclusive.
+func (r *teamRepository) ClaimDigest(ctx context.Context, teamID int64, periodStart time.Time) (bool, error) {
+	q := `
+	UPDATE teams SET last_digest_at = $2
+	WHERE id = $1 AND (last_digest_at IS NULL OR last_digest_at < $2);
+	`
+	tag, err := r.db.Exec(ctx, q, teamID, periodStart)
+	if err != nil {
+		return false, fmt.Errorf("claim team digest: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *teamRepository) ReleaseDigest(ctx context.Context, teamID int64, periodStart time.Time) error {
+	q := `UPDATE teams SET last_digest_at = NULL WHERE id = $1 AND last_digest_at = $2;`
+	if _, err := r.db.Exec(ctx, q, teamID, periodStart); err != nil {
+		return fmt.Errorf("release team digest: %w", err)
+	}
+	return nil
+}
+
+func scanTeam(row pgx.Row) (models.Team, error) {
+	var t models.Team
+	var weekday, hour, days int16
+	err := row.Scan(&t.ID, &t.ChatID, &t.Title, &t.Active, &t.Settings.DigestEnabled, &weekday, &hour, &days, &t.LastDigestAt, &t.CreatedAt)
+	t.Settings.DigestWeekday = time.Weekday(weekday)
+	t.Settings.DigestHour = int(hour)
+	t.Settings.LeaderboardDays = int(days)
+	return t, err
+}
+
+func scanMembership(row pgx.Row) (models.TeamMembership, error) {
+	var m models.TeamMembership
+	var weekday, hour, days int16
+	t := &m.Team
+	err := row.Scan(&t.ID, &t.ChatID, &t.Title, &t.Active, &t.Settings.DigestEnabled, &weekday, &hour, &days, &t.LastDigestAt, &t.CreatedAt,
+		&m.DisplayName, &m.Sharing, &m.ActivityIDs)
+	t.Settings.DigestWeekday = time.Weekday(weekday)
+	t.Settings.DigestHour = int(hour)
+	t.Settings.LeaderboardDays = int(days)
+	return m, err
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 	"tracker-bot/internal/models"
@@ -14,9 +15,26 @@ import (
 type TimerRepository interface {
 	UpsertInterval(ctx context.Context, userID int64, intervalMin int, nextPingAt time.Time) error
 	ListDueUsers(ctx context.Context, now time.Time, limit int) ([]models.TimerDueUser, error)
+	ListSchedule(ctx context.Context) ([]models.TimerSchedule, error)
+	// ClaimDueUsers locks due rows, advances next ping and records delivery in one statement.
+	ClaimDueUsers(ctx context.Context, now time.Time, limit int) ([]models.TimerDueUser, error)
+	MarkDelivered(ctx context.Context, userID int64, scheduledAt, sentAt time.Time) error
+	PruneDeliveries(ctx context.Context, before time.Time) (int64, error)
 	SetNextPing(ctx context.Context, userID int64, nextPingAt time.Time) error
 	GetInterval(ctx context.Context, userID int64) (int, error)
 	Disable(ctx context.Context, userID int64) error
+	// GetSettings returns saved settings; false when user never configured timer.
+	GetSettings(ctx context.Context, userID int64) (models.TimerSettings, bool, error)
+	// SaveInterval stores default interval without enabling timer.
+	SaveInterval(ctx context.Context, userID int64, intervalMin int) error
+	SetQuietHours(ctx context.Context, userID int64, quiet models.QuietHours) error
+	SetAdaptive(ctx context.Context, userID int64, adaptive models.AdaptiveInterval) error
+	// GetAdaptiveState returns interval and answer history; false when user never configured timer.
+	GetAdaptiveState(ctx context.Context, userID int64) (models.AdaptiveState, bool, error)
+	// SaveAdaptiveState stores interval and answer history only if row still matches cur,
+	// moving pending prompt by interval change. Zero time means row changed meanwhile
+	// or timer has no pending prompt.
+	SaveAdaptiveState(ctx context.Context, userID int64, cur, next models.AdaptiveState) (time.Time, error)
 }
 
 type timerRepository struct {
@@ -38,6 +56,7 @@ func (r *timerRepository) UpsertInterval(ctx context.Context, userID int64, inte
 		interval_min = EXCLUDED.interval_min,
 		next_ping_at = EXCLUDED.next_ping_at,
 		enabled = TRUE,
+		same_answer_streak = 0,
 		updated_at = now();
 	`
 	if _, err := r.db.Exec(ctx, q, userID, intervalMin, nextPingAt); err != nil {
@@ -79,6 +98,115 @@ func (r *timerRepository) ListDueUsers(ctx context.Context, now time.Time, limit
 	return out, nil
 }
 
+// ListSchedule returns next ping time of every enabled timer.
+func (r *timerRepository) ListSchedule(ctx context.Context) ([]models.TimerSchedule, error) {
+	q := `
+	SELECT user_id, next_ping_at
+	FROM user_timer_settings
+	WHERE enabled = TRUE AND next_ping_at IS NOT NULL;
+	`
+	rows, err := r.db.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("list timer schedule query: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]models.TimerSchedule, 0, 256)
+	for rows.Next() {
+		var item models.TimerSchedule
+		if err := rows.Scan(&item.DBUserID, &item.NextPingAt); err != nil {
+			return nil, fmt.Errorf("list timer schedule scan: %w", err)
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list timer schedule rows: %w", err)
+	}
+	return out, nil
+}
+
+// ClaimDueUsers is safe to call from several instances at once:
+// FOR UPDATE SKIP LOCKED hands each due row to one caller, next_ping_at moves
+// forward before the prompt is sent, and the prompt_deliveries primary key
+// drops anything already claimed. A crash after claim loses one prompt
+// instead of sending it twice.
+func (r *timerRepository) ClaimDueUsers(ctx context.Context, now time.Time, limit int) ([]models.TimerDueUser, error) {
+	q := `
+	WITH due AS (
+		SELECT user_id, interval_min, next_ping_at
+		FROM user_timer_settings
+		WHERE enabled = TRUE
+		  AND next_ping_at IS NOT NULL
+		  AND next_ping_at <= $1
+		ORDER BY next_ping_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	),
+	advanced AS (
+		UPDATE user_timer_settings uts
+		SET next_ping_at = $1 + make_interval(mins => due.interval_min), updated_at = now()
+		FROM due
+		WHERE uts.user_id = due.user_id
+		RETURNING uts.user_id
+	),
+	claimed AS (
+		INSERT INTO prompt_deliveries (user_id, scheduled_at)
+		SELECT user_id, next_ping_at FROM due
+		ON CONFLICT DO NOTHING
+		RETURNING user_id, scheduled_at
+	)
+	SELECT due.user_id, u.tg_user_id, due.interval_min, claimed.scheduled_at,
+		u.timezone, COALESCE(uts.quiet_from_min, 0), COALESCE(uts.quiet_to_min, 0)
+	FROM due
+	JOIN claimed ON claimed.user_id = due.user_id
+	JOIN users u ON u.id = due.user_id
+	JOIN user_timer_settings uts ON uts.user_id = due.user_id
+	ORDER BY claimed.scheduled_at;
+	`
+
+	rows, err := r.db.Query(ctx, q, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("claim due users query: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]models.TimerDueUser, 0, limit)
+	for rows.Next() {
+		var item models.TimerDueUser
+		if err := rows.Scan(&item.DBUserID, &item.TgUserID, &item.IntervalMin, &item.ScheduledAt,
+			&item.TimeZone, &item.Quiet.FromMin, &item.Quiet.ToMin); err != nil {
+			return nil, fmt.Errorf("claim due users scan: %w", err)
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("claim due users rows: %w", err)
+	}
+	return out, nil
+}
+
+// MarkDelivered stores send time for claimed prompt.
+func (r *timerRepository) MarkDelivered(ctx context.Context, userID int64, scheduledAt, sentAt time.Time) error {
+	q := `
+	UPDATE prompt_deliveries
+	SET sent_at = $3
+	WHERE user_id = $1 AND scheduled_at = $2;
+	`
+	if _, err := r.db.Exec(ctx, q, userID, scheduledAt, sentAt); err != nil {
+		return fmt.Errorf("mark prompt delivered: %w", err)
+	}
+	return nil
+}
+
+// PruneDeliveries removes delivery records claimed before given time.
+func (r *timerRepository) PruneDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM prompt_deliveries WHERE claimed_at < $1;`, before)
+	if err != nil {
+		return 0, fmt.Errorf("prune prompt deliveries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
 // SetNextPing updates next scheduled ping time for user.
 func (r *timerRepository) SetNextPing(ctx context.Context, userID int64, nextPingAt time.Time) error {
 	q := `
@@ -123,3 +251,127 @@ func (r *timerRepository) Disable(ctx context.Context, userID int64) error {
 	}
 	return nil
 }
+
+func (r *timerRepository) GetSettings(ctx context.Context, userID int64) (models.TimerSettings, bool, error) {
+	q := `
+	SELECT interval_min, enabled, COALESCE(quiet_from_min, 0), COALESCE(quiet_to_min, 0),
+		adaptive, min_interval_min, max_interval_min
+	FROM user_timer_settings
+	WHERE user_id = $1;
+	`
+	var s models.TimerSettings
+	err := r.db.QueryRow(ctx, q, userID).Scan(&s.IntervalMin, &s.Enabled, &s.Quiet.FromMin, &s.Quiet.ToMin,
+		&s.Adaptive.Enabled, &s.Adaptive.MinMin, &s.Adaptive.MaxMin)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.TimerSettings{}, false, nil
+	}
+	if err != nil {
+		return models.TimerSettings{}, false, fmt.Errorf("get timer settings: %w", err)
+	}
+	return s, true, nil
+}
+
+func (r *timerRepository) SaveInterval(ctx context.Context, userID int64, intervalMin int) error {
+	q := `
+	INSERT INTO user_timer_settings (user_id, interval_min, enabled, updated_at)
+	VALUES ($1, $2, FALSE, now())
+	ON CONFLICT (user_id)
+	DO UPDATE SET interval_min = EXCLUDED.interval_min, updated_at = now();
+	`
+	if _, err := r.db.Exec(ctx, q, userID, intervalMin); err != nil {
+		return fmt.Errorf("save interval: %w", err)
+	}
+	return nil
+}
+
+// SetQuietHours stores window; disabled window is stored as NULLs.
+func (r *timerRepository) SetQuietHours(ctx context.Context, userID int64, quiet models.QuietHours) error {
+	var from, to *int
+	if quiet.Enabled() {
+		from, to = &quiet.FromMin, &quiet.ToMin
+	}
+	q := `
+	INSERT INTO user_timer_settings (user_id, enabled, quiet_from_min, quiet_to_min, updated_at)
+	VALUES ($1, FALSE, $2, $3, now())
+	ON CONFLICT (user_id)
+	DO UPDATE SET quiet_from_min = EXCLUDED.quiet_from_min, quiet_to_min = EXCLUDED.quiet_to_min, updated_at = now();
+	`
+	if _, err := r.db.Exec(ctx, q, userID, from, to); err != nil {
+		return fmt.Errorf("set quiet hours: %w", err)
+	}
+	return nil
+}
+
+func (r *timerRepository) SetAdaptive(ctx context.Context, userID int64, adaptive models.AdaptiveInterval) error {
+	q := `
+	INSERT INTO user_timer_settings (user_id, enabled, adaptive, min_interval_min, max_interval_min, updated_at)
+	VALUES ($1, FALSE, $2, $3, $4, now())
+	ON CONFLICT (user_id)
+	DO UPDATE SET
+		adaptive = EXCLUDED.adaptive,
+		min_interval_min = EXCLUDED.min_interval_min,
+		max_interval_min = EXCLUDED.max_interval_min,
+		same_answer_streak = 0,
+		updated_at = now();
+	`
+	if _, err := r.db.Exec(ctx, q, userID, adaptive.Enabled, adaptive.MinMin, adaptive.MaxMin); err != nil {
+		return fmt.Errorf("set adaptive interval: %w", err)
+	}
+	return nil
+}
+
+func (r *timerRepository) GetAdaptiveState(ctx context.Context, userID int64) (models.AdaptiveState, bool, error) {
+	q := `
+	SELECT interval_min, enabled, adaptive, min_interval_min, max_interval_min,
+		COALESCE(last_answer_activity_id, 0), same_answer_streak
+	FROM user_timer_settings
+	WHERE user_id = $1;
+	`
+	var st models.AdaptiveState
+	err := r.db.QueryRow(ctx, q, userID).Scan(&st.IntervalMin, &st.Enabled, &st.Adaptive.Enabled,
+		&st.Adaptive.MinMin, &st.Adaptive.MaxMin, &st.LastActivityID, &st.SameAnswerStreak)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.AdaptiveState{}, false, nil
+	}
+	if err != nil {
+		return models.AdaptiveState{}, false, fmt.Errorf("get adaptive state: %w", err)
+	}
+	return st, true, nil
+}
+
+// SaveAdaptiveState keeps pending prompt anchored to the previous one: next_ping_at
+// shifts by interval difference, but never into the past.
+func (r *timerRepository) SaveAdaptiveState(ctx context.Context, userID int64, cur, next models.AdaptiveState) (time.Time, error) {
+	var lastActivityID *int64
+	if next.LastActivityID > 0 {
+		lastActivityID = &next.LastActivityID
+	}
+	q := `
+	UPDATE user_timer_settings
+	SET interval_min = $4,
+		last_answer_activity_id = $5,
+		same_answer_streak = $6,
+		next_ping_at = CASE
+			WHEN enabled AND next_ping_at IS NOT NULL
+			THEN GREATEST(next_ping_at + make_interval(mins => $4 - interval_min), now())
+			ELSE next_ping_at
+		END,
+		updated_at = now()
+	WHERE user_id = $1 AND interval_min = $2 AND same_answer_streak = $3
+	  AND COALESCE(last_answer_activity_id, 0) = $7
+	RETURNING CASE WHEN enabled THEN next_ping_at END;
+	`
+	var nextPingAt *time.Time
+	err := r.db.QueryRow(ctx, q, userID, cur.IntervalMin, cur.SameAnswerStreak,
+		next.IntervalMin, lastActivityID, next.SameAnswerStreak, cur.LastActivityID).Scan(&nextPingAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("save adaptive state: %w", err)
+	}
+	if nextPingAt == nil {
+		return time.Time{}, nil
+	}
+	return *nextPingAt, nil
+}
@@ -0,0 +1,155 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// startPostgres runs migrated database in a container; test is skipped without Docker.
+func startPostgres(t *testing.T) string {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx := context.Background()
+
+	ctr, err := postgres.Run(ctx, "postgres:16-alpine",
+		postgres.WithDatabase("tracker"),
+		postgres.WithUsername("tracker"),
+		postgres.WithPassword("tracker"),
+		postgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	if err != nil {
+		t.Fatalf("start postgres: %v", err)
+	}
+	dsn, err := ctr.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("postgres dsn: %v", err)
+	}
+
+	m, err := migrate.New("file://../../migrations", dsn)
+	if err != nil {
+		t.Fatalf("migrate new: %v", err)
+	}
+	defer m.Close()
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return dsn
+}
+
+// TestClaimDueUsersTwoSchedulers ticks two schedulers, each with its own pool like separate
+// instances, over one database at the same moments and checks every due prompt is claimed once.
+func TestClaimDueUsersTwoSchedulers(t *testing.T) {
+	const (
+		users       = 200
+		intervalMin = 15
+		ticks       = 60
+		batch       = 7
+	)
+	ctx := context.Background()
+	dsn := startPostgres(t)
+
+	pools := make([]*pgxpool.Pool, 2)
+	for i := range pools {
+		pool, err := pgxpool.New(ctx, dsn)
+		if err != nil {
+			t.Fatalf("pool %d: %v", i, err)
+		}
+		defer pool.Close()
+		pools[i] = pool
+	}
+
+	// User i is first due i%interval minutes after start, then every interval.
+	start := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < users; i++ {
+		q := `
+		WITH u AS (INSERT INTO users (tg_user_id) VALUES ($1) RETURNING id)
+		INSERT INTO user_timer_settings (user_id, interval_min, next_ping_at, enabled)
+		SELECT id, $2, $3, TRUE FROM u;
+		`
+		next := start.Add(time.Duration(i%intervalMin) * time.Minute)
+		if _, err := pools[0].Exec(ctx, q, int64(1000+i), intervalMin, next); err != nil {
+			t.Fatalf("seed user %d: %v", i, err)
+		}
+	}
+
+	type key struct {
+		userID      int64
+		scheduledAt time.Time
+	}
+	var (
+		mu      sync.Mutex
+		claimed = make(map[key]int)
+		perUser = make(map[int64]int)
+	)
+	claim := func(r TimerRepository, now time.Time) error {
+		for {
+			due, err := r.ClaimDueUsers(ctx, now, batch)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			for _, item := range due {
+				claimed[key{item.DBUserID, item.ScheduledAt.UTC()}]++
+				perUser[item.DBUserID]++
+			}
+			mu.Unlock()
+			if len(due) < batch {
+				return nil
+			}
+		}
+	}
+
+	repos := []TimerRepository{NewTimerRepository(pools[0]), NewTimerRepository(pools[1])}
+	for tick := 0; tick < ticks; tick++ {
+		now := start.Add(time.Duration(tick) * time.Minute)
+		var wg sync.WaitGroup
+		errs := make([]error, len(repos))
+		for i, r := range repos {
+			wg.Add(1)
+			go func(i int, r TimerRepository) {
+				defer wg.Done()
+				errs[i] = claim(r, now)
+			}(i, r)
+		}
+		wg.Wait()
+		for i, err := range errs {
+			if err != nil {
+				t.Fatalf("scheduler %d tick %d: %v", i, tick, err)
+			}
+		}
+	}
+
+	for k, n := range claimed {
+		if n != 1 {
+			t.Errorf("user %d prompt at %s claimed %d times", k.userID, k.scheduledAt.Format(time.RFC3339), n)
+		}
+	}
+	wantPerUser := ticks / intervalMin
+	if len(perUser) != users {
+		t.Fatalf("claimed prompts of %d users, want %d", len(perUser), users)
+	}
+	for userID, n := range perUser {
+		if n != wantPerUser {
+			t.Errorf("user %d got %d prompts, want %d", userID, n, wantPerUser)
+		}
+	}
+
+	var deliveries int
+	if err := pools[0].QueryRow(ctx, `SELECT count(*) FROM prompt_deliveries;`).Scan(&deliveries); err != nil {
+		t.Fatalf("count deliveries: %v", err)
+	}
+	if deliveries != len(claimed) {
+		t.Errorf("prompt_deliveries has %d rows, want %d", deliveries, len(claimed))
+	}
+}
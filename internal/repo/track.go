@@ -8,8 +8,8 @@ import (
 	"time"
 	errlocal "tracker-bot/internal/models"
 
-	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,15 +19,28 @@ type Activity struct {
 	Name       string
 	Emoji      string
 	IsArchived bool
+	SortOrder  int
 	CreatedAt  time.Time
+	DeletedAt  time.Time
 }
 type TrackerRepository interface {
 	Create(ctx context.Context, userID int64, name, emoji string) (Activity, error)
+	// CreateMany inserts activities in given order and skips names that already exist; returns created ones.
+	CreateMany(ctx context.Context, userID int64, items []errlocal.ActivityTemplate) ([]Activity, error)
+	Rename(ctx context.Context, userID, activityID int64, name string) error
+	SetEmoji(ctx context.Context, userID, activityID int64, emoji string) error
+	Move(ctx context.Context, userID, activityID int64, delta int) error
+	Merge(ctx context.Context, userID, fromID, toID int64) (int64, error)
 	ListActive(ctx context.Context, userID int64) ([]Activity, error)
 	ListArchived(ctx context.Context, userID int64) ([]Activity, error)
 	SelectedListActive(ctx context.Context, userID int64) ([]int64, error)
 	ToggleSelectedActive(ctx context.Context, userID, activityID int64) error
-	DeleteSelected(ctx context.Context, userID int64) (int64, error)
+	// DeleteSelected moves selected activities to trash; returns count and deletion time used for undo.
+	DeleteSelected(ctx context.Context, userID int64) (int64, time.Time, error)
+	ListTrashed(ctx context.Context, userID int64) ([]Activity, error)
+	RestoreTrashed(ctx context.Context, userID, activityID int64) error
+	UndoDelete(ctx context.Context, userID int64, deletedAt time.Time) (int64, error)
+	PurgeWithHistory(ctx context.Context, userID, activityID int64) (int64, error)
 	ArchiveSelected(ctx context.Context, userID int64) (int64, error)
 	RestoreArchived(ctx context.Context, userID, activityID int64) error
 	DeleteArchivedForever(ctx context.Context, userID, activityID int64) error
@@ -35,6 +48,8 @@ type TrackerRepository interface {
 	GetTodayActivities(ctx context.Context, userID int64) ([]Activity, []time.Duration, []int, error)
 	GetPeriodActivities(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) ([]Activity, []time.Duration, []int, time.Duration, int, error)
 	GetPeriodMonthlyTotals(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) ([]time.Time, []time.Duration, error)
+	// GetPomodoroDailyCounts returns completed pomodoro focus blocks per UTC day within [from, to).
+	GetPomodoroDailyCounts(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) ([]time.Time, []int, error)
 	GetMonthDailyTotals(ctx context.Context, userID int64, month time.Time, activityIDs []int64) (map[int]time.Duration, error)
 	GetPeriodBuckets(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64, granularity string) ([]time.Time, []time.Duration, error)
 	GetLastTrackedActiveActivity(ctx context.Context, userID int64) (Activity, bool, error)
@@ -62,10 +77,13 @@ func (r *trackRepository) Create(ctx context.Context, userID int64, name, emoji
 
 	emoji = strings.TrimSpace(emoji)
 
+	// New activities go to the end of the user's manual order.
 	q := `
-	INSERT INTO activities (user_id, name, emoji)
-	VALUES ($1, $2, $3)
-	RETURNING id, user_id, name, emoji, is_archived, created_at;
+	INSERT INTO activities (user_id, name, emoji, sort_order)
+	SELECT $1, $2, $3, COALESCE(MAX(sort_order), 0) + 1
+	FROM activities
+	WHERE user_id = $1
+	RETURNING id, user_id, name, emoji, is_archived, sort_order, created_at;
 	`
 
 	var a Activity
@@ -75,6 +93,7 @@ func (r *trackRepository) Create(ctx context.Context, userID int64, name, emoji
 		&a.Name,
 		&a.Emoji,
 		&a.IsArchived,
+		&a.SortOrder,
 		&a.CreatedAt,
 	)
 	if err != nil {
@@ -90,16 +109,253 @@ func (r *trackRepository) Create(ctx context.Context, userID int64, name, emoji
 	return a, nil
 }
 
+// CreateMany relies on uq_activities_user_lower_name, so names taken by active,
+// archived or concurrently created activities are skipped instead of failing the batch.
+func (r *trackRepository) CreateMany(ctx context.Context, userID int64, items []errlocal.ActivityTemplate) ([]Activity, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("create activities: invalid userID")
+	}
+	names := make([]string, 0, len(items))
+	emojis := make([]string, 0, len(items))
+	for _, it := range items {
+		names = append(names, it.Name)
+		emojis = append(emojis, it.Emoji)
+	}
+
+	q := `
+	INSERT INTO activities (user_id, name, emoji, sort_order)
+	SELECT $1, t.name, t.emoji,
+		COALESCE((SELECT MAX(sort_order) FROM activities WHERE user_id = $1), 0) + t.ord
+	FROM unnest($2::text[], $3::text[]) WITH ORDINALITY AS t(name, emoji, ord)
+	ORDER BY t.ord
+	ON CONFLICT (user_id, lower(name)) WHERE deleted_at IS NULL DO NOTHING
+	RETURNING id, user_id, name, emoji, is_archived, sort_order, created_at;
+	`
+	rows, err := r.db.Query(ctx, q, userID, names, emojis)
+	if err != nil {
+		return nil, fmt.Errorf("create activities: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Activity
+	for rows.Next() {
+		var a Activity
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Name, &a.Emoji, &a.IsArchived, &a.SortOrder, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("create activities scan: %w", err)
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("create activities rows: %w", err)
+	}
+	return out, nil
+}
+
+func (r *trackRepository) Rename(ctx context.Context, userID, activityID int64, name string) error {
+	if userID <= 0 || activityID <= 0 {
+		return fmt.Errorf("rename activity: invalid input")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("rename activity: empty name")
+	}
+
+	q := `
+	UPDATE activities
+	SET name = $3
+	WHERE id = $1 AND user_id = $2;
+	`
+	tag, err := r.db.Exec(ctx, q, activityID, userID, name)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		// 23505 is PostgreSQL unique_violation on uq_activities_user_lower_name.
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return errlocal.ErrActivityExists
+		}
+		return fmt.Errorf("rename activity exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errlocal.ErrActivityNotFound
+	}
+	return nil
+}
+
+func (r *trackRepository) SetEmoji(ctx context.Context, userID, activityID int64, emoji string) error {
+	if userID <= 0 || activityID <= 0 {
+		return fmt.Errorf("set activity emoji: invalid input")
+	}
+	q := `
+	UPDATE activities
+	SET emoji = $3
+	WHERE id = $1 AND user_id = $2;
+	`
+	tag, err := r.db.Exec(ctx, q, activityID, userID, strings.TrimSpace(emoji))
+	if err != nil {
+		return fmt.Errorf("set activity emoji exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errlocal.ErrActivityNotFound
+	}
+	return nil
+}
+
+// Move shifts active activity up (delta < 0) or down (delta > 0) by one position.
+// Positions are renumbered inside the transaction so duplicates from old rows disappear.
+func (r *trackRepository) Move(ctx context.Context, userID, activityID int64, delta int) error {
+	if userID <= 0 || activityID <= 0 || delta == 0 {
+		return fmt.Errorf("move activity: invalid input")
+	}
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("move activity begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Lock user's active activities to serialize concurrent reorders.
+	listQ := `
+	SELECT id
+	FROM activities
+	WHERE user_id = $1 AND is_archived = FALSE
+	ORDER BY sort_order, lower(name), id
+	FOR UPDATE;
+	`
+	rows, err := tx.Query(ctx, listQ, userID)
+	if err != nil {
+		return fmt.Errorf("move activity list: %w", err)
+	}
+	ids := make([]int64, 0, 16)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("move activity scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("move activity rows: %w", err)
+	}
+
+	pos := -1
+	for i, id := range ids {
+		if id == activityID {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return errlocal.ErrActivityNotFound
+	}
+	next := pos + 1
+	if delta < 0 {
+		next = pos - 1
+	}
+	if next < 0 || next >= len(ids) {
+		return nil
+	}
+	ids[pos], ids[next] = ids[next], ids[pos]
+
+	updQ := `
+	UPDATE activities a
+	SET sort_order = o.pos
+	FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id, pos)
+	WHERE a.id = o.id AND a.user_id = $1;
+	`
+	if _, err := tx.Exec(ctx, updQ, userID, ids); err != nil {
+		return fmt.Errorf("move activity update: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// Merge moves all sessions, goals, tags and selection of fromID into toID and removes fromID.
+// Everything runs in one transaction; returns number of moved sessions.
+func (r *trackRepository) Merge(ctx context.Context, userID, fromID, toID int64) (int64, error) {
+	if userID <= 0 || fromID <= 0 || toID <= 0 {
+		return 0, fmt.Errorf("merge activities: invalid input")
+	}
+	if fromID == toID {
+		return 0, errlocal.ErrSameActivity
+	}
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("merge activities begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ownQ := `
+	SELECT COUNT(*)
+	FROM (
+		SELECT id FROM activities
+		WHERE id = ANY($1) AND user_id = $2
+		FOR UPDATE
+	) owned;`
+	var owned int
+	if err := tx.QueryRow(ctx, ownQ, []int64{fromID, toID}, userID).Scan(&owned); err != nil {
+		return 0, fmt.Errorf("merge activities ownership: %w", err)
+	}
+	if owned != 2 {
+		return 0, errlocal.ErrActivityNotFound
+	}
+
+	moveQ := `UPDATE activity_sessions SET activity_id = $2 WHERE activity_id = $1 AND user_id = $3;`
+	tag, err := tx.Exec(ctx, moveQ, fromID, toID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("merge activities sessions: %w", err)
+	}
+
+	// Target keeps its own goal when both activities have one for the same period.
+	goalQ := `
+	UPDATE activity_goals g
+	SET activity_id = $2, updated_at = now()
+	WHERE g.activity_id = $1
+	  AND NOT EXISTS (SELECT 1 FROM activity_goals t WHERE t.activity_id = $2 AND t.period = g.period);
+	`
+	if _, err := tx.Exec(ctx, goalQ, fromID, toID); err != nil {
+		return 0, fmt.Errorf("merge activities goals: %w", err)
+	}
+
+	tagsQ := `
+	INSERT INTO activity_tags (activity_id, tag_id)
+	SELECT $2, tag_id FROM activity_tags WHERE activity_id = $1
+	ON CONFLICT DO NOTHING;
+	`
+	if _, err := tx.Exec(ctx, tagsQ, fromID, toID); err != nil {
+		return 0, fmt.Errorf("merge activities tags: %w", err)
+	}
+
+	selQ := `
+	INSERT INTO user_selected_activities (user_id, activity_id)
+	SELECT user_id, $2 FROM user_selected_activities WHERE activity_id = $1
+	ON CONFLICT DO NOTHING;
+	`
+	if _, err := tx.Exec(ctx, selQ, fromID, toID); err != nil {
+		return 0, fmt.Errorf("merge activities selection: %w", err)
+	}
+
+	// Remaining goals/tags/selection rows of fromID are removed by ON DELETE CASCADE.
+	if _, err := tx.Exec(ctx, `DELETE FROM activities WHERE id = $1 AND user_id = $2;`, fromID, userID); err != nil {
+		return 0, fmt.Errorf("merge activities delete: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("merge activities commit: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
 func (r *trackRepository) ListActive(ctx context.Context, userID int64) ([]Activity, error) {
 	if userID <= 0 {
 		return nil, fmt.Errorf("list active: invalid userID")
 	}
 
 	q := `
-	SELECT id, user_id, name, emoji, is_archived, created_at
+	SELECT id, user_id, name, COALESCE(emoji, ''), is_archived, sort_order, created_at
 	FROM activities
 	WHERE user_id = $1 AND is_archived = false
-	ORDER BY lower(name), id;
+	ORDER BY sort_order, lower(name), id;
 	`
 
 	rows, err := r.db.Query(ctx, q, userID)
@@ -112,7 +368,7 @@ func (r *trackRepository) ListActive(ctx context.Context, userID int64) ([]Activ
 	for rows.Next() {
 		var a Activity
 		if err := rows.Scan(
-			&a.ID, &a.UserID, &a.Name, &a.Emoji, &a.IsArchived, &a.CreatedAt,
+			&a.ID, &a.UserID, &a.Name, &a.Emoji, &a.IsArchived, &a.SortOrder, &a.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("list active scan: %w", err)
 		}
@@ -162,10 +418,10 @@ func (r *trackRepository) ListArchived(ctx context.Context, userID int64) ([]Act
 	}
 
 	q := `
-	SELECT id, user_id, name, emoji, is_archived, created_at
+	SELECT id, user_id, name, COALESCE(emoji, ''), is_archived, sort_order, created_at
 	FROM activities
-	WHERE user_id = $1 AND is_archived = true
-	ORDER BY lower(name), id;
+	WHERE user_id = $1 AND is_archived = true AND deleted_at IS NULL
+	ORDER BY sort_order, lower(name), id;
 	`
 
 	rows, err := r.db.Query(ctx, q, userID)
@@ -177,7 +433,7 @@ func (r *trackRepository) ListArchived(ctx context.Context, userID int64) ([]Act
 	out := make([]Activity, 0, 16)
 	for rows.Next() {
 		var a Activity
-		if err := rows.Scan(&a.ID, &a.UserID, &a.Name, &a.Emoji, &a.IsArchived, &a.CreatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Name, &a.Emoji, &a.IsArchived, &a.SortOrder, &a.CreatedAt); err != nil {
 			return nil, fmt.Errorf("list archived scan: %w", err)
 		}
 		out = append(out, a)
@@ -240,22 +496,169 @@ func (r *trackRepository) ToggleSelectedActive(ctx context.Context, userID, acti
 	return tx.Commit(ctx)
 }
 
-func (r *trackRepository) DeleteSelected(ctx context.Context, userID int64) (int64, error) {
+// DeleteSelected soft-deletes selected activities: they are archived and marked deleted,
+// sessions stay untouched so the operation can be undone.
+func (r *trackRepository) DeleteSelected(ctx context.Context, userID int64) (int64, time.Time, error) {
 	if userID <= 0 {
-		return 0, fmt.Errorf("delete selected: invalid userID")
+		return 0, time.Time{}, fmt.Errorf("delete selected: invalid userID")
+	}
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("delete selected begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// now() is fixed for the transaction, so all rows share one deleted_at mark.
+	updateQ := `
+	WITH upd AS (
+		UPDATE activities a
+		SET deleted_at = now(), is_archived = TRUE
+		FROM user_selected_activities s
+		WHERE a.id = s.activity_id
+		  AND a.user_id = $1
+		  AND s.user_id = $1
+		  AND a.deleted_at IS NULL
+		RETURNING a.id
+	)
+	SELECT COUNT(*), now() FROM upd;
+	`
+	var deleted int64
+	var deletedAt time.Time
+	if err := tx.QueryRow(ctx, updateQ, userID).Scan(&deleted, &deletedAt); err != nil {
+		return 0, time.Time{}, fmt.Errorf("delete selected update: %w", err)
+	}
+
+	cleanupQ := `DELETE FROM user_selected_activities WHERE user_id = $1;`
+	if _, err := tx.Exec(ctx, cleanupQ, userID); err != nil {
+		return 0, time.Time{}, fmt.Errorf("delete selected cleanup: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, time.Time{}, fmt.Errorf("delete selected commit: %w", err)
+	}
+	return deleted, deletedAt, nil
+}
+
+func (r *trackRepository) ListTrashed(ctx context.Context, userID int64) ([]Activity, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("list trashed: invalid userID")
 	}
 
 	q := `
-	DELETE FROM activities a
-	USING user_selected_activities s
-	WHERE a.id = s.activity_id
-	  AND a.user_id = $1
-	  AND s.user_id = $1;
+	SELECT id, user_id, name, COALESCE(emoji, ''), is_archived, sort_order, created_at, deleted_at
+	FROM activities
+	WHERE user_id = $1 AND deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC, id;
 	`
 
-	tag, err := r.db.Exec(ctx, q, userID)
+	rows, err := r.db.Query(ctx, q, userID)
 	if err != nil {
-		return 0, fmt.Errorf("delete selected exec: %w", err)
+		return nil, fmt.Errorf("list trashed query: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Activity, 0, 16)
+	for rows.Next() {
+		var a Activity
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Name, &a.Emoji, &a.IsArchived, &a.SortOrder, &a.CreatedAt, &a.DeletedAt); err != nil {
+			return nil, fmt.Errorf("list trashed scan: %w", err)
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list trashed rows: %w", err)
+	}
+	return out, nil
+}
+
+// RestoreTrashed moves one activity from trash back to active list.
+func (r *trackRepository) RestoreTrashed(ctx context.Context, userID, activityID int64) error {
+	if userID <= 0 || activityID <= 0 {
+		return fmt.Errorf("restore trashed: invalid input")
+	}
+	q := `
+	UPDATE activities
+	SET deleted_at = NULL, is_archived = FALSE
+	WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL;
+	`
+	tag, err := r.db.Exec(ctx, q, activityID, userID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		// 23505: an active activity with the same name was created meanwhile.
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return errlocal.ErrActivityExists
+		}
+		return fmt.Errorf("restore trashed exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errlocal.ErrActivityNotFound
+	}
+	return nil
+}
+
+// UndoDelete restores all activities deleted by one DeleteSelected call.
+func (r *trackRepository) UndoDelete(ctx context.Context, userID int64, deletedAt time.Time) (int64, error) {
+	if userID <= 0 || deletedAt.IsZero() {
+		return 0, fmt.Errorf("undo delete: invalid input")
+	}
+	q := `
+	UPDATE activities
+	SET deleted_at = NULL, is_archived = FALSE
+	WHERE user_id = $1 AND deleted_at = $2;
+	`
+	tag, err := r.db.Exec(ctx, q, userID, deletedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, errlocal.ErrActivityExists
+		}
+		return 0, fmt.Errorf("undo delete exec: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// PurgeWithHistory removes archived or trashed activity together with all its sessions.
+// Goals, tags and selection rows go away by ON DELETE CASCADE; returns removed sessions count.
+func (r *trackRepository) PurgeWithHistory(ctx context.Context, userID, activityID int64) (int64, error) {
+	if userID <= 0 || activityID <= 0 {
+		return 0, fmt.Errorf("purge activity: invalid input")
+	}
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("purge activity begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ownQ := `
+	SELECT EXISTS(
+		SELECT 1
+		FROM activities
+		WHERE id = $1 AND user_id = $2 AND is_archived = TRUE
+		FOR UPDATE
+	);`
+	var owned bool
+	if err := tx.QueryRow(ctx, ownQ, activityID, userID).Scan(&owned); err != nil {
+		return 0, fmt.Errorf("purge activity ownership: %w", err)
+	}
+	if !owned {
+		return 0, errlocal.ErrActivityNotFound
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM activity_sessions WHERE activity_id = $1 AND user_id = $2;`, activityID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("purge activity sessions: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM activities WHERE id = $1 AND user_id = $2;`, activityID, userID); err != nil {
+		if isForeignKeyViolation(err) {
+			return 0, errlocal.ErrActivityHasHistory
+		}
+		return 0, fmt.Errorf("purge activity delete: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("purge activity commit: %w", err)
 	}
 	return tag.RowsAffected(), nil
 }
@@ -302,7 +705,7 @@ func (r *trackRepository) RestoreArchived(ctx context.Context, userID, activityI
 	q := `
 	UPDATE activities
 	SET is_archived = FALSE
-	WHERE id = $1 AND user_id = $2 AND is_archived = TRUE;
+	WHERE id = $1 AND user_id = $2 AND is_archived = TRUE AND deleted_at IS NULL;
 	`
 	tag, err := r.db.Exec(ctx, q, activityID, userID)
 	if err != nil {
@@ -320,10 +723,14 @@ func (r *trackRepository) DeleteArchivedForever(ctx context.Context, userID, act
 	}
 	q := `
 	DELETE FROM activities
-	WHERE id = $1 AND user_id = $2 AND is_archived = TRUE;
+	WHERE id = $1 AND user_id = $2 AND is_archived = TRUE AND deleted_at IS NULL;
 	`
 	tag, err := r.db.Exec(ctx, q, activityID, userID)
 	if err != nil {
+		// Sessions reference activity with ON DELETE RESTRICT; caller may purge with history instead.
+		if isForeignKeyViolation(err) {
+			return errlocal.ErrActivityHasHistory
+		}
 		return fmt.Errorf("delete archived forever exec: %w", err)
 	}
 	if tag.RowsAffected() == 0 {
@@ -499,6 +906,45 @@ func (r *trackRepository) GetPeriodMonthlyTotals(ctx context.Context, userID int
 	return months, durs, nil
 }
 
+func (r *trackRepository) GetPomodoroDailyCounts(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) ([]time.Time, []int, error) {
+	if userID <= 0 || len(activityIDs) == 0 {
+		return nil, nil, nil
+	}
+	q := `
+	SELECT date_trunc('day', s.start_at) AS day_start, COUNT(*)
+	FROM activity_sessions s
+	JOIN activities a ON a.id = s.activity_id
+	WHERE s.user_id = $1
+	  AND a.is_archived = FALSE
+	  AND s.source = $5
+	  AND s.start_at >= $2
+	  AND s.start_at < $3
+	  AND s.activity_id = ANY($4)
+	GROUP BY day_start
+	ORDER BY day_start;
+	`
+	rows, err := r.db.Query(ctx, q, userID, from.UTC(), to.UTC(), activityIDs, errlocal.SessionSourcePomodoro)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pomodoro daily counts query: %w", err)
+	}
+	defer rows.Close()
+	days := make([]time.Time, 0, 16)
+	counts := make([]int, 0, 16)
+	for rows.Next() {
+		var d time.Time
+		var n int
+		if err := rows.Scan(&d, &n); err != nil {
+			return nil, nil, fmt.Errorf("pomodoro daily counts scan: %w", err)
+		}
+		days = append(days, d)
+		counts = append(counts, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("pomodoro daily counts rows: %w", err)
+	}
+	return days, counts, nil
+}
+
 func (r *trackRepository) GetMonthDailyTotals(ctx context.Context, userID int64, month time.Time, activityIDs []int64) (map[int]time.Duration, error) {
 	if userID <= 0 {
 		return nil, fmt.Errorf("month daily totals: invalid userID")
@@ -682,3 +1128,9 @@ func (r *trackRepository) GetTodayTrackedActivitiesCount(ctx context.Context, us
 	}
 	return count, nil
 }
+
+// isForeignKeyViolation reports PostgreSQL foreign_key_violation (23503).
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
@@ -0,0 +1,162 @@
+// Package reportcard renders period reports as shareable PNG cards.
+package reportcard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sync"
+	"tracker-bot/internal/models"
+	"tracker-bot/pkg/textbuilder"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	cardWidth  = 800
+	cardPad    = 40
+	headerH    = 150
+	rowH       = 56
+	footerH    = 50
+	barH       = 14
+	maxRows    = 8
+	labelWidth = 300
+)
+
+var (
+	colorBackground = color.RGBA{0x1e, 0x22, 0x2b, 0xff}
+	colorText       = color.RGBA{0xf2, 0xf4, 0xf8, 0xff}
+	colorMuted      = color.RGBA{0x9a, 0xa3, 0xb2, 0xff}
+	colorTrack      = color.RGBA{0x2f, 0x35, 0x42, 0xff}
+	colorBars       = []color.RGBA{
+		{0x4f, 0x9d, 0xff, 0xff},
+		{0x35, 0xc7, 0x8a, 0xff},
+		{0xff, 0xb0, 0x3b, 0xff},
+		{0xf2, 0x5f, 0x7a, 0xff},
+		{0xa3, 0x7b, 0xff, 0xff},
+	}
+)
+
+// faces are parsed once; Go fonts cover Latin and Cyrillic.
+var (
+	facesOnce sync.Once
+	facesErr  error
+	faceTitle font.Face
+	faceBody  font.Face
+	faceSmall font.Face
+)
+
+// Render draws report card: title, range, total and top activities as bars.
+func Render(stats models.ReportPeriodStats, title string) ([]byte, error) {
+	facesOnce.Do(loadFaces)
+	if facesErr != nil {
+		return nil, fmt.Errorf("render report card: %w", facesErr)
+	}
+
+	rows := stats.Activities
+	if len(rows) > maxRows {
+		rows = rows[:maxRows]
+	}
+	height := headerH + max(len(rows), 1)*rowH + footerH
+	img := image.NewRGBA(image.Rect(0, 0, cardWidth, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	drawText(img, faceTitle, colorText, cardPad, 60, title)
+	rangeText := fmt.Sprintf("%s — %s", stats.From.Format("Jan 2, 2006"), stats.To.Format("Jan 2, 2006"))
+	drawText(img, faceSmall, colorMuted, cardPad, 92, rangeText)
+	totalText := fmt.Sprintf("Total %s · %d sessions", textbuilder.Duration(stats.TotalTracked), stats.TotalSessions)
+	drawText(img, faceBody, colorText, cardPad, 126, totalText)
+
+	if len(rows) == 0 {
+		drawText(img, faceBody, colorMuted, cardPad, headerH+rowH/2, "No sessions for this period.")
+	}
+
+	var longest int64
+	for _, a := range rows {
+		longest = max(longest, int64(a.Duration))
+	}
+	barX, barW := cardPad+labelWidth, cardWidth-cardPad*2-labelWidth
+	for i, a := range rows {
+		y := headerH + i*rowH
+		drawText(img, faceBody, colorText, cardPad, y+24, truncate(faceBody, a.Name, labelWidth-20))
+		drawText(img, faceSmall, colorMuted, cardPad, y+46, textbuilder.Duration(a.Duration))
+
+		fillRect(img, barX, y+18, barW, barH, colorTrack)
+		w := barW
+		if longest > 0 {
+			w = int(int64(barW) * int64(a.Duration) / longest)
+		}
+		fillRect(img, barX, y+18, max(w, 2), barH, colorBars[i%len(colorBars)])
+	}
+
+	drawText(img, faceSmall, colorMuted, cardPad, height-20, "Tracked with tracker-bot")
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("render report card: encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func loadFaces() {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		facesErr = err
+		return
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		facesErr = err
+		return
+	}
+	newFace := func(f *opentype.Font, size float64) font.Face {
+		if facesErr != nil {
+			return nil
+		}
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			facesErr = err
+		}
+		return face
+	}
+	faceTitle = newFace(bold, 34)
+	faceBody = newFace(regular, 22)
+	faceSmall = newFace(regular, 18)
+}
+
+func drawText(img draw.Image, face font.Face, c color.Color, x, y int, text string) {
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+func fillRect(img draw.Image, x, y, w, h int, c color.Color) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// truncate shortens text with ellipsis to fit width in pixels.
+func truncate(face font.Face, text string, width int) string {
+	limit := fixed.I(width)
+	if font.MeasureString(face, text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if s := string(runes) + "…"; font.MeasureString(face, s) <= limit {
+			return s
+		}
+	}
+	return "…"
+}
@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"tracker-bot/internal/handlers"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/service"
+	"tracker-bot/internal/tracing"
+	"tracker-bot/internal/utils/tgclient"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// broadcastBatchSize is how many recipients are claimed per database round trip.
+	broadcastBatchSize = 20
+	// broadcastInterval spaces announcement messages so interactive traffic keeps headroom
+	// under the global limit (20 msg/s of 30).
+	broadcastInterval = 50 * time.Millisecond
+	// broadcastPollInterval picks up scheduled broadcasts and work left by other instances.
+	broadcastPollInterval = 30 * time.Second
+)
+
+// BroadcastWaker implements service.BroadcastEvents and wakes sender when a broadcast is queued.
+type BroadcastWaker struct {
+	wake chan struct{}
+}
+
+// NewBroadcastWaker creates waker.
+func NewBroadcastWaker() *BroadcastWaker {
+	return &BroadcastWaker{wake: make(chan struct{}, 1)}
+}
+
+// BroadcastScheduled signals sender; broadcasts scheduled for later are found by polling.
+func (w *BroadcastWaker) BroadcastScheduled(_ int64, at time.Time) {
+	if at.After(time.Now()) {
+		return
+	}
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Wake is signalled when a broadcast should start right away.
+func (w *BroadcastWaker) Wake() <-chan struct{} {
+	return w.wake
+}
+
+// BroadcastSender delivers queued broadcasts from database.
+// Recipient status is stored per message, so delivery resumes after restart.
+type BroadcastSender struct {
+	ctx      context.Context
+	svc      service.BroadcastService
+	track    *handlers.Module
+	waker    *BroadcastWaker
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewBroadcastSender creates sender; waker must be the one passed to BroadcastService as events.
+func NewBroadcastSender(ctx context.Context, svc service.BroadcastService, track *handlers.Module, waker *BroadcastWaker) *BroadcastSender {
+	return &BroadcastSender{
+		ctx:   ctx,
+		svc:   svc,
+		track: track,
+		waker: waker,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// Run starts background loop; unfinished broadcasts are resumed right after start.
+func (s *BroadcastSender) Run() {
+	ticker := time.NewTicker(broadcastPollInterval)
+	go func() {
+		defer close(s.done)
+		defer ticker.Stop()
+		s.tick()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-s.stop:
+				return
+			case <-s.waker.Wake():
+				s.tick()
+			case <-ticker.C:
+				s.tick()
+			}
+		}
+	}()
+}
+
+// Stop asks loop to exit and waits until the message being sent is recorded.
+func (s *BroadcastSender) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("broadcast sender stop: %w", ctx.Err())
+	}
+}
+
+// stopping reports whether Stop was called.
+func (s *BroadcastSender) stopping() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return s.ctx.Err() != nil
+	}
+}
+
+// tick starts due broadcasts and sends their pending recipients.
+func (s *BroadcastSender) tick() {
+	due, err := s.svc.ListDue(s.ctx, time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("broadcast sender: list due failed")
+		return
+	}
+	for _, b := range due {
+		if s.stopping() {
+			return
+		}
+		s.deliver(b)
+	}
+}
+
+// deliver sends one broadcast in paced batches until no recipients are left.
+func (s *BroadcastSender) deliver(b models.Broadcast) {
+	ctx, span := tracing.Start(s.ctx, "scheduler.broadcast")
+	defer span.End()
+	logger := log.With().Int64("broadcast_id", b.ID).Logger()
+
+	if b.Status == models.BroadcastStatusScheduled {
+		if err := s.svc.Start(ctx, b.ID); err != nil {
+			logger.Error().Err(err).Msg("broadcast sender: start failed")
+			return
+		}
+		logger.Info().Msg("broadcast started")
+	}
+
+	for !s.stopping() {
+		batch, err := s.svc.ClaimRecipients(ctx, b.ID, broadcastBatchSize)
+		if err != nil {
+			logger.Error().Err(err).Msg("broadcast sender: claim recipients failed")
+			return
+		}
+		for _, r := range batch {
+			if !s.send(ctx, b, r) {
+				// Sender is congested; leave the rest for next poll.
+				return
+			}
+			time.Sleep(broadcastInterval)
+		}
+		if len(batch) < broadcastBatchSize {
+			break
+		}
+	}
+
+	finished, err := s.svc.FinishIfDone(ctx, b.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("broadcast sender: finish failed")
+		return
+	}
+	if !finished {
+		return
+	}
+	st, err := s.svc.Stats(ctx, b.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("broadcast sender: stats failed")
+		return
+	}
+	logger.Info().Int("sent", st.Sent).Int("failed", st.Failed).Int("blocked", st.Blocked).Msg("broadcast finished")
+	s.track.NotifyBroadcastFinished(ctx, b, st)
+}
+
+// send delivers to one recipient and records outcome; false when message was dropped.
+func (s *BroadcastSender) send(ctx context.Context, b models.Broadcast, r models.BroadcastRecipient) bool {
+	sendErr := s.track.SendBroadcastMessage(ctx, b, r.ChatID)
+
+	status := models.RecipientStatusSent
+	switch {
+	case sendErr == nil:
+	case errors.Is(sendErr, tgclient.ErrDropped):
+		status = models.RecipientStatusPending
+	case tgclient.IsUnreachable(sendErr):
+		status = models.RecipientStatusBlocked
+	default:
+		status = models.RecipientStatusFailed
+		log.Warn().Err(sendErr).Int64("broadcast_id", b.ID).Int64("chat_id", r.ChatID).Msg("broadcast sender: send failed")
+	}
+
+	if err := s.svc.MarkRecipient(ctx, r, status, sendErr); err != nil {
+		log.Error().Err(err).Int64("broadcast_id", b.ID).Int64("chat_id", r.ChatID).Msg("broadcast sender: mark recipient failed")
+	}
+	return status != models.RecipientStatusPending
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tracker-bot/internal/service"
+)
+
+// PromptDeliveryCleanupJob removes old prompt delivery records.
+type PromptDeliveryCleanupJob struct {
+	timersvc service.TimerService
+}
+
+// NewPromptDeliveryCleanupJob creates cleanup job instance.
+func NewPromptDeliveryCleanupJob(timersvc service.TimerService) *PromptDeliveryCleanupJob {
+	return &PromptDeliveryCleanupJob{timersvc: timersvc}
+}
+
+// Name returns job name for logs.
+func (j *PromptDeliveryCleanupJob) Name() string {
+	return "prompt_delivery_cleanup"
+}
+
+// Run prunes delivery records past retention.
+func (j *PromptDeliveryCleanupJob) Run(ctx context.Context, now time.Time) error {
+	if err := j.timersvc.PruneDeliveries(ctx, now); err != nil {
+		return fmt.Errorf("prune prompt deliveries: %w", err)
+	}
+	return nil
+}
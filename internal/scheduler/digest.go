@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tracker-bot/internal/handlers"
+	"tracker-bot/internal/service"
+	"tracker-bot/internal/utils/tgclient"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DigestJob delivers due daily/weekly/monthly digests.
+type DigestJob struct {
+	digestsvc service.DigestService
+	track     *handlers.Module
+}
+
+// NewDigestJob creates digest job instance.
+func NewDigestJob(digestsvc service.DigestService, track *handlers.Module) *DigestJob {
+	return &DigestJob{
+		digestsvc: digestsvc,
+		track:     track,
+	}
+}
+
+// Name returns job name for logs.
+func (j *DigestJob) Name() string {
+	return "digest"
+}
+
+// Run claims each due digest before sending, so every period is delivered once
+// even across restarts; failed sends release the claim to retry on next run.
+func (j *DigestJob) Run(ctx context.Context, now time.Time) error {
+	due, err := j.digestsvc.ListDue(ctx, now)
+	if err != nil {
+		return fmt.Errorf("list due digests: %w", err)
+	}
+
+	for _, item := range due {
+		claimed, err := j.digestsvc.Claim(ctx, item)
+		if err != nil {
+			log.Error().Err(err).Int64("user_id", item.UserID).Str("kind", item.Kind).Msg("digest job: claim failed")
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := j.track.SendDigest(ctx, item); err != nil {
+			log.Error().Err(err).Int64("user_id", item.UserID).Str("kind", item.Kind).Msg("digest job: send failed")
+			if err := j.digestsvc.Release(ctx, item); err != nil {
+				log.Error().Err(err).Int64("user_id", item.UserID).Str("kind", item.Kind).Msg("digest job: release failed")
+			}
+		}
+	}
+	return nil
+}
+
+// TeamDigestJob posts weekly leaderboards to group teams.
+type TeamDigestJob struct {
+	teamsvc service.TeamService
+	track   *handlers.Module
+}
+
+// NewTeamDigestJob creates team digest job instance.
+func NewTeamDigestJob(teamsvc service.TeamService, track *handlers.Module) *TeamDigestJob {
+	return &TeamDigestJob{
+		teamsvc: teamsvc,
+		track:   track,
+	}
+}
+
+// Name returns job name for logs.
+func (j *TeamDigestJob) Name() string {
+	return "team digest"
+}
+
+// Run claims and sends due team digests like DigestJob; groups the bot can no longer
+// post to are deactivated instead of retried.
+func (j *TeamDigestJob) Run(ctx context.Context, now time.Time) error {
+	due, err := j.teamsvc.ListDueDigests(ctx, now)
+	if err != nil {
+		return fmt.Errorf("list due team digests: %w", err)
+	}
+
+	for _, item := range due {
+		claimed, err := j.teamsvc.ClaimDigest(ctx, item)
+		if err != nil {
+			log.Error().Err(err).Int64("team_id", item.Team.ID).Msg("team digest job: claim failed")
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		err = j.track.SendTeamDigest(ctx, item)
+		switch {
+		case err == nil:
+		case tgclient.IsUnreachable(err):
+			log.Warn().Err(err).Int64("team_id", item.Team.ID).Msg("team digest job: group unreachable")
+			if err := j.teamsvc.Deactivate(ctx, item.Team.ChatID); err != nil {
+				log.Error().Err(err).Int64("team_id", item.Team.ID).Msg("team digest job: deactivate failed")
+			}
+		default:
+			log.Error().Err(err).Int64("team_id", item.Team.ID).Msg("team digest job: send failed")
+			if err := j.teamsvc.ReleaseDigest(ctx, item); err != nil {
+				log.Error().Err(err).Int64("team_id", item.Team.ID).Msg("team digest job: release failed")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tracker-bot/internal/handlers"
+	"tracker-bot/internal/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PomodoroTick is how often running pomodoros are checked; countdowns change once a minute,
+// so it only bounds how late a phase switch may be noticed.
+const PomodoroTick = 15 * time.Second
+
+// PomodoroJob switches due pomodoro phases and keeps countdown messages current.
+type PomodoroJob struct {
+	pomodorosvc service.PomodoroService
+	track       *handlers.Module
+}
+
+// NewPomodoroJob creates pomodoro job instance.
+func NewPomodoroJob(pomodorosvc service.PomodoroService, track *handlers.Module) *PomodoroJob {
+	return &PomodoroJob{
+		pomodorosvc: pomodorosvc,
+		track:       track,
+	}
+}
+
+// Name returns job name for logs.
+func (j *PomodoroJob) Name() string {
+	return "pomodoro"
+}
+
+// Run advances every due pomodoro once; the conditional phase update makes
+// sure only one instance records the focus block and notifies the user.
+func (j *PomodoroJob) Run(ctx context.Context, now time.Time) error {
+	running, err := j.pomodorosvc.ListRunning(ctx)
+	if err != nil {
+		return fmt.Errorf("list running pomodoros: %w", err)
+	}
+
+	for _, p := range running {
+		if now.Before(p.PhaseEndsAt) {
+			if err := j.track.UpdatePomodoroCountdown(ctx, p, now); err != nil {
+				log.Warn().Err(err).Int64("user_id", p.UserID).Msg("pomodoro job: update countdown failed")
+			}
+			continue
+		}
+
+		next, ok, err := j.pomodorosvc.Advance(ctx, p, now)
+		if err != nil {
+			log.Error().Err(err).Int64("user_id", p.UserID).Str("phase", p.Phase).Msg("pomodoro job: advance failed")
+		}
+		if !ok {
+			continue
+		}
+		if err := j.track.SendPomodoroPhase(ctx, p, next); err != nil {
+			log.Error().Err(err).Int64("user_id", p.UserID).Str("phase", next.Phase).Msg("pomodoro job: send phase failed")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+	"tracker-bot/internal/models"
+)
+
+// TimerQueue is an in-memory min-heap of next prompt times keyed by user.
+// It implements service.TimerEvents, so timer changes update it right away.
+// The database stays the source of truth: the queue only decides when to claim.
+type TimerQueue struct {
+	mu    sync.Mutex
+	items timerHeap
+	index map[int64]*timerItem
+	wake  chan struct{}
+}
+
+type timerItem struct {
+	userID int64
+	dueAt  time.Time
+	pos    int
+}
+
+// NewTimerQueue creates empty queue.
+func NewTimerQueue() *TimerQueue {
+	return &TimerQueue{
+		index: make(map[int64]*timerItem),
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// TimerScheduled adds user or moves its next due time.
+func (q *TimerQueue) TimerScheduled(userID int64, nextPingAt time.Time) {
+	q.mu.Lock()
+	q.set(userID, nextPingAt.UTC())
+	q.mu.Unlock()
+	q.signal()
+}
+
+// TimerStopped removes user from queue.
+func (q *TimerQueue) TimerStopped(userID int64) {
+	q.mu.Lock()
+	if item, ok := q.index[userID]; ok {
+		heap.Remove(&q.items, item.pos)
+		delete(q.index, userID)
+	}
+	q.mu.Unlock()
+	q.signal()
+}
+
+// Reset replaces queue content with schedule loaded from database.
+func (q *TimerQueue) Reset(schedule []models.TimerSchedule) {
+	q.mu.Lock()
+	q.items = make(timerHeap, 0, len(schedule))
+	q.index = make(map[int64]*timerItem, len(schedule))
+	for _, s := range schedule {
+		item := &timerItem{userID: s.DBUserID, dueAt: s.NextPingAt.UTC(), pos: len(q.items)}
+		q.items = append(q.items, item)
+		q.index[s.DBUserID] = item
+	}
+	heap.Init(&q.items)
+	q.mu.Unlock()
+	q.signal()
+}
+
+// Next returns earliest due time; false when queue is empty.
+func (q *TimerQueue) Next() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		return time.Time{}, false
+	}
+	return q.items[0].dueAt, true
+}
+
+// PopDue removes all entries due at now and returns them.
+func (q *TimerQueue) PopDue(now time.Time) []models.TimerSchedule {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var out []models.TimerSchedule
+	for len(q.items) > 0 && !q.items[0].dueAt.After(now) {
+		item := heap.Pop(&q.items).(*timerItem)
+		delete(q.index, item.userID)
+		out = append(out, models.TimerSchedule{DBUserID: item.userID, NextPingAt: item.dueAt})
+	}
+	return out
+}
+
+// Requeue puts popped entries back due at dueAt, skipping users rescheduled meanwhile.
+func (q *TimerQueue) Requeue(entries []models.TimerSchedule, dueAt time.Time) {
+	q.mu.Lock()
+	for _, e := range entries {
+		if _, ok := q.index[e.DBUserID]; !ok {
+			q.set(e.DBUserID, dueAt.UTC())
+		}
+	}
+	q.mu.Unlock()
+	q.signal()
+}
+
+// Len returns number of queued timers.
+func (q *TimerQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Wake is signalled whenever queue changes and the wait time must be recomputed.
+func (q *TimerQueue) Wake() <-chan struct{} {
+	return q.wake
+}
+
+func (q *TimerQueue) set(userID int64, dueAt time.Time) {
+	if item, ok := q.index[userID]; ok {
+		item.dueAt = dueAt
+		heap.Fix(&q.items, item.pos)
+		return
+	}
+	item := &timerItem{userID: userID, dueAt: dueAt}
+	heap.Push(&q.items, item)
+	q.index[userID] = item
+}
+
+func (q *TimerQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// timerHeap implements heap.Interface ordered by due time.
+type timerHeap []*timerItem
+
+func (h timerHeap) Len() int           { return len(h) }
+func (h timerHeap) Less(i, j int) bool { return h[i].dueAt.Before(h[j].dueAt) }
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+
+func (h *timerHeap) Push(x any) {
+	item := x.(*timerItem)
+	item.pos = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *timerHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"tracker-bot/internal/tracing"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Job is one periodic background task run by JobRunner.
+type Job interface {
+	Name() string
+	Run(ctx context.Context, now time.Time) error
+}
+
+// JobRunner runs registered jobs sequentially on a fixed interval.
+type JobRunner struct {
+	ctx      context.Context
+	interval time.Duration
+	jobs     []Job
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewJobRunner creates runner for jobs with given check interval.
+func NewJobRunner(ctx context.Context, interval time.Duration, jobs ...Job) *JobRunner {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &JobRunner{
+		ctx:      ctx,
+		interval: interval,
+		jobs:     jobs,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Run starts background ticker loop; jobs also run once right after start.
+func (r *JobRunner) Run() {
+	ticker := time.NewTicker(r.interval)
+	go func() {
+		defer close(r.done)
+		defer ticker.Stop()
+		r.tick(time.Now().UTC())
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-r.stop:
+				return
+			case now := <-ticker.C:
+				r.tick(now.UTC())
+			}
+		}
+	}()
+}
+
+// Stop asks loop to exit and waits for the running job to finish.
+func (r *JobRunner) Stop(ctx context.Context) error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("job runner stop: %w", ctx.Err())
+	}
+}
+
+// stopping reports whether Stop was called.
+func (r *JobRunner) stopping() bool {
+	select {
+	case <-r.stop:
+		return true
+	default:
+		return r.ctx.Err() != nil
+	}
+}
+
+// tick runs every job once at provided UTC time.
+func (r *JobRunner) tick(now time.Time) {
+	for _, job := range r.jobs {
+		if r.stopping() {
+			return
+		}
+		ctx, span := tracing.Start(r.ctx, "job."+job.Name())
+		err := job.Run(ctx, now)
+		tracing.End(span, err)
+		if err != nil {
+			log.Error().Err(err).Str("job", job.Name()).Msg("job runner: job failed")
+		}
+	}
+}
@@ -2,60 +2,159 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 	"tracker-bot/internal/handlers"
+	"tracker-bot/internal/metrics"
 	"tracker-bot/internal/service"
+	"tracker-bot/internal/tracing"
 
 	"github.com/rs/zerolog/log"
 )
 
-// TimerScheduler periodically checks due users and sends tracking prompts.
+const (
+	// timerBatchSize is how many due users are claimed per database round trip.
+	timerBatchSize = 500
+	// timerResyncInterval reloads the queue from database to pick up
+	// changes made by other instances.
+	timerResyncInterval = 5 * time.Minute
+	// timerIdleWait bounds sleep when queue is empty.
+	timerIdleWait = time.Minute
+	// timerRetryMin and timerRetryMax bound backoff of claims that failed.
+	timerRetryMin = 5 * time.Second
+	timerRetryMax = time.Minute
+)
+
+// TimerScheduler sends tracking prompts when the earliest queued timer is due.
 type TimerScheduler struct {
 	ctx      context.Context
 	timersvc service.TimerService
 	track    *handlers.Module
+	queue    *TimerQueue
+	retry    time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
-// NewTimerScheduler creates scheduler instance.
-func NewTimerScheduler(ctx context.Context, timersvc service.TimerService, track *handlers.Module) *TimerScheduler {
+// NewTimerScheduler creates scheduler instance; queue must be the one passed to TimerService as events.
+func NewTimerScheduler(ctx context.Context, timersvc service.TimerService, track *handlers.Module, queue *TimerQueue) *TimerScheduler {
 	return &TimerScheduler{
 		ctx:      ctx,
 		timersvc: timersvc,
 		track:    track,
+		queue:    queue,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
 	}
 }
 
-// Run starts background ticker loop.
+// Run loads schedule from database and starts background loop.
 func (s *TimerScheduler) Run() {
-	ticker := time.NewTicker(10 * time.Second)
 	go func() {
-		defer ticker.Stop()
+		defer close(s.done)
+		s.resync()
+		resync := time.NewTicker(timerResyncInterval)
+		defer resync.Stop()
+		timer := time.NewTimer(timerIdleWait)
+		defer timer.Stop()
+
 		for {
+			wait := timerIdleWait
+			if next, ok := s.queue.Next(); ok {
+				wait = max(time.Until(next), 0)
+			}
+			timer.Reset(wait)
+
 			select {
 			case <-s.ctx.Done():
 				return
-			case now := <-ticker.C:
+			case <-s.stop:
+				return
+			case <-s.queue.Wake():
+			case <-resync.C:
+				s.resync()
+			case now := <-timer.C:
 				s.tick(now.UTC())
 			}
 		}
 	}()
 }
 
-// tick processes one scheduler cycle at provided UTC time.
-func (s *TimerScheduler) tick(now time.Time) {
-	dueUsers, err := s.timersvc.ListDueUsers(s.ctx, now, 100)
+// Stop asks loop to exit and waits until the running tick finishes its current batch.
+func (s *TimerScheduler) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timer scheduler stop: %w", ctx.Err())
+	}
+}
+
+// stopping reports whether Stop was called.
+func (s *TimerScheduler) stopping() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return s.ctx.Err() != nil
+	}
+}
+
+// resync replaces queue content with enabled timers from database.
+func (s *TimerScheduler) resync() {
+	schedule, err := s.timersvc.ListSchedule(s.ctx)
 	if err != nil {
-		log.Error().Err(err).Msg("timer scheduler: list due users failed")
+		log.Error().Err(err).Msg("timer scheduler: load schedule failed")
 		return
 	}
+	s.queue.Reset(schedule)
+}
+
+// tick claims and notifies all users due at now in batches.
+// Claiming makes ticks safe to run on several instances against one database.
+// When claim fails, popped users are queued again with growing backoff.
+func (s *TimerScheduler) tick(now time.Time) {
+	popped := s.queue.PopDue(now)
+	if len(popped) == 0 {
+		return
+	}
+	ctx, span := tracing.Start(s.ctx, "scheduler.timer_tick")
+	defer span.End()
+
+	for !s.stopping() {
+		dueUsers, err := s.timersvc.ClaimDueUsers(ctx, now, timerBatchSize)
+		if err != nil {
+			s.retry = min(max(s.retry*2, timerRetryMin), timerRetryMax)
+			log.Error().Err(err).Dur("retry_in", s.retry).Msg("timer scheduler: claim due users failed")
+			s.queue.Requeue(popped, time.Now().Add(s.retry))
+			return
+		}
+		s.retry = 0
 
-	for _, item := range dueUsers {
-		if err := s.track.SendPromptMessage(s.ctx, item.TgUserID, item.DBUserID, item.IntervalMin); err != nil {
-			log.Error().Err(err).Int64("user_id", item.DBUserID).Msg("timer scheduler: send prompt failed")
-			continue
+		for _, item := range dueUsers {
+			quiet, err := s.timersvc.PostponeQuiet(ctx, item, now)
+			if err != nil {
+				log.Error().Err(err).Int64("user_id", item.DBUserID).Msg("timer scheduler: postpone quiet prompt failed")
+			}
+			if quiet {
+				continue
+			}
+			if err := s.track.SendPromptMessage(ctx, item.TgUserID, item.DBUserID, item.IntervalMin); err != nil {
+				log.Error().Err(err).Int64("user_id", item.DBUserID).Msg("timer scheduler: send prompt failed")
+				continue
+			}
+			sentAt := time.Now()
+			metrics.PromptSent(item.ScheduledAt, sentAt)
+			if err := s.timersvc.MarkPromptSent(ctx, item, sentAt); err != nil {
+				log.Error().Err(err).Int64("user_id", item.DBUserID).Msg("timer scheduler: mark prompt sent failed")
+			}
 		}
-		if err := s.timersvc.MarkPromptSent(s.ctx, item.DBUserID, item.IntervalMin, now); err != nil {
-			log.Error().Err(err).Int64("user_id", item.DBUserID).Msg("timer scheduler: mark prompt sent failed")
+
+		if len(dueUsers) < timerBatchSize {
+			return
 		}
 	}
 }
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+// AdminService contains operator use-cases; callers check admin rights.
+type AdminService interface {
+	Stats(ctx context.Context, now time.Time) (models.AdminStats, error)
+	GetUserInfo(ctx context.Context, tgUserID int64) (models.AdminUserInfo, error)
+	TimerBacklog(ctx context.Context, now time.Time, limit int) (models.TimerBacklog, error)
+}
+
+type adminService struct {
+	repo repo.AdminRepository
+}
+
+// NewAdminService creates admin service.
+func NewAdminService(repo repo.AdminRepository) AdminService {
+	return &adminService{repo: repo}
+}
+
+// Stats returns totals; "today" is the current UTC day.
+func (s *adminService) Stats(ctx context.Context, now time.Time) (models.AdminStats, error) {
+	return s.repo.Stats(ctx, now.UTC().Truncate(24*time.Hour))
+}
+
+// GetUserInfo returns operator view of user by Telegram id.
+func (s *adminService) GetUserInfo(ctx context.Context, tgUserID int64) (models.AdminUserInfo, error) {
+	return s.repo.GetUserInfo(ctx, tgUserID)
+}
+
+// TimerBacklog returns overdue timer counters and up to limit nearest timers.
+func (s *adminService) TimerBacklog(ctx context.Context, now time.Time, limit int) (models.TimerBacklog, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	return s.repo.TimerBacklog(ctx, now.UTC(), limit)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+const (
+	// broadcastStaleAfter is when a recipient claimed by a crashed sender is retried.
+	broadcastStaleAfter = 10 * time.Minute
+	// maxBroadcastButtons keeps inline keyboard readable.
+	maxBroadcastButtons = 6
+)
+
+// broadcastButtonLine matches trailing "Button text | https://link" lines of draft.
+var broadcastButtonLine = regexp.MustCompile(`^\s*(.+?)\s*\|\s*(https?://\S+)\s*$`)
+
+// BroadcastEvents is notified when a broadcast becomes due so sender can wake up.
+type BroadcastEvents interface {
+	BroadcastScheduled(id int64, at time.Time)
+}
+
+// BroadcastService contains announcement composing and delivery use-cases.
+type BroadcastService interface {
+	CreateDraft(ctx context.Context, createdBy int64, raw, photoFileID string) (models.Broadcast, error)
+	Get(ctx context.Context, id int64) (models.Broadcast, error)
+	ListRecent(ctx context.Context, limit int) ([]models.Broadcast, error)
+	SetSegment(ctx context.Context, id int64, seg models.BroadcastSegment) error
+	CountRecipients(ctx context.Context, seg models.BroadcastSegment) (int, error)
+	Schedule(ctx context.Context, id int64, at time.Time) error
+	Cancel(ctx context.Context, id int64) error
+	ListDue(ctx context.Context, now time.Time) ([]models.Broadcast, error)
+	Start(ctx context.Context, id int64) error
+	ClaimRecipients(ctx context.Context, id int64, limit int) ([]models.BroadcastRecipient, error)
+	MarkRecipient(ctx context.Context, r models.BroadcastRecipient, status string, sendErr error) error
+	FinishIfDone(ctx context.Context, id int64) (bool, error)
+	Stats(ctx context.Context, id int64) (models.BroadcastStats, error)
+}
+
+type broadcastService struct {
+	repo   repo.BroadcastRepository
+	events BroadcastEvents
+}
+
+// NewBroadcastService creates broadcast service; events may be nil.
+func NewBroadcastService(repo repo.BroadcastRepository, events BroadcastEvents) BroadcastService {
+	return &broadcastService{repo: repo, events: events}
+}
+
+// CreateDraft stores new draft; trailing "Text | https://link" lines become URL buttons.
+func (s *broadcastService) CreateDraft(ctx context.Context, createdBy int64, raw, photoFileID string) (models.Broadcast, error) {
+	text, buttons := parseBroadcastButtons(raw)
+	if text == "" && photoFileID == "" {
+		return models.Broadcast{}, models.ErrInvalidBroadcast
+	}
+	if len(buttons) > maxBroadcastButtons {
+		return models.Broadcast{}, fmt.Errorf("%w: at most %d buttons", models.ErrInvalidBroadcast, maxBroadcastButtons)
+	}
+
+	b := models.Broadcast{
+		CreatedBy:   createdBy,
+		Text:        text,
+		PhotoFileID: photoFileID,
+		Buttons:     buttons,
+		Status:      models.BroadcastStatusDraft,
+	}
+	id, err := s.repo.Create(ctx, b)
+	if err != nil {
+		return models.Broadcast{}, err
+	}
+	b.ID = id
+	return b, nil
+}
+
+// Get returns broadcast by id.
+func (s *broadcastService) Get(ctx context.Context, id int64) (models.Broadcast, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// ListRecent returns latest broadcasts first.
+func (s *broadcastService) ListRecent(ctx context.Context, limit int) ([]models.Broadcast, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	return s.repo.ListRecent(ctx, limit)
+}
+
+// SetSegment updates targeting of draft.
+func (s *broadcastService) SetSegment(ctx context.Context, id int64, seg models.BroadcastSegment) error {
+	if seg.ActiveDays < 0 {
+		return models.ErrInvalidBroadcast
+	}
+	return s.repo.UpdateSegment(ctx, id, seg)
+}
+
+// CountRecipients returns how many users segment currently matches.
+func (s *broadcastService) CountRecipients(ctx context.Context, seg models.BroadcastSegment) (int, error) {
+	return s.repo.CountSegment(ctx, seg)
+}
+
+// Schedule queues draft for delivery at given time; past or zero time means now.
+func (s *broadcastService) Schedule(ctx context.Context, id int64, at time.Time) error {
+	now := time.Now().UTC()
+	if at.IsZero() || at.Before(now) {
+		at = now
+	}
+	if err := s.repo.Schedule(ctx, id, at.UTC()); err != nil {
+		return err
+	}
+	if s.events != nil {
+		s.events.BroadcastScheduled(id, at)
+	}
+	return nil
+}
+
+// Cancel stops draft, scheduled or running broadcast; already sent messages stay.
+func (s *broadcastService) Cancel(ctx context.Context, id int64) error {
+	return s.repo.Cancel(ctx, id)
+}
+
+// ListDue returns broadcasts that should be sending now.
+func (s *broadcastService) ListDue(ctx context.Context, now time.Time) ([]models.Broadcast, error) {
+	return s.repo.ListDue(ctx, now.UTC())
+}
+
+// Start enqueues recipients of scheduled broadcast; no-op if already started.
+func (s *broadcastService) Start(ctx context.Context, id int64) error {
+	return s.repo.StartSending(ctx, id)
+}
+
+// ClaimRecipients reserves next batch of deliveries for this instance.
+func (s *broadcastService) ClaimRecipients(ctx context.Context, id int64, limit int) ([]models.BroadcastRecipient, error) {
+	return s.repo.ClaimRecipients(ctx, id, limit, time.Now().UTC().Add(-broadcastStaleAfter))
+}
+
+// MarkRecipient stores delivery outcome.
+func (s *broadcastService) MarkRecipient(ctx context.Context, r models.BroadcastRecipient, status string, sendErr error) error {
+	var errText string
+	if sendErr != nil {
+		errText = sendErr.Error()
+	}
+	return s.repo.MarkRecipient(ctx, r, status, errText)
+}
+
+// FinishIfDone completes broadcast when no deliveries are left; true if it was completed now.
+func (s *broadcastService) FinishIfDone(ctx context.Context, id int64) (bool, error) {
+	return s.repo.FinishIfDone(ctx, id)
+}
+
+// Stats returns recipient counters by status.
+func (s *broadcastService) Stats(ctx context.Context, id int64) (models.BroadcastStats, error) {
+	return s.repo.Stats(ctx, id)
+}
+
+// parseBroadcastButtons splits trailing button lines from message text.
+func parseBroadcastButtons(raw string) (string, []models.BroadcastButton) {
+	lines := strings.Split(strings.TrimSpace(raw), "\n")
+	end := len(lines)
+	for end > 0 {
+		m := broadcastButtonLine.FindStringSubmatch(lines[end-1])
+		if m == nil {
+			break
+		}
+		if _, err := url.ParseRequestURI(m[2]); err != nil {
+			break
+		}
+		end--
+	}
+
+	buttons := make([]models.BroadcastButton, 0, len(lines)-end)
+	for _, line := range lines[end:] {
+		m := broadcastButtonLine.FindStringSubmatch(line)
+		buttons = append(buttons, models.BroadcastButton{Text: m[1], URL: m[2]})
+	}
+	return strings.TrimSpace(strings.Join(lines[:end], "\n")), buttons
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+// defaultDigestSendAtMin is 21:00 local time.
+const defaultDigestSendAtMin = 21 * 60
+
+// DigestService contains scheduled digest use-cases.
+type DigestService interface {
+	GetSettings(ctx context.Context, userID int64) (models.DigestSettings, error)
+	Toggle(ctx context.Context, userID int64, kind string) (models.DigestSettings, error)
+	SetSendAt(ctx context.Context, userID int64, minutes int) (models.DigestSettings, error)
+	ListDue(ctx context.Context, now time.Time) ([]models.DigestDue, error)
+	Claim(ctx context.Context, due models.DigestDue) (bool, error)
+	Release(ctx context.Context, due models.DigestDue) error
+}
+
+type digestService struct {
+	repo repo.DigestRepository
+}
+
+// NewDigestService creates digest service.
+func NewDigestService(repo repo.DigestRepository) DigestService {
+	return &digestService{repo: repo}
+}
+
+// GetSettings returns saved settings or defaults when user has none.
+func (s *digestService) GetSettings(ctx context.Context, userID int64) (models.DigestSettings, error) {
+	settings, ok, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return models.DigestSettings{}, err
+	}
+	if !ok {
+		return models.DigestSettings{UserID: userID, SendAtMin: defaultDigestSendAtMin}, nil
+	}
+	return settings, nil
+}
+
+// Toggle switches one digest kind on or off.
+func (s *digestService) Toggle(ctx context.Context, userID int64, kind string) (models.DigestSettings, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return models.DigestSettings{}, err
+	}
+	switch kind {
+	case models.DigestKindDaily:
+		settings.Daily = !settings.Daily
+	case models.DigestKindWeekly:
+		settings.Weekly = !settings.Weekly
+	case models.DigestKindMonthly:
+		settings.Monthly = !settings.Monthly
+	default:
+		return models.DigestSettings{}, fmt.Errorf("toggle digest: unknown kind %q", kind)
+	}
+	if err := s.repo.Upsert(ctx, settings); err != nil {
+		return models.DigestSettings{}, err
+	}
+	return settings, nil
+}
+
+// SetSendAt changes local delivery time (minutes after midnight).
+func (s *digestService) SetSendAt(ctx context.Context, userID int64, minutes int) (models.DigestSettings, error) {
+	if minutes < 0 || minutes >= 24*60 {
+		return models.DigestSettings{}, fmt.Errorf("set digest time: invalid minutes")
+	}
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return models.DigestSettings{}, err
+	}
+	settings.SendAtMin = minutes
+	if err := s.repo.Upsert(ctx, settings); err != nil {
+		return models.DigestSettings{}, err
+	}
+	return settings, nil
+}
+
+// ListDue returns digests whose local delivery time has passed in the current period.
+// Already delivered periods are filtered out later by Claim.
+func (s *digestService) ListDue(ctx context.Context, now time.Time) ([]models.DigestDue, error) {
+	settings, err := s.repo.ListEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.DigestDue, 0, len(settings))
+	for _, item := range settings {
+		out = append(out, dueDigests(item, now)...)
+	}
+	return out, nil
+}
+
+// Claim marks digest period as delivered; false means another run already did it.
+func (s *digestService) Claim(ctx context.Context, due models.DigestDue) (bool, error) {
+	return s.repo.Claim(ctx, due.UserID, due.Kind, due.PeriodStart)
+}
+
+// Release undoes Claim after a failed delivery so it is retried.
+func (s *digestService) Release(ctx context.Context, due models.DigestDue) error {
+	return s.repo.Release(ctx, due.UserID, due.Kind, due.PeriodStart)
+}
+
+// dueDigests computes digests due at now in user's local time zone.
+// Daily covers the current day and is due until midnight;
+// weekly covers last week and is due from Monday; monthly covers last month and is due from the 1st.
+func dueDigests(s models.DigestSettings, now time.Time) []models.DigestDue {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	sendAt := time.Duration(s.SendAtMin) * time.Minute
+
+	out := make([]models.DigestDue, 0, 3)
+	add := func(kind string, from, to time.Time) {
+		out = append(out, models.DigestDue{
+			UserID:      s.UserID,
+			TgUserID:    s.TgUserID,
+			Kind:        kind,
+			PeriodStart: time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC),
+			From:        from,
+			To:          to,
+		})
+	}
+
+	if s.Daily && !local.Before(midnight.Add(sendAt)) {
+		add(models.DigestKindDaily, midnight, midnight.AddDate(0, 0, 1))
+	}
+	if s.Weekly {
+		monday := midnight.AddDate(0, 0, -((int(midnight.Weekday()) + 6) % 7))
+		if !local.Before(monday.Add(sendAt)) {
+			add(models.DigestKindWeekly, monday.AddDate(0, 0, -7), monday)
+		}
+	}
+	if s.Monthly {
+		first := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+		if !local.Before(first.Add(sendAt)) {
+			add(models.DigestKindMonthly, first.AddDate(0, -1, 0), first)
+		}
+	}
+	return out
+}
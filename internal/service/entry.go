@@ -6,10 +6,14 @@ import (
 
 	"tracker-bot/internal/models"
 	"tracker-bot/internal/repo"
+	"tracker-bot/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type EntryService interface {
 	EnsureUser(ctx context.Context, user *models.UserInput) (int64, error)
+	SetActive(ctx context.Context, tgUserID int64, active bool) (int64, bool, error)
 }
 
 type entryService struct {
@@ -22,8 +26,11 @@ func NewEntryService(repo repo.EntryRepository) EntryService {
 	}
 }
 
-func (s *entryService) EnsureUser(ctx context.Context, user *models.UserInput) (int64, error) {
-	_, err := s.repo.GetByID(ctx, user.TgUserID)
+func (s *entryService) EnsureUser(ctx context.Context, user *models.UserInput) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "EntryService.EnsureUser", attribute.Int64("telegram.user_id", user.TgUserID))
+	defer func() { tracing.End(span, err) }()
+
+	_, err = s.repo.GetByID(ctx, user.TgUserID)
 	if err == nil {
 		return s.repo.GetDBIDByTgUserID(ctx, user.TgUserID)
 	}
@@ -51,3 +58,8 @@ func (s *entryService) EnsureUser(ctx context.Context, user *models.UserInput) (
 
 	return dbID, nil
 }
+
+// SetActive marks user as reachable or not (e.g. after blocking the bot).
+func (s *entryService) SetActive(ctx context.Context, tgUserID int64, active bool) (int64, bool, error) {
+	return s.repo.SetActive(ctx, tgUserID, active)
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+// goalStreakLookbackDays limits how far back goal-based streaks are evaluated.
+const goalStreakLookbackDays = 366
+
+// GoalService contains goal use-cases.
+type GoalService interface {
+	SetGoal(ctx context.Context, userID, activityID int64, spec string) (models.Goal, error)
+	DeleteGoal(ctx context.Context, userID, goalID int64) error
+	ToggleStreak(ctx context.Context, userID, goalID int64) error
+	ListProgress(ctx context.Context, userID int64) ([]models.GoalProgress, error)
+}
+
+type goalService struct {
+	repo repo.GoalRepository
+}
+
+// NewGoalService creates goal service.
+func NewGoalService(repo repo.GoalRepository) GoalService {
+	return &goalService{repo: repo}
+}
+
+// SetGoal parses spec like "30m/day", "5h/week" or "max 1h/day" and saves it.
+func (s *goalService) SetGoal(ctx context.Context, userID, activityID int64, spec string) (models.Goal, error) {
+	goal, err := ParseGoalSpec(spec)
+	if err != nil {
+		return models.Goal{}, err
+	}
+	goal.ActivityID = activityID
+	if err := s.repo.Upsert(ctx, userID, activityID, goal.Period, goal.Kind, int(goal.Target/time.Minute)); err != nil {
+		return models.Goal{}, err
+	}
+	return goal, nil
+}
+
+// DeleteGoal removes one goal.
+func (s *goalService) DeleteGoal(ctx context.Context, userID, goalID int64) error {
+	return s.repo.Delete(ctx, userID, goalID)
+}
+
+// ToggleStreak switches whether a daily goal drives the streak counter.
+func (s *goalService) ToggleStreak(ctx context.Context, userID, goalID int64) error {
+	goals, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, g := range goals {
+		if g.ID != goalID {
+			continue
+		}
+		if g.Period != models.GoalPeriodDay {
+			return models.ErrInvalidGoal
+		}
+		return s.repo.SetUseForStreak(ctx, userID, goalID, !g.UseForStreak)
+	}
+	return models.ErrGoalNotFound
+}
+
+// ListProgress returns all goals with time tracked in their current period.
+func (s *goalService) ListProgress(ctx context.Context, userID int64) ([]models.GoalProgress, error) {
+	return goalProgress(ctx, s.repo, userID, time.Now().UTC())
+}
+
+// ParseGoalSpec parses "<duration>/<day|week>" with optional "max " prefix.
+func ParseGoalSpec(spec string) (models.Goal, error) {
+	raw := strings.ToLower(strings.TrimSpace(spec))
+	kind := models.GoalKindMin
+	switch {
+	case strings.HasPrefix(raw, "max "):
+		kind = models.GoalKindMax
+		raw = strings.TrimSpace(strings.TrimPrefix(raw, "max "))
+	case strings.HasPrefix(raw, "min "):
+		raw = strings.TrimSpace(strings.TrimPrefix(raw, "min "))
+	}
+
+	parts := strings.Split(raw, "/")
+	if len(parts) != 2 {
+		return models.Goal{}, models.ErrInvalidGoal
+	}
+
+	var period string
+	switch strings.TrimSpace(parts[1]) {
+	case "d", "day":
+		period = models.GoalPeriodDay
+	case "w", "week":
+		period = models.GoalPeriodWeek
+	default:
+		return models.Goal{}, models.ErrInvalidGoal
+	}
+
+	target, err := parseGoalDuration(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return models.Goal{}, err
+	}
+	if period == models.GoalPeriodDay && target > 24*time.Hour {
+		return models.Goal{}, models.ErrInvalidGoal
+	}
+
+	return models.Goal{
+		Period: period,
+		Kind:   kind,
+		Target: target,
+	}, nil
+}
+
+// parseGoalDuration accepts "45", "45m", "2h", "1h30m" and "1.5h".
+func parseGoalDuration(s string) (time.Duration, error) {
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return 0, models.ErrInvalidGoal
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		s = fmt.Sprintf("%dm", n)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < time.Minute || d > 7*24*time.Hour {
+		return 0, models.ErrInvalidGoal
+	}
+	return d.Truncate(time.Minute), nil
+}
+
+// goalProgress loads user goals and sums tracked time in the current day/week.
+func goalProgress(ctx context.Context, goalRepo repo.GoalRepository, userID int64, now time.Time) ([]models.GoalProgress, error) {
+	goals, err := goalRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(goals) == 0 {
+		return nil, nil
+	}
+
+	today := dayStart(now, time.UTC)
+	from := weekStart(now, time.UTC)
+	totals, err := goalRepo.GetDailyTotals(ctx, userID, goalActivityIDs(goals), from, today.AddDate(0, 0, 1), time.UTC)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.GoalProgress, 0, len(goals))
+	for _, g := range goals {
+		periodFrom := today
+		if g.Period == models.GoalPeriodWeek {
+			periodFrom = from
+		}
+		out = append(out, models.GoalProgress{
+			Goal:    g,
+			Tracked: sumDays(totals[g.ActivityID], periodFrom, today.AddDate(0, 0, 1)),
+		})
+	}
+	return out, nil
+}
+
+// goalCompletion counts how many days/weeks of [from, to) in loc met each goal.
+// Days after now are not counted.
+func goalCompletion(ctx context.Context, goalRepo repo.GoalRepository, userID int64, from, to, now time.Time, loc *time.Location) ([]models.GoalCompletion, error) {
+	goals, err := goalRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(goals) == 0 {
+		return nil, nil
+	}
+
+	from = dayStart(from, loc)
+	if limit := dayStart(now, loc).AddDate(0, 0, 1); to.After(limit) {
+		to = limit
+	}
+	if !from.Before(to) {
+		return nil, nil
+	}
+
+	totals, err := goalRepo.GetDailyTotals(ctx, userID, goalActivityIDs(goals), weekStart(from, loc), to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.GoalCompletion, 0, len(goals))
+	for _, g := range goals {
+		c := models.GoalCompletion{Goal: g}
+		step := 1
+		cur := from
+		if g.Period == models.GoalPeriodWeek {
+			step = 7
+			cur = weekStart(from, loc)
+		}
+		for ; cur.Before(to); cur = cur.AddDate(0, 0, step) {
+			p := models.GoalProgress{Goal: g, Tracked: sumDays(totals[g.ActivityID], cur, cur.AddDate(0, 0, step))}
+			c.PeriodsTotal++
+			if p.Met() {
+				c.PeriodsMet++
+			}
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// calcGoalStreakDays counts consecutive days (ending today) that met a daily goal.
+// Today is skipped while a "min" goal is still in progress.
+func calcGoalStreakDays(totals map[string]time.Duration, goal models.Goal, now time.Time) int {
+	if len(totals) == 0 {
+		return 0
+	}
+	earliest := ""
+	for key := range totals {
+		if earliest == "" || key < earliest {
+			earliest = key
+		}
+	}
+
+	cur := dayStart(now, time.UTC)
+	met := func(day time.Time) bool {
+		p := models.GoalProgress{Goal: goal, Tracked: totals[day.Format("2006-01-02")]}
+		return p.Met()
+	}
+	if goal.Kind == models.GoalKindMin && !met(cur) {
+		cur = cur.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for cur.Format("2006-01-02") >= earliest && met(cur) {
+		streak++
+		cur = cur.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+func goalActivityIDs(goals []models.Goal) []int64 {
+	seen := make(map[int64]struct{}, len(goals))
+	ids := make([]int64, 0, len(goals))
+	for _, g := range goals {
+		if _, ok := seen[g.ActivityID]; ok {
+			continue
+		}
+		seen[g.ActivityID] = struct{}{}
+		ids = append(ids, g.ActivityID)
+	}
+	return ids
+}
+
+// sumDays sums daily totals in [from, to).
+func sumDays(days map[string]time.Duration, from, to time.Time) time.Duration {
+	var total time.Duration
+	for cur := from; cur.Before(to); cur = cur.AddDate(0, 0, 1) {
+		total += days[cur.Format("2006-01-02")]
+	}
+	return total
+}
+
+// dayStart returns midnight in loc of the day containing t.
+func dayStart(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// weekStart returns Monday midnight in loc of the week containing t.
+func weekStart(t time.Time, loc *time.Location) time.Time {
+	d := dayStart(t, loc)
+	offset := (int(d.Weekday()) + 6) % 7
+	return d.AddDate(0, 0, -offset)
+}
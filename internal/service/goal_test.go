@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+	"tracker-bot/pkg/timeparse"
+)
+
+type testSession struct {
+	activityID int64
+	start      time.Time
+	dur        time.Duration
+}
+
+// fakeGoalRepo serves goals and buckets sessions by start day in loc, like goal_repo's SQL.
+type fakeGoalRepo struct {
+	repo.GoalRepository
+	goals    []models.Goal
+	sessions []testSession
+}
+
+func (r *fakeGoalRepo) ListByUser(context.Context, int64) ([]models.Goal, error) {
+	return r.goals, nil
+}
+
+func (r *fakeGoalRepo) GetDailyTotals(_ context.Context, _ int64, _ []int64, from, to time.Time, loc *time.Location) (map[int64]map[string]time.Duration, error) {
+	out := make(map[int64]map[string]time.Duration)
+	for _, s := range r.sessions {
+		if s.start.Before(from) || !s.start.Before(to) {
+			continue
+		}
+		if out[s.activityID] == nil {
+			out[s.activityID] = make(map[string]time.Duration)
+		}
+		out[s.activityID][s.start.In(loc).Format("2006-01-02")] += s.dur
+	}
+	return out, nil
+}
+
+func TestGoalCompletionDigestInBerlin(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone is not available: %v", err)
+	}
+	at := func(m time.Month, d, h, min int) time.Time {
+		return time.Date(2026, m, d, h, min, 0, 0, berlin)
+	}
+	daily := models.Goal{ID: 1, ActivityID: 10, Period: models.GoalPeriodDay, Kind: models.GoalKindMin, Target: 30 * time.Minute}
+	weekly := models.Goal{ID: 2, ActivityID: 10, Period: models.GoalPeriodWeek, Kind: models.GoalKindMin, Target: 2 * time.Hour}
+	// 00:30 Berlin is still the previous day in UTC.
+	sessions := []testSession{
+		{activityID: 10, start: at(10, 16, 0, 30), dur: 40 * time.Minute},
+		{activityID: 10, start: at(10, 14, 23, 30), dur: 90 * time.Minute},
+	}
+
+	tests := []struct {
+		name      string
+		goal      models.Goal
+		from, to  time.Time
+		now       time.Time
+		wantMet   int
+		wantTotal int
+	}{
+		{name: "daily digest", goal: daily, from: at(10, 16, 0, 0), to: at(10, 17, 0, 0), now: at(10, 17, 9, 0), wantMet: 1, wantTotal: 1},
+		{name: "daily digest of day without goal", goal: daily, from: at(10, 15, 0, 0), to: at(10, 16, 0, 0), now: at(10, 16, 9, 0), wantMet: 0, wantTotal: 1},
+		{name: "weekly digest", goal: weekly, from: at(10, 12, 0, 0), to: at(10, 19, 0, 0), now: at(10, 19, 9, 0), wantMet: 1, wantTotal: 1},
+		{name: "week of daily goals", goal: daily, from: at(10, 12, 0, 0), to: at(10, 19, 0, 0), now: at(10, 19, 9, 0), wantMet: 2, wantTotal: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeGoalRepo{goals: []models.Goal{tt.goal}, sessions: sessions}
+			got, err := goalCompletion(context.Background(), r, 1, tt.from, tt.to, tt.now, berlin)
+			if err != nil {
+				t.Fatalf("goalCompletion: %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("goalCompletion returned %d goals, want 1", len(got))
+			}
+			if got[0].PeriodsMet != tt.wantMet || got[0].PeriodsTotal != tt.wantTotal {
+				t.Errorf("goalCompletion = %d/%d, want %d/%d", got[0].PeriodsMet, got[0].PeriodsTotal, tt.wantMet, tt.wantTotal)
+			}
+		})
+	}
+}
+
+// TestGoalCompletionAcrossDST takes report bounds the way handlers do: local midnights from
+// timeparse, with the last day closed by the next calendar day rather than +24h.
+func TestGoalCompletionAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone is not available: %v", err)
+	}
+	// Berlin returns to winter time on 2026-10-25, a 25-hour day.
+	now := time.Date(2026, 10, 26, 9, 0, 0, 0, berlin)
+	from, to, err := timeparse.New(now, "en").Range("last week")
+	if err != nil {
+		t.Fatalf("Range: %v", err)
+	}
+	goal := models.Goal{ID: 1, ActivityID: 10, Period: models.GoalPeriodDay, Kind: models.GoalKindMin, Target: 30 * time.Minute}
+	r := &fakeGoalRepo{goals: []models.Goal{goal}, sessions: []testSession{
+		{activityID: 10, start: time.Date(2026, 10, 19, 0, 15, 0, 0, berlin), dur: 40 * time.Minute},
+		{activityID: 10, start: time.Date(2026, 10, 25, 23, 15, 0, 0, berlin), dur: 40 * time.Minute},
+	}}
+
+	got, err := goalCompletion(context.Background(), r, 1, from, to.AddDate(0, 0, 1), now, from.Location())
+	if err != nil {
+		t.Fatalf("goalCompletion: %v", err)
+	}
+	if len(got) != 1 || got[0].PeriodsMet != 2 || got[0].PeriodsTotal != 7 {
+		t.Fatalf("goalCompletion = %+v, want 2/7 days met", got)
+	}
+}
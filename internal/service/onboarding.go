@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+// maxStartPayload is Telegram's limit for /start deep-link parameter.
+const maxStartPayload = 64
+
+// onboardingSteps is wizard order; done is last.
+var onboardingSteps = []string{
+	models.OnboardingStepLanguage,
+	models.OnboardingStepTimeZone,
+	models.OnboardingStepActivities,
+	models.OnboardingStepInterval,
+	models.OnboardingStepQuiet,
+	models.OnboardingStepTimer,
+	models.OnboardingStepDone,
+}
+
+// OnboardingService drives first-run wizard and stores /start attribution.
+type OnboardingService interface {
+	// Step returns step user stopped at; done when wizard is finished or skipped.
+	Step(ctx context.Context, userID int64) (string, error)
+	// Advance moves user past step and returns next one. Repeated taps on
+	// an already passed step do not move user back.
+	Advance(ctx context.Context, userID int64, step string) (string, error)
+	Finish(ctx context.Context, userID int64) error
+	// RecordStartPayload stores first /start payload (campaign, referral) on user.
+	RecordStartPayload(ctx context.Context, userID int64, payload string) error
+}
+
+type onboardingService struct {
+	repo repo.OnboardingRepository
+}
+
+// NewOnboardingService creates onboarding service.
+func NewOnboardingService(repo repo.OnboardingRepository) OnboardingService {
+	return &onboardingService{repo: repo}
+}
+
+func (s *onboardingService) Step(ctx context.Context, userID int64) (string, error) {
+	step, err := s.repo.GetStep(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(onboardingSteps, step) {
+		return models.OnboardingStepDone, nil
+	}
+	return step, nil
+}
+
+func (s *onboardingService) Advance(ctx context.Context, userID int64, step string) (string, error) {
+	idx := slices.Index(onboardingSteps, step)
+	if idx < 0 || step == models.OnboardingStepDone {
+		return "", fmt.Errorf("advance onboarding: unknown step %q", step)
+	}
+	current, err := s.Step(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	next := onboardingSteps[idx+1]
+	if slices.Index(onboardingSteps, current) >= idx+1 {
+		return current, nil
+	}
+	if err := s.repo.SetStep(ctx, userID, next); err != nil {
+		return "", err
+	}
+	return next, nil
+}
+
+func (s *onboardingService) Finish(ctx context.Context, userID int64) error {
+	return s.repo.SetStep(ctx, userID, models.OnboardingStepDone)
+}
+
+func (s *onboardingService) RecordStartPayload(ctx context.Context, userID int64, payload string) error {
+	payload = strings.TrimSpace(payload)
+	if payload == "" || len(payload) > maxStartPayload {
+		return nil
+	}
+	// Invite tokens are one-time secrets; keep only the source.
+	if strings.HasPrefix(payload, PartnerInvitePrefix) {
+		payload = strings.TrimSuffix(PartnerInvitePrefix, "_")
+	}
+	_, err := s.repo.RecordStartPayload(ctx, userID, payload)
+	return err
+}
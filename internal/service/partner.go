@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+// PartnerInvitePrefix marks partner invites in /start deep-link payload.
+const PartnerInvitePrefix = "invite_"
+
+// partnerInviteTTL is how long an invite link can be accepted.
+const partnerInviteTTL = 7 * 24 * time.Hour
+
+// PartnerService contains accountability partner use-cases.
+type PartnerService interface {
+	// CreateInvite returns deep link that pairs whoever opens it with userID.
+	CreateInvite(ctx context.Context, userID int64) (string, time.Time, error)
+	GetInvite(ctx context.Context, token string) (models.PartnerInvite, error)
+	AcceptInvite(ctx context.Context, token string, userID int64) (models.Partner, error)
+	List(ctx context.Context, userID int64) ([]models.Partner, error)
+	Get(ctx context.Context, partnershipID, userID int64) (models.Partner, error)
+	// Progress returns partner's goals for activities partner shares with viewer.
+	Progress(ctx context.Context, partnershipID, viewerID int64) (models.PartnerProgress, error)
+	ToggleActivity(ctx context.Context, partnershipID, userID, activityID int64) (models.Partner, error)
+	End(ctx context.Context, partnershipID, userID int64) error
+	// Nudge records nudge when partner tracked nothing today (partner's time zone), once per day.
+	Nudge(ctx context.Context, partnershipID, fromUserID int64) (models.Partner, error)
+	// ReleaseNudge forgets today's nudge, e.g. when it could not be delivered.
+	ReleaseNudge(ctx context.Context, partner models.Partner, fromUserID int64) error
+}
+
+type partnerService struct {
+	repo        repo.PartnerRepository
+	goalRepo    repo.GoalRepository
+	botUsername string
+}
+
+// NewPartnerService creates partner service; botUsername builds t.me invite links.
+func NewPartnerService(repo repo.PartnerRepository, goalRepo repo.GoalRepository, botUsername string) PartnerService {
+	return &partnerService{
+		repo:        repo,
+		goalRepo:    goalRepo,
+		botUsername: botUsername,
+	}
+}
+
+func (s *partnerService) CreateInvite(ctx context.Context, userID int64) (string, time.Time, error) {
+	if s.botUsername == "" {
+		return "", time.Time{}, fmt.Errorf("create partner invite: bot username is unknown")
+	}
+	raw := make([]byte, 12)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, fmt.Errorf("create partner invite token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	expiresAt := time.Now().UTC().Add(partnerInviteTTL).Truncate(time.Second)
+	if err := s.repo.CreateInvite(ctx, token, userID, expiresAt); err != nil {
+		return "", time.Time{}, err
+	}
+	return fmt.Sprintf("https://t.me/%s?start=%s%s", s.botUsername, PartnerInvitePrefix, token), expiresAt, nil
+}
+
+func (s *partnerService) GetInvite(ctx context.Context, token string) (models.PartnerInvite, error) {
+	return s.repo.GetInvite(ctx, token, time.Now().UTC())
+}
+
+func (s *partnerService) AcceptInvite(ctx context.Context, token string, userID int64) (models.Partner, error) {
+	id, err := s.repo.AcceptInvite(ctx, token, userID, time.Now().UTC())
+	if err != nil {
+		return models.Partner{}, err
+	}
+	return s.repo.Get(ctx, id, userID)
+}
+
+func (s *partnerService) List(ctx context.Context, userID int64) ([]models.Partner, error) {
+	return s.repo.List(ctx, userID)
+}
+
+func (s *partnerService) Get(ctx context.Context, partnershipID, userID int64) (models.Partner, error) {
+	return s.repo.Get(ctx, partnershipID, userID)
+}
+
+func (s *partnerService) Progress(ctx context.Context, partnershipID, viewerID int64) (models.PartnerProgress, error) {
+	partner, err := s.repo.Get(ctx, partnershipID, viewerID)
+	if err != nil {
+		return models.PartnerProgress{}, err
+	}
+	shared, err := s.repo.SharedActivityIDs(ctx, partnershipID, partner.UserID)
+	if err != nil {
+		return models.PartnerProgress{}, err
+	}
+
+	now := time.Now()
+	out := models.PartnerProgress{Partner: partner}
+	if len(shared) > 0 {
+		goals, err := goalProgress(ctx, s.goalRepo, partner.UserID, now.UTC())
+		if err != nil {
+			return models.PartnerProgress{}, err
+		}
+		for _, g := range goals {
+			if slices.Contains(shared, g.Goal.ActivityID) {
+				out.Goals = append(out.Goals, g)
+			}
+		}
+	}
+	out.TrackedToday, err = s.repo.HasTrackedSince(ctx, partner.UserID, partnerDayStart(partner, now))
+	if err != nil {
+		return models.PartnerProgress{}, err
+	}
+	return out, nil
+}
+
+func (s *partnerService) ToggleActivity(ctx context.Context, partnershipID, userID, activityID int64) (models.Partner, error) {
+	if err := s.repo.ToggleActivity(ctx, partnershipID, userID, activityID); err != nil {
+		return models.Partner{}, err
+	}
+	return s.repo.Get(ctx, partnershipID, userID)
+}
+
+func (s *partnerService) End(ctx context.Context, partnershipID, userID int64) error {
+	return s.repo.Delete(ctx, partnershipID, userID)
+}
+
+func (s *partnerService) Nudge(ctx context.Context, partnershipID, fromUserID int64) (models.Partner, error) {
+	partner, err := s.repo.Get(ctx, partnershipID, fromUserID)
+	if err != nil {
+		return models.Partner{}, err
+	}
+	dayStart := partnerDayStart(partner, time.Now())
+	tracked, err := s.repo.HasTrackedSince(ctx, partner.UserID, dayStart)
+	if err != nil {
+		return models.Partner{}, err
+	}
+	if tracked {
+		return models.Partner{}, models.ErrPartnerTracked
+	}
+	claimed, err := s.repo.ClaimNudge(ctx, partnershipID, fromUserID, dayStart)
+	if err != nil {
+		return models.Partner{}, err
+	}
+	if !claimed {
+		return models.Partner{}, models.ErrAlreadyNudged
+	}
+	return partner, nil
+}
+
+func (s *partnerService) ReleaseNudge(ctx context.Context, partner models.Partner, fromUserID int64) error {
+	return s.repo.ReleaseNudge(ctx, partner.PartnershipID, fromUserID, partnerDayStart(partner, time.Now()))
+}
+
+// partnerDayStart returns start of partner's current day; unknown zones fall back to UTC.
+func partnerDayStart(partner models.Partner, now time.Time) time.Time {
+	loc, err := time.LoadLocation(partner.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
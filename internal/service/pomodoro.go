@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+// defaultPomodoroSettings mirrors pomodoro_settings column defaults.
+var defaultPomodoroSettings = models.PomodoroSettings{
+	FocusMin:       25,
+	ShortBreakMin:  5,
+	LongBreakMin:   15,
+	LongBreakEvery: 4,
+}
+
+// PomodoroService contains pomodoro use-cases: settings, focus/break cycle and recording focus as sessions.
+type PomodoroService interface {
+	GetSettings(ctx context.Context, userID int64) (models.PomodoroSettings, error)
+	SaveSettings(ctx context.Context, userID int64, s models.PomodoroSettings) error
+	// Start begins first focus block for activity; ErrPomodoroRunning if one is already running.
+	Start(ctx context.Context, userID, activityID int64) (models.Pomodoro, error)
+	Get(ctx context.Context, userID int64) (models.Pomodoro, error)
+	ListRunning(ctx context.Context) ([]models.Pomodoro, error)
+	// Advance finishes due phase at now: finished focus is saved as session and a break starts,
+	// finished break waits for NextFocus. false means phase was already advanced elsewhere.
+	Advance(ctx context.Context, p models.Pomodoro, now time.Time) (models.Pomodoro, bool, error)
+	// SetShown remembers countdown message state of p's current phase.
+	SetShown(ctx context.Context, p models.Pomodoro, messageID, shownMin int) error
+	// NextFocus starts next focus block from a break or after it; running focus is returned as is.
+	NextFocus(ctx context.Context, userID int64) (models.Pomodoro, error)
+	// Stop ends pomodoro; focus time already spent is saved when it is at least a minute.
+	Stop(ctx context.Context, userID int64) (models.Pomodoro, time.Duration, error)
+}
+
+type pomodoroService struct {
+	repo repo.PomodoroRepository
+}
+
+// NewPomodoroService creates pomodoro service.
+func NewPomodoroService(repo repo.PomodoroRepository) PomodoroService {
+	return &pomodoroService{repo: repo}
+}
+
+func (s *pomodoroService) GetSettings(ctx context.Context, userID int64) (models.PomodoroSettings, error) {
+	settings, ok, err := s.repo.GetSettings(ctx, userID)
+	if err != nil {
+		return models.PomodoroSettings{}, err
+	}
+	if !ok {
+		return defaultPomodoroSettings, nil
+	}
+	return settings, nil
+}
+
+// SaveSettings validates ranges of chk_pomodoro_settings before saving.
+func (s *pomodoroService) SaveSettings(ctx context.Context, userID int64, settings models.PomodoroSettings) error {
+	if settings.FocusMin < 1 || settings.FocusMin > 180 ||
+		settings.ShortBreakMin < 1 || settings.ShortBreakMin > 60 ||
+		settings.LongBreakMin < 1 || settings.LongBreakMin > 120 ||
+		settings.LongBreakEvery < 2 || settings.LongBreakEvery > 12 {
+		return fmt.Errorf("save pomodoro settings: invalid settings")
+	}
+	return s.repo.SaveSettings(ctx, userID, settings)
+}
+
+func (s *pomodoroService) Start(ctx context.Context, userID, activityID int64) (models.Pomodoro, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return models.Pomodoro{}, err
+	}
+	// Second precision keeps phase_ends_at equal to what database returns, it guards transitions.
+	now := time.Now().UTC().Truncate(time.Second)
+	p := models.Pomodoro{
+		UserID:         userID,
+		ActivityID:     activityID,
+		Phase:          models.PomodoroPhaseFocus,
+		Cycle:          1,
+		PhaseStartedAt: now,
+		PhaseEndsAt:    now.Add(time.Duration(settings.FocusMin) * time.Minute),
+		Settings:       settings,
+	}
+	if err := s.repo.Start(ctx, p); err != nil {
+		return models.Pomodoro{}, err
+	}
+	return s.repo.Get(ctx, userID)
+}
+
+func (s *pomodoroService) Get(ctx context.Context, userID int64) (models.Pomodoro, error) {
+	return s.repo.Get(ctx, userID)
+}
+
+func (s *pomodoroService) ListRunning(ctx context.Context) ([]models.Pomodoro, error) {
+	return s.repo.ListRunning(ctx)
+}
+
+func (s *pomodoroService) Advance(ctx context.Context, p models.Pomodoro, now time.Time) (models.Pomodoro, bool, error) {
+	next := nextPomodoroPhase(p, now.UTC().Truncate(time.Second))
+	ok, err := s.repo.Advance(ctx, p, next)
+	if err != nil || !ok {
+		return models.Pomodoro{}, false, err
+	}
+	return next, true, nil
+}
+
+func (s *pomodoroService) SetShown(ctx context.Context, p models.Pomodoro, messageID, shownMin int) error {
+	return s.repo.SetShown(ctx, p.UserID, p.PhaseEndsAt, messageID, shownMin)
+}
+
+func (s *pomodoroService) NextFocus(ctx context.Context, userID int64) (models.Pomodoro, error) {
+	p, err := s.repo.Get(ctx, userID)
+	if err != nil || p.Phase == models.PomodoroPhaseFocus {
+		return p, err
+	}
+	next := nextPomodoroFocus(p, time.Now().UTC().Truncate(time.Second))
+	ok, err := s.repo.Advance(ctx, p, next)
+	if err != nil {
+		return models.Pomodoro{}, err
+	}
+	if !ok {
+		// Break ended on scheduler meanwhile or pomodoro was stopped; show what is there now.
+		return s.repo.Get(ctx, userID)
+	}
+	return next, nil
+}
+
+func (s *pomodoroService) Stop(ctx context.Context, userID int64) (models.Pomodoro, time.Duration, error) {
+	return s.repo.Stop(ctx, userID, time.Now().UTC())
+}
+
+// nextPomodoroPhase returns p moved to the phase that follows its current one, starting at now.
+// Focus is followed by a short break, or a long one after every LongBreakEvery focus blocks;
+// a break is followed by waiting, so focus never starts without the user.
+func nextPomodoroPhase(p models.Pomodoro, now time.Time) models.Pomodoro {
+	next := p
+	next.PhaseStartedAt = now
+	next.MessageID = 0
+	next.ShownMin = 0
+
+	var minutes int
+	switch {
+	case p.Phase != models.PomodoroPhaseFocus:
+		next.Phase = models.PomodoroPhaseWaiting
+	case p.Settings.LongBreakEvery > 0 && p.Cycle%p.Settings.LongBreakEvery == 0:
+		next.Phase = models.PomodoroPhaseLongBreak
+		minutes = p.Settings.LongBreakMin
+	default:
+		next.Phase = models.PomodoroPhaseShortBreak
+		minutes = p.Settings.ShortBreakMin
+	}
+	next.PhaseEndsAt = now.Add(time.Duration(minutes) * time.Minute)
+	return next
+}
+
+// nextPomodoroFocus returns p moved to the focus block that follows its break, starting at now.
+func nextPomodoroFocus(p models.Pomodoro, now time.Time) models.Pomodoro {
+	next := p
+	next.Phase = models.PomodoroPhaseFocus
+	next.Cycle = p.Cycle + 1
+	next.PhaseStartedAt = now
+	next.PhaseEndsAt = now.Add(time.Duration(p.Settings.FocusMin) * time.Minute)
+	next.MessageID = 0
+	next.ShownMin = 0
+	return next
+}
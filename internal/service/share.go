@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+// ShareService issues and verifies signed, expiring read-only report links.
+type ShareService interface {
+	// Enabled reports whether links can be issued (base URL and secret are configured).
+	Enabled() bool
+	CreateLink(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) (models.ReportShare, string, error)
+	// Resolve verifies token signature, expiry and revocation.
+	Resolve(ctx context.Context, token string) (models.ReportShare, error)
+	ListActive(ctx context.Context, userID int64) ([]models.ReportShare, error)
+	Revoke(ctx context.Context, userID, id int64) error
+}
+
+type shareService struct {
+	repo    repo.ShareRepository
+	baseURL string
+	secret  []byte
+	ttl     time.Duration
+}
+
+// NewShareService creates share service; links are disabled when baseURL or secret is empty.
+func NewShareService(repo repo.ShareRepository, baseURL, secret string, ttl time.Duration) ShareService {
+	if ttl <= 0 {
+		ttl = 7 * 24 * time.Hour
+	}
+	return &shareService{
+		repo:    repo,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  []byte(secret),
+		ttl:     ttl,
+	}
+}
+
+func (s *shareService) Enabled() bool {
+	return s.baseURL != "" && len(s.secret) > 0
+}
+
+// CreateLink stores share and returns its public URL; from and to are local midnights
+// whose zone is kept with the share so the link covers the same instants as the card.
+func (s *shareService) CreateLink(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) (models.ReportShare, string, error) {
+	if !s.Enabled() {
+		return models.ReportShare{}, "", fmt.Errorf("create share link: sharing is not configured")
+	}
+	share := models.ReportShare{
+		UserID:      userID,
+		From:        from,
+		To:          to,
+		TimeZone:    from.Location().String(),
+		ActivityIDs: activityIDs,
+		ExpiresAt:   time.Now().UTC().Add(s.ttl).Truncate(time.Second),
+	}
+	id, err := s.repo.Create(ctx, share)
+	if err != nil {
+		return models.ReportShare{}, "", err
+	}
+	share.ID = id
+	return share, s.baseURL + "/r/" + s.sign(id, share.ExpiresAt), nil
+}
+
+// Resolve checks token and returns share it points to.
+func (s *shareService) Resolve(ctx context.Context, token string) (models.ReportShare, error) {
+	id, expiresAt, ok := s.verify(token)
+	if !ok {
+		return models.ReportShare{}, models.ErrShareNotFound
+	}
+	if time.Now().After(expiresAt) {
+		return models.ReportShare{}, models.ErrShareExpired
+	}
+	share, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return models.ReportShare{}, err
+	}
+	if share.RevokedAt != nil || time.Now().After(share.ExpiresAt) {
+		return models.ReportShare{}, models.ErrShareExpired
+	}
+	return share, nil
+}
+
+func (s *shareService) ListActive(ctx context.Context, userID int64) ([]models.ReportShare, error) {
+	return s.repo.ListActive(ctx, userID, time.Now().UTC())
+}
+
+func (s *shareService) Revoke(ctx context.Context, userID, id int64) error {
+	return s.repo.Revoke(ctx, userID, id)
+}
+
+// sign builds "<id>.<expires_unix>.<hmac>" token.
+func (s *shareService) sign(id int64, expiresAt time.Time) string {
+	payload := strconv.FormatInt(id, 10) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + s.mac(payload)
+}
+
+func (s *shareService) verify(token string) (int64, time.Time, bool) {
+	if !s.Enabled() {
+		return 0, time.Time{}, false
+	}
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return 0, time.Time{}, false
+	}
+	payload, sig := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.mac(payload))) {
+		return 0, time.Time{}, false
+	}
+	idRaw, expRaw, ok := strings.Cut(payload, ".")
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	exp, err := strconv.ParseInt(expRaw, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return id, time.Unix(exp, 0), true
+}
+
+func (s *shareService) mac(payload string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+// TagService contains activity tag use-cases.
+type TagService interface {
+	CreateTag(ctx context.Context, userID int64, input string) (models.Tag, error)
+	ListTags(ctx context.Context, userID int64) ([]models.Tag, error)
+	DeleteTag(ctx context.Context, userID, tagID int64) error
+	ToggleActivityTag(ctx context.Context, userID, activityID, tagID int64) error
+	ListTagActivityIDs(ctx context.Context, userID, tagID int64) ([]int64, error)
+	ResolveActivityIDs(ctx context.Context, userID, tagID int64) ([]int64, error)
+}
+
+type tagService struct {
+	repo repo.TagRepository
+}
+
+// NewTagService creates tag service.
+func NewTagService(repo repo.TagRepository) TagService {
+	return &tagService{repo: repo}
+}
+
+// CreateTag creates "Name" or "Parent/Name"; missing parent is created too.
+func (s *tagService) CreateTag(ctx context.Context, userID int64, input string) (models.Tag, error) {
+	parts := strings.Split(input, "/")
+	if len(parts) > 2 {
+		return models.Tag{}, fmt.Errorf("create tag: only one parent level is supported")
+	}
+	name := strings.TrimSpace(parts[len(parts)-1])
+	if name == "" {
+		return models.Tag{}, fmt.Errorf("create tag: empty name")
+	}
+
+	var parentID int64
+	if len(parts) == 2 {
+		parentName := strings.TrimSpace(parts[0])
+		parent, err := s.repo.GetByName(ctx, userID, parentName)
+		if errors.Is(err, models.ErrTagNotFound) {
+			parent, err = s.repo.Create(ctx, userID, parentName, 0)
+		}
+		if err != nil {
+			return models.Tag{}, err
+		}
+		parentID = parent.ID
+	}
+
+	tag, err := s.repo.Create(ctx, userID, name, parentID)
+	if err != nil {
+		return models.Tag{}, err
+	}
+	return tag, nil
+}
+
+// ListTags returns tags with display paths like "Learning › Go".
+func (s *tagService) ListTags(ctx context.Context, userID int64) ([]models.Tag, error) {
+	tags, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return withTagPaths(tags), nil
+}
+
+func (s *tagService) DeleteTag(ctx context.Context, userID, tagID int64) error {
+	return s.repo.Delete(ctx, userID, tagID)
+}
+
+func (s *tagService) ToggleActivityTag(ctx context.Context, userID, activityID, tagID int64) error {
+	return s.repo.ToggleActivityTag(ctx, userID, activityID, tagID)
+}
+
+func (s *tagService) ListTagActivityIDs(ctx context.Context, userID, tagID int64) ([]int64, error) {
+	return s.repo.ListTagActivityIDs(ctx, userID, tagID)
+}
+
+// ResolveActivityIDs returns activities of tag and all its subtags for report filters.
+func (s *tagService) ResolveActivityIDs(ctx context.Context, userID, tagID int64) ([]int64, error) {
+	return s.repo.ResolveActivityIDs(ctx, userID, tagID)
+}
+
+// withTagPaths fills Path from parent chain and sorts children under parents.
+func withTagPaths(tags []models.Tag) []models.Tag {
+	byID := make(map[int64]models.Tag, len(tags))
+	for _, t := range tags {
+		byID[t.ID] = t
+	}
+
+	out := make([]models.Tag, 0, len(tags))
+	var walk func(parentID int64)
+	walk = func(parentID int64) {
+		for _, t := range tags {
+			if t.ParentID != parentID {
+				continue
+			}
+			path := t.Name
+			for p, ok := byID[t.ParentID]; ok; p, ok = byID[p.ParentID] {
+				path = p.Name + " › " + path
+			}
+			t.Path = path
+			out = append(out, t)
+			walk(t.ID)
+		}
+	}
+	walk(0)
+	return out
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/repo"
+)
+
+// TeamService contains group team use-cases: membership, privacy choices and leaderboards.
+type TeamService interface {
+	Register(ctx context.Context, chatID int64, title string) (models.Team, error)
+	Deactivate(ctx context.Context, chatID int64) error
+	MigrateChat(ctx context.Context, fromChatID, toChatID int64) error
+	GetByChat(ctx context.Context, chatID int64) (models.Team, error)
+	UpdateSettings(ctx context.Context, teamID int64, s models.TeamSettings) error
+	// Join adds user to group's team; joined is false when user already was a member.
+	Join(ctx context.Context, chatID, userID int64, displayName string) (team models.Team, joined bool, err error)
+	Leave(ctx context.Context, teamID, userID int64) error
+	ListMemberships(ctx context.Context, userID int64) ([]models.TeamMembership, error)
+	GetMembership(ctx context.Context, teamID, userID int64) (models.TeamMembership, error)
+	ToggleSharing(ctx context.Context, teamID, userID int64) (models.TeamMembership, error)
+	ToggleActivity(ctx context.Context, teamID, userID, activityID int64) (models.TeamMembership, error)
+	// Leaderboard returns opted-in members' totals within [from, to), highest first.
+	Leaderboard(ctx context.Context, teamID int64, from, to time.Time) ([]models.LeaderboardEntry, error)
+	ListDueDigests(ctx context.Context, now time.Time) ([]models.TeamDigestDue, error)
+	ClaimDigest(ctx context.Context, due models.TeamDigestDue) (bool, error)
+	ReleaseDigest(ctx context.Context, due models.TeamDigestDue) error
+}
+
+type teamService struct {
+	repo repo.TeamRepository
+}
+
+// NewTeamService creates team service.
+func NewTeamService(repo repo.TeamRepository) TeamService {
+	return &teamService{repo: repo}
+}
+
+func (s *teamService) Register(ctx context.Context, chatID int64, title string) (models.Team, error) {
+	if chatID >= 0 {
+		return models.Team{}, fmt.Errorf("register team: chat %d is not a group", chatID)
+	}
+	return s.repo.Upsert(ctx, chatID, title)
+}
+
+func (s *teamService) Deactivate(ctx context.Context, chatID int64) error {
+	return s.repo.SetActive(ctx, chatID, false)
+}
+
+func (s *teamService) MigrateChat(ctx context.Context, fromChatID, toChatID int64) error {
+	return s.repo.MigrateChat(ctx, fromChatID, toChatID)
+}
+
+func (s *teamService) GetByChat(ctx context.Context, chatID int64) (models.Team, error) {
+	return s.repo.GetByChat(ctx, chatID)
+}
+
+func (s *teamService) UpdateSettings(ctx context.Context, teamID int64, settings models.TeamSettings) error {
+	if settings.DigestWeekday < time.Sunday || settings.DigestWeekday > time.Saturday ||
+		settings.DigestHour < 0 || settings.DigestHour > 23 || settings.LeaderboardDays <= 0 {
+		return fmt.Errorf("update team settings: invalid settings")
+	}
+	return s.repo.UpdateSettings(ctx, teamID, settings)
+}
+
+func (s *teamService) Join(ctx context.Context, chatID, userID int64, displayName string) (models.Team, bool, error) {
+	team, err := s.repo.GetByChat(ctx, chatID)
+	if err != nil {
+		return models.Team{}, false, err
+	}
+	joined, err := s.repo.Join(ctx, team.ID, userID, displayName)
+	if err != nil {
+		return models.Team{}, false, err
+	}
+	return team, joined, nil
+}
+
+func (s *teamService) Leave(ctx context.Context, teamID, userID int64) error {
+	return s.repo.Leave(ctx, teamID, userID)
+}
+
+func (s *teamService) ListMemberships(ctx context.Context, userID int64) ([]models.TeamMembership, error) {
+	return s.repo.ListMemberships(ctx, userID)
+}
+
+func (s *teamService) GetMembership(ctx context.Context, teamID, userID int64) (models.TeamMembership, error) {
+	return s.repo.GetMembership(ctx, teamID, userID)
+}
+
+// ToggleSharing switches whether member appears on leaderboard and digests.
+func (s *teamService) ToggleSharing(ctx context.Context, teamID, userID int64) (models.TeamMembership, error) {
+	m, err := s.repo.GetMembership(ctx, teamID, userID)
+	if err != nil {
+		return models.TeamMembership{}, err
+	}
+	if err := s.repo.SetSharing(ctx, teamID, userID, !m.Sharing); err != nil {
+		return models.TeamMembership{}, err
+	}
+	m.Sharing = !m.Sharing
+	return m, nil
+}
+
+func (s *teamService) ToggleActivity(ctx context.Context, teamID, userID, activityID int64) (models.TeamMembership, error) {
+	if err := s.repo.ToggleActivity(ctx, teamID, userID, activityID); err != nil {
+		return models.TeamMembership{}, err
+	}
+	return s.repo.GetMembership(ctx, teamID, userID)
+}
+
+func (s *teamService) Leaderboard(ctx context.Context, teamID int64, from, to time.Time) ([]models.LeaderboardEntry, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("team leaderboard: invalid range")
+	}
+	return s.repo.Leaderboard(ctx, teamID, from, to)
+}
+
+// ListDueDigests returns teams whose weekly digest time (UTC) has passed this week.
+// A team gets its first digest once it has existed for a whole week.
+func (s *teamService) ListDueDigests(ctx context.Context, now time.Time) ([]models.TeamDigestDue, error) {
+	teams, err := s.repo.ListDigestEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now = now.UTC()
+	var out []models.TeamDigestDue
+	for _, t := range teams {
+		periodStart := teamDigestSlot(now, t.Settings)
+		if now.Before(periodStart) || periodStart.Before(t.CreatedAt.AddDate(0, 0, 7)) {
+			continue
+		}
+		if t.LastDigestAt != nil && !t.LastDigestAt.Before(periodStart) {
+			continue
+		}
+		out = append(out, models.TeamDigestDue{Team: t, PeriodStart: periodStart})
+	}
+	return out, nil
+}
+
+func (s *teamService) ClaimDigest(ctx context.Context, due models.TeamDigestDue) (bool, error) {
+	return s.repo.ClaimDigest(ctx, due.Team.ID, due.PeriodStart)
+}
+
+func (s *teamService) ReleaseDigest(ctx context.Context, due models.TeamDigestDue) error {
+	return s.repo.ReleaseDigest(ctx, due.Team.ID, due.PeriodStart)
+}
+
+// teamDigestSlot returns digest time of the current week in UTC; on digest weekday it may still be ahead of now.
+func teamDigestSlot(now time.Time, settings models.TeamSettings) time.Time {
+	back := (int(now.Weekday()) - int(settings.DigestWeekday) + 7) % 7
+	day := time.Date(now.Year(), now.Month(), now.Day(), settings.DigestHour, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -back)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"tracker-bot/internal/models"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// maxTemplateActivities bounds one import or pack.
+	maxTemplateActivities = 50
+	// maxTemplateNameRunes keeps imported names readable on buttons.
+	maxTemplateNameRunes = 64
+)
+
+// ActivityPacks are built-in starter packs shown in templates menu.
+var ActivityPacks = []models.ActivityPack{
+	{Key: "dev", Name: "💻 Developer", Activities: []models.ActivityTemplate{
+		{Name: "Coding", Emoji: "💻"},
+		{Name: "Code review", Emoji: "🔍"},
+		{Name: "Meetings", Emoji: "👥"},
+		{Name: "Debugging", Emoji: "🐞"},
+		{Name: "Learning", Emoji: "📚"},
+		{Name: "Docs", Emoji: "📝"},
+	}},
+	{Key: "student", Name: "🎓 Student", Activities: []models.ActivityTemplate{
+		{Name: "Lectures", Emoji: "🎓"},
+		{Name: "Homework", Emoji: "📓"},
+		{Name: "Reading", Emoji: "📚"},
+		{Name: "Exam prep", Emoji: "🧠"},
+		{Name: "Projects", Emoji: "🛠"},
+	}},
+	{Key: "fitness", Name: "🏋️ Fitness", Activities: []models.ActivityTemplate{
+		{Name: "Workout", Emoji: "🏋️"},
+		{Name: "Running", Emoji: "🏃"},
+		{Name: "Stretching", Emoji: "🤸"},
+		{Name: "Walking", Emoji: "🚶"},
+		{Name: "Meal prep", Emoji: "🥗"},
+	}},
+	{Key: "lang", Name: "🗣 Language learner", Activities: []models.ActivityTemplate{
+		{Name: "Vocabulary", Emoji: "🔤"},
+		{Name: "Grammar", Emoji: "📖"},
+		{Name: "Listening", Emoji: "🎧"},
+		{Name: "Speaking", Emoji: "🗣"},
+		{Name: "Writing", Emoji: "✍️"},
+	}},
+}
+
+// ActivityPack returns built-in pack by key.
+func (srv *trackerService) ActivityPack(key string) (models.ActivityPack, error) {
+	for _, p := range ActivityPacks {
+		if p.Key == key {
+			return p, nil
+		}
+	}
+	return models.ActivityPack{}, models.ErrPackNotFound
+}
+
+// CreateActivities validates templates and creates them in one batch; existing names are skipped.
+func (srv *trackerService) CreateActivities(ctx context.Context, userID int64, items []models.ActivityTemplate) (models.BulkCreateResult, error) {
+	items, err := normalizeTemplates(items)
+	if err != nil {
+		return models.BulkCreateResult{}, err
+	}
+	created, err := srv.repo.CreateMany(ctx, userID, items)
+	if err != nil {
+		return models.BulkCreateResult{}, err
+	}
+
+	done := make(map[string]bool, len(created))
+	var res models.BulkCreateResult
+	for _, a := range created {
+		done[strings.ToLower(a.Name)] = true
+		res.Created = append(res.Created, a.Name)
+	}
+	for _, it := range items {
+		if !done[strings.ToLower(it.Name)] {
+			res.Skipped = append(res.Skipped, it.Name)
+		}
+	}
+	return res, nil
+}
+
+// ExportActivities returns active activities as JSON template that ParseActivityPack accepts.
+func (srv *trackerService) ExportActivities(ctx context.Context, userID int64) ([]byte, error) {
+	list, err := srv.repo.ListActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	pack := models.ActivityPack{Name: "My activities", Activities: make([]models.ActivityTemplate, 0, len(list))}
+	for _, a := range list {
+		pack.Activities = append(pack.Activities, models.ActivityTemplate{Name: a.Name, Emoji: a.Emoji})
+	}
+	out, err := json.MarshalIndent(pack, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("export activities: %w", err)
+	}
+	return out, nil
+}
+
+// ParseActivityPack reads template document in JSON or YAML.
+func (srv *trackerService) ParseActivityPack(data []byte) (models.ActivityPack, error) {
+	data = bytes.TrimSpace(data)
+	var (
+		pack models.ActivityPack
+		err  error
+	)
+	// JSON is valid YAML, but YAML rejects tab indentation that JSON allows.
+	if bytes.HasPrefix(data, []byte("{")) {
+		err = json.Unmarshal(data, &pack)
+	} else {
+		err = yaml.Unmarshal(data, &pack)
+	}
+	if err != nil {
+		return models.ActivityPack{}, fmt.Errorf("%w: %v", models.ErrInvalidTemplate, err)
+	}
+	if pack.Activities, err = normalizeTemplates(pack.Activities); err != nil {
+		return models.ActivityPack{}, err
+	}
+	return pack, nil
+}
+
+// normalizeTemplates trims names and emojis and drops case-insensitive duplicates.
+func normalizeTemplates(items []models.ActivityTemplate) ([]models.ActivityTemplate, error) {
+	out := make([]models.ActivityTemplate, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, it := range items {
+		it.Name = strings.TrimSpace(it.Name)
+		it.Emoji = strings.TrimSpace(it.Emoji)
+		if it.Name == "" || utf8.RuneCountInString(it.Name) > maxTemplateNameRunes ||
+			utf8.RuneCountInString(it.Emoji) > maxEmojiRunes {
+			return nil, models.ErrInvalidTemplate
+		}
+		key := strings.ToLower(it.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, it)
+	}
+	if len(out) == 0 {
+		return nil, models.ErrInvalidTemplate
+	}
+	if len(out) > maxTemplateActivities {
+		return nil, models.ErrTemplateTooLarge
+	}
+	return out, nil
+}
@@ -6,6 +6,25 @@ import (
 	"time"
 	"tracker-bot/internal/models"
 	"tracker-bot/internal/repo"
+	"tracker-bot/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// promptDeliveryRetention is how long delivery records are kept; claims only
+// need them for the current schedule slot, older rows are history.
+const promptDeliveryRetention = 7 * 24 * time.Hour
+
+const (
+	// defaultIntervalMin mirrors user_timer_settings.interval_min default.
+	defaultIntervalMin = 15
+	// maxIntervalMin mirrors chk_interval_min_range.
+	maxIntervalMin = 360
+	// defaultAdaptiveMinMin and defaultAdaptiveMaxMin mirror adaptive bound column defaults.
+	defaultAdaptiveMinMin = 10
+	defaultAdaptiveMaxMin = 60
+	// adaptiveGrowStreak is how many same answers in a row lengthen the interval.
+	adaptiveGrowStreak = 3
 )
 
 // TimerService contains timer-related use-cases.
@@ -13,26 +32,56 @@ type TimerService interface {
 	Activate(ctx context.Context, userID int64, intervalMin int) error
 	Stop(ctx context.Context, userID int64) error
 	ListDueUsers(ctx context.Context, now time.Time, limit int) ([]models.TimerDueUser, error)
-	MarkPromptSent(ctx context.Context, userID int64, intervalMin int, now time.Time) error
+	ListSchedule(ctx context.Context) ([]models.TimerSchedule, error)
+	ClaimDueUsers(ctx context.Context, now time.Time, limit int) ([]models.TimerDueUser, error)
+	MarkPromptSent(ctx context.Context, due models.TimerDueUser, sentAt time.Time) error
+	PruneDeliveries(ctx context.Context, now time.Time) error
 	RecordPromptAnswer(ctx context.Context, userID, activityID int64) error
 	RecordPromptAnswerWithInterval(ctx context.Context, userID, activityID int64, intervalMin int) error
+	RecordManualSession(ctx context.Context, userID, activityID int64, startAt, endAt time.Time) error
+	// RecordManualSessionOnce is RecordManualSession for deliveries that may repeat;
+	// false means session with key was already saved.
+	RecordManualSessionOnce(ctx context.Context, userID, activityID int64, startAt, endAt time.Time, key string) (bool, error)
+	GetSettings(ctx context.Context, userID int64) (models.TimerSettings, error)
+	// SaveDefaultInterval stores interval used when timer is started later.
+	SaveDefaultInterval(ctx context.Context, userID int64, intervalMin int) error
+	SetQuietHours(ctx context.Context, userID int64, quiet models.QuietHours) error
+	// PostponeQuiet moves claimed prompt that falls into user's quiet hours to the window end;
+	// true means prompt must not be sent.
+	PostponeQuiet(ctx context.Context, due models.TimerDueUser, now time.Time) (bool, error)
+	// SetAdaptive saves adaptive bounds; enabling it moves current interval into them.
+	SetAdaptive(ctx context.Context, userID int64, adaptive models.AdaptiveInterval) error
+	// AdaptInterval updates interval from prompt answer when adaptive interval is on;
+	// false means interval stayed the same.
+	AdaptInterval(ctx context.Context, userID, activityID int64) (int, bool, error)
+}
+
+// TimerEvents receives timer schedule changes, e.g. to keep scheduler queue in sync.
+type TimerEvents interface {
+	TimerScheduled(userID int64, nextPingAt time.Time)
+	TimerStopped(userID int64)
 }
 
 type timerService struct {
 	timerRepo   repo.TimerRepository
 	sessionRepo repo.SessionRepository
+	events      TimerEvents
 }
 
-// NewTimerService creates timer service.
-func NewTimerService(timerRepo repo.TimerRepository, sessionRepo repo.SessionRepository) TimerService {
+// NewTimerService creates timer service; events may be nil.
+func NewTimerService(timerRepo repo.TimerRepository, sessionRepo repo.SessionRepository, events TimerEvents) TimerService {
 	return &timerService{
 		timerRepo:   timerRepo,
 		sessionRepo: sessionRepo,
+		events:      events,
 	}
 }
 
 // Activate enables timer and schedules next prompt.
-func (s *timerService) Activate(ctx context.Context, userID int64, intervalMin int) error {
+func (s *timerService) Activate(ctx context.Context, userID int64, intervalMin int) (err error) {
+	ctx, span := tracing.Start(ctx, "TimerService.Activate", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	if userID <= 0 {
 		return fmt.Errorf("activate timer: invalid userID")
 	}
@@ -40,15 +89,30 @@ func (s *timerService) Activate(ctx context.Context, userID int64, intervalMin i
 		return fmt.Errorf("activate timer: invalid interval")
 	}
 	nextPingAt := time.Now().UTC().Add(time.Duration(intervalMin) * time.Minute)
-	return s.timerRepo.UpsertInterval(ctx, userID, intervalMin, nextPingAt)
+	if err := s.timerRepo.UpsertInterval(ctx, userID, intervalMin, nextPingAt); err != nil {
+		return err
+	}
+	if s.events != nil {
+		s.events.TimerScheduled(userID, nextPingAt)
+	}
+	return nil
 }
 
 // Stop disables timer for user.
-func (s *timerService) Stop(ctx context.Context, userID int64) error {
+func (s *timerService) Stop(ctx context.Context, userID int64) (err error) {
+	ctx, span := tracing.Start(ctx, "TimerService.Stop", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	if userID <= 0 {
 		return fmt.Errorf("stop timer: invalid userID")
 	}
-	return s.timerRepo.Disable(ctx, userID)
+	if err := s.timerRepo.Disable(ctx, userID); err != nil {
+		return err
+	}
+	if s.events != nil {
+		s.events.TimerStopped(userID)
+	}
+	return nil
 }
 
 // ListDueUsers returns users that should receive prompt now.
@@ -59,10 +123,45 @@ func (s *timerService) ListDueUsers(ctx context.Context, now time.Time, limit in
 	return s.timerRepo.ListDueUsers(ctx, now.UTC(), limit)
 }
 
-// MarkPromptSent moves next prompt time forward by interval.
-func (s *timerService) MarkPromptSent(ctx context.Context, userID int64, intervalMin int, now time.Time) error {
-	nextPingAt := now.UTC().Add(time.Duration(intervalMin) * time.Minute)
-	return s.timerRepo.SetNextPing(ctx, userID, nextPingAt)
+// ClaimDueUsers reserves due prompts for this instance; next prompt time is already moved forward.
+func (s *timerService) ClaimDueUsers(ctx context.Context, now time.Time, limit int) (claimed []models.TimerDueUser, err error) {
+	ctx, span := tracing.Start(ctx, "TimerService.ClaimDueUsers")
+	defer func() { tracing.End(span, err) }()
+
+	if limit <= 0 {
+		limit = 100
+	}
+	now = now.UTC()
+	claimed, err = s.timerRepo.ClaimDueUsers(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	if s.events != nil {
+		// Mirrors next_ping_at computed by the claim query.
+		for _, item := range claimed {
+			s.events.TimerScheduled(item.DBUserID, now.Add(time.Duration(item.IntervalMin)*time.Minute))
+		}
+	}
+	return claimed, nil
+}
+
+// ListSchedule returns next prompt time of all enabled timers.
+func (s *timerService) ListSchedule(ctx context.Context) ([]models.TimerSchedule, error) {
+	return s.timerRepo.ListSchedule(ctx)
+}
+
+// MarkPromptSent records that claimed prompt reached Telegram.
+func (s *timerService) MarkPromptSent(ctx context.Context, due models.TimerDueUser, sentAt time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "TimerService.MarkPromptSent", attribute.Int64("user.id", due.DBUserID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.timerRepo.MarkDelivered(ctx, due.DBUserID, due.ScheduledAt, sentAt.UTC())
+}
+
+// PruneDeliveries drops delivery records older than promptDeliveryRetention.
+func (s *timerService) PruneDeliveries(ctx context.Context, now time.Time) error {
+	_, err := s.timerRepo.PruneDeliveries(ctx, now.UTC().Add(-promptDeliveryRetention))
+	return err
 }
 
 // RecordPromptAnswer stores prompt answer using current timer interval from settings.
@@ -75,9 +174,189 @@ func (s *timerService) RecordPromptAnswer(ctx context.Context, userID, activityI
 }
 
 // RecordPromptAnswerWithInterval stores prompt answer for explicit interval.
-func (s *timerService) RecordPromptAnswerWithInterval(ctx context.Context, userID, activityID int64, intervalMin int) error {
+func (s *timerService) RecordPromptAnswerWithInterval(ctx context.Context, userID, activityID int64, intervalMin int) (err error) {
+	ctx, span := tracing.Start(ctx, "TimerService.RecordPromptAnswer", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	if intervalMin <= 0 {
 		return fmt.Errorf("invalid interval")
 	}
 	return s.sessionRepo.CreateRetroSession(ctx, userID, activityID, intervalMin, "prompt")
 }
+
+// RecordManualSession stores session entered by user with explicit bounds.
+func (s *timerService) RecordManualSession(ctx context.Context, userID, activityID int64, startAt, endAt time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "TimerService.RecordManualSession", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
+	if !endAt.After(startAt) {
+		return fmt.Errorf("invalid session bounds")
+	}
+	return s.sessionRepo.CreateSession(ctx, userID, activityID, startAt, endAt, "manual")
+}
+
+func (s *timerService) RecordManualSessionOnce(ctx context.Context, userID, activityID int64, startAt, endAt time.Time, key string) (saved bool, err error) {
+	ctx, span := tracing.Start(ctx, "TimerService.RecordManualSessionOnce", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
+	if !endAt.After(startAt) {
+		return false, fmt.Errorf("invalid session bounds")
+	}
+	return s.sessionRepo.CreateSessionOnce(ctx, userID, activityID, startAt, endAt, "manual", key)
+}
+
+// GetSettings returns saved timer settings or defaults.
+func (s *timerService) GetSettings(ctx context.Context, userID int64) (models.TimerSettings, error) {
+	settings, ok, err := s.timerRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return models.TimerSettings{}, err
+	}
+	if !ok {
+		return models.TimerSettings{
+			IntervalMin: defaultIntervalMin,
+			Adaptive:    models.AdaptiveInterval{MinMin: defaultAdaptiveMinMin, MaxMin: defaultAdaptiveMaxMin},
+		}, nil
+	}
+	return settings, nil
+}
+
+func (s *timerService) SaveDefaultInterval(ctx context.Context, userID int64, intervalMin int) error {
+	if intervalMin <= 0 || intervalMin > maxIntervalMin {
+		return fmt.Errorf("save default interval: invalid interval")
+	}
+	return s.timerRepo.SaveInterval(ctx, userID, intervalMin)
+}
+
+func (s *timerService) SetQuietHours(ctx context.Context, userID int64, quiet models.QuietHours) error {
+	if quiet.FromMin < 0 || quiet.FromMin >= 24*60 || quiet.ToMin < 0 || quiet.ToMin >= 24*60 {
+		return fmt.Errorf("set quiet hours: invalid window")
+	}
+	return s.timerRepo.SetQuietHours(ctx, userID, quiet)
+}
+
+func (s *timerService) PostponeQuiet(ctx context.Context, due models.TimerDueUser, now time.Time) (bool, error) {
+	until := quietUntil(now, due.TimeZone, due.Quiet)
+	if until.IsZero() {
+		return false, nil
+	}
+	until = until.UTC()
+	if err := s.timerRepo.SetNextPing(ctx, due.DBUserID, until); err != nil {
+		return true, fmt.Errorf("postpone quiet prompt: %w", err)
+	}
+	if s.events != nil {
+		s.events.TimerScheduled(due.DBUserID, until)
+	}
+	return true, nil
+}
+
+func (s *timerService) SetAdaptive(ctx context.Context, userID int64, adaptive models.AdaptiveInterval) (err error) {
+	ctx, span := tracing.Start(ctx, "TimerService.SetAdaptive", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
+	if adaptive.MinMin < 1 || adaptive.MinMin > adaptive.MaxMin || adaptive.MaxMin > maxIntervalMin {
+		return fmt.Errorf("set adaptive interval: invalid bounds")
+	}
+	if err := s.timerRepo.SetAdaptive(ctx, userID, adaptive); err != nil {
+		return err
+	}
+	if !adaptive.Enabled {
+		return nil
+	}
+	cur, ok, err := s.timerRepo.GetAdaptiveState(ctx, userID)
+	if err != nil || !ok {
+		return err
+	}
+	next := cur
+	next.IntervalMin = clampInterval(cur.IntervalMin, adaptive)
+	if next.IntervalMin == cur.IntervalMin {
+		return nil
+	}
+	return s.saveAdaptiveState(ctx, userID, cur, next)
+}
+
+func (s *timerService) AdaptInterval(ctx context.Context, userID, activityID int64) (intervalMin int, changed bool, err error) {
+	ctx, span := tracing.Start(ctx, "TimerService.AdaptInterval", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
+	cur, ok, err := s.timerRepo.GetAdaptiveState(ctx, userID)
+	if err != nil {
+		return 0, false, err
+	}
+	if !ok || !cur.Adaptive.Enabled {
+		return cur.IntervalMin, false, nil
+	}
+	next := nextAdaptiveState(cur, activityID)
+	if err := s.saveAdaptiveState(ctx, userID, cur, next); err != nil {
+		return 0, false, err
+	}
+	return next.IntervalMin, next.IntervalMin != cur.IntervalMin, nil
+}
+
+func (s *timerService) saveAdaptiveState(ctx context.Context, userID int64, cur, next models.AdaptiveState) error {
+	nextPingAt, err := s.timerRepo.SaveAdaptiveState(ctx, userID, cur, next)
+	if err != nil {
+		return err
+	}
+	if s.events != nil && !nextPingAt.IsZero() && next.IntervalMin != cur.IntervalMin {
+		s.events.TimerScheduled(userID, nextPingAt)
+	}
+	return nil
+}
+
+// nextAdaptiveState records answer with activityID in st. Every answer from
+// adaptiveGrowStreak same ones in a row lengthens interval by a quarter; a switch
+// right after another switch shortens it by a third. Result stays within bounds.
+func nextAdaptiveState(st models.AdaptiveState, activityID int64) models.AdaptiveState {
+	next := st
+	next.LastActivityID = activityID
+	next.SameAnswerStreak = 1
+	switch {
+	case st.LastActivityID == activityID:
+		next.SameAnswerStreak = st.SameAnswerStreak + 1
+		if next.SameAnswerStreak >= adaptiveGrowStreak {
+			next.IntervalMin += max(st.IntervalMin/4, 1)
+		}
+	case st.LastActivityID != 0 && st.SameAnswerStreak <= 1:
+		next.IntervalMin -= max(st.IntervalMin/3, 1)
+	}
+	next.IntervalMin = clampInterval(next.IntervalMin, st.Adaptive)
+	return next
+}
+
+func clampInterval(intervalMin int, bounds models.AdaptiveInterval) int {
+	return min(max(intervalMin, bounds.MinMin), bounds.MaxMin)
+}
+
+// quietUntil returns end of quiet window containing now, or zero time when now is outside of it.
+// Unknown zones fall back to UTC.
+func quietUntil(now time.Time, tz string, quiet models.QuietHours) time.Time {
+	if !quiet.Enabled() {
+		return time.Time{}
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	end := func(dayOffset int) time.Time {
+		d := midnight.AddDate(0, 0, dayOffset)
+		return time.Date(d.Year(), d.Month(), d.Day(), quiet.ToMin/60, quiet.ToMin%60, 0, 0, loc)
+	}
+
+	if quiet.FromMin < quiet.ToMin {
+		if minute >= quiet.FromMin && minute < quiet.ToMin {
+			return end(0)
+		}
+		return time.Time{}
+	}
+	// Window wraps past midnight, e.g. 22:00-08:00.
+	switch {
+	case minute >= quiet.FromMin:
+		return end(1)
+	case minute < quiet.ToMin:
+		return end(0)
+	}
+	return time.Time{}
+}
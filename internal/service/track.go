@@ -7,15 +7,39 @@ import (
 	"time"
 	"tracker-bot/internal/models"
 	"tracker-bot/internal/repo"
+	"tracker-bot/internal/tracing"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// DeleteUndoWindow is how long the "Undo" button restores deleted activities.
+// After it expires activities stay in trash and can be restored from there.
+const DeleteUndoWindow = time.Minute
+
+// maxEmojiRunes allows composed emoji (skin tones, ZWJ sequences) but not free text.
+const maxEmojiRunes = 8
+
 // TrackerService contains tracking use-cases used by handlers.
 type TrackerService interface {
 	GetMainStats(ctx context.Context, userID int64) (models.MainStats, error)
 	CreateActivity(ctx context.Context, userID int64, name, emoji string) (repo.Activity, error)
+	ActivityPack(key string) (models.ActivityPack, error)
+	// CreateActivities creates templates in bulk; names that already exist are reported as skipped.
+	CreateActivities(ctx context.Context, userID int64, items []models.ActivityTemplate) (models.BulkCreateResult, error)
+	ExportActivities(ctx context.Context, userID int64) ([]byte, error)
+	ParseActivityPack(data []byte) (models.ActivityPack, error)
+	RenameActivity(ctx context.Context, userID, activityID int64, name string) error
+	SetActivityEmoji(ctx context.Context, userID, activityID int64, emoji string) error
+	MoveActivity(ctx context.Context, userID, activityID int64, delta int) error
+	MergeActivities(ctx context.Context, userID, fromID, toID int64) (int64, error)
 	ListActivities(ctx context.Context, userID int64) ([]models.TrackActivityItem, error)
 	ToggleSelectedActivity(ctx context.Context, userID, activityID int64) error
-	DeleteSelectedActivities(ctx context.Context, userID int64) (int64, error)
+	DeleteSelectedActivities(ctx context.Context, userID int64) (int64, time.Time, error)
+	UndoDeleteActivities(ctx context.Context, userID int64, deletedAt time.Time) (int64, error)
+	ListTrashedActivities(ctx context.Context, userID int64) ([]models.TrackActivityItem, error)
+	RestoreTrashedActivity(ctx context.Context, userID, activityID int64) error
+	PurgeActivity(ctx context.Context, userID, activityID int64) (int64, error)
 	ListSelectedActivities(ctx context.Context, userID int64) ([]models.TrackActivityItem, error)
 	ListArchivedActivities(ctx context.Context, userID int64) ([]models.TrackActivityItem, error)
 	ArchiveSelectedActivities(ctx context.Context, userID int64) (int64, error)
@@ -29,13 +53,17 @@ type TrackerService interface {
 }
 
 type trackerService struct {
-	repo repo.TrackerRepository
+	repo     repo.TrackerRepository
+	goalRepo repo.GoalRepository
+	tagRepo  repo.TagRepository
 }
 
 // NewTrackerService creates tracking service.
-func NewTrackerService(repo repo.TrackerRepository) TrackerService {
+func NewTrackerService(repo repo.TrackerRepository, goalRepo repo.GoalRepository, tagRepo repo.TagRepository) TrackerService {
 	return &trackerService{
-		repo: repo,
+		repo:     repo,
+		goalRepo: goalRepo,
+		tagRepo:  tagRepo,
 	}
 }
 
@@ -45,12 +73,18 @@ func (srv *trackerService) GetMainStats(ctx context.Context, userID int64) (mode
 		return models.MainStats{}, fmt.Errorf("main stats: invalid userID")
 	}
 
+	now := time.Now().UTC()
+	goals, err := goalProgress(ctx, srv.goalRepo, userID, now)
+	if err != nil {
+		return models.MainStats{}, err
+	}
+
 	last, ok, err := srv.repo.GetLastTrackedActiveActivity(ctx, userID)
 	if err != nil {
 		return models.MainStats{}, err
 	}
 	if !ok {
-		return models.MainStats{}, nil
+		return models.MainStats{Goals: goals}, nil
 	}
 
 	total, err := srv.repo.GetTodayDurationByActivity(ctx, userID, last.ID)
@@ -68,7 +102,17 @@ func (srv *trackerService) GetMainStats(ctx context.Context, userID int64) (mode
 		return models.MainStats{}, err
 	}
 
-	streak := calcStreakDays(days, time.Now().UTC())
+	streak := calcStreakDays(days, now)
+	streakByGoal := false
+	if goal, ok := streakGoal(goals, last.ID); ok {
+		today := dayStart(now, time.UTC)
+		totals, err := srv.goalRepo.GetDailyTotals(ctx, userID, []int64{last.ID}, today.AddDate(0, 0, -goalStreakLookbackDays), today.AddDate(0, 0, 1), time.UTC)
+		if err != nil {
+			return models.MainStats{}, err
+		}
+		streak = calcGoalStreakDays(totals[last.ID], goal, now)
+		streakByGoal = true
+	}
 	currentName := last.Name
 	if strings.TrimSpace(last.Emoji) != "" {
 		currentName = last.Emoji + " " + last.Name
@@ -79,6 +123,8 @@ func (srv *trackerService) GetMainStats(ctx context.Context, userID int64) (mode
 		TodayTracked:        total,
 		TodaySessions:       todayTrackedActivities,
 		StreakDays:          streak,
+		StreakByGoal:        streakByGoal,
+		Goals:               goals,
 	}, nil
 }
 
@@ -89,6 +135,33 @@ func (srv *trackerService) CreateActivity(ctx context.Context, userID int64, nam
 	return srv.repo.Create(ctx, userID, name, emoji)
 }
 
+// RenameActivity changes activity name; names stay unique per user case-insensitively.
+func (srv *trackerService) RenameActivity(ctx context.Context, userID, activityID int64, name string) error {
+	return srv.repo.Rename(ctx, userID, activityID, strings.TrimSpace(name))
+}
+
+// SetActivityEmoji changes activity emoji; empty string removes it.
+func (srv *trackerService) SetActivityEmoji(ctx context.Context, userID, activityID int64, emoji string) error {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "-" {
+		emoji = ""
+	}
+	if utf8.RuneCountInString(emoji) > maxEmojiRunes {
+		return models.ErrInvalidEmoji
+	}
+	return srv.repo.SetEmoji(ctx, userID, activityID, emoji)
+}
+
+// MoveActivity moves activity one position up (delta < 0) or down (delta > 0).
+func (srv *trackerService) MoveActivity(ctx context.Context, userID, activityID int64, delta int) error {
+	return srv.repo.Move(ctx, userID, activityID, delta)
+}
+
+// MergeActivities moves all history of fromID into toID and removes fromID.
+func (srv *trackerService) MergeActivities(ctx context.Context, userID, fromID, toID int64) (int64, error) {
+	return srv.repo.Merge(ctx, userID, fromID, toID)
+}
+
 // ListActivities returns active activities with selected flags.
 func (srv *trackerService) ListActivities(ctx context.Context, userID int64) ([]models.TrackActivityItem, error) {
 	activities, err := srv.repo.ListActive(ctx, userID)
@@ -125,11 +198,47 @@ func (srv *trackerService) ToggleSelectedActivity(ctx context.Context, userID, a
 	return srv.repo.ToggleSelectedActive(ctx, userID, activityID)
 }
 
-// DeleteSelectedActivities removes currently selected activities.
-func (srv *trackerService) DeleteSelectedActivities(ctx context.Context, userID int64) (int64, error) {
+// DeleteSelectedActivities moves currently selected activities to trash.
+// Returned time identifies the deletion for UndoDeleteActivities.
+func (srv *trackerService) DeleteSelectedActivities(ctx context.Context, userID int64) (int64, time.Time, error) {
 	return srv.repo.DeleteSelected(ctx, userID)
 }
 
+// UndoDeleteActivities restores activities of one deletion while undo window is open.
+func (srv *trackerService) UndoDeleteActivities(ctx context.Context, userID int64, deletedAt time.Time) (int64, error) {
+	if time.Since(deletedAt) > DeleteUndoWindow {
+		return 0, models.ErrUndoExpired
+	}
+	return srv.repo.UndoDelete(ctx, userID, deletedAt)
+}
+
+// ListTrashedActivities returns soft-deleted activities, newest first.
+func (srv *trackerService) ListTrashedActivities(ctx context.Context, userID int64) ([]models.TrackActivityItem, error) {
+	activities, err := srv.repo.ListTrashed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.TrackActivityItem, 0, len(activities))
+	for _, a := range activities {
+		items = append(items, models.TrackActivityItem{
+			ID:    a.ID,
+			Name:  a.Name,
+			Emoji: a.Emoji,
+		})
+	}
+	return items, nil
+}
+
+// RestoreTrashedActivity moves one activity from trash back to active.
+func (srv *trackerService) RestoreTrashedActivity(ctx context.Context, userID, activityID int64) error {
+	return srv.repo.RestoreTrashed(ctx, userID, activityID)
+}
+
+// PurgeActivity permanently removes archived or trashed activity with all its sessions.
+func (srv *trackerService) PurgeActivity(ctx context.Context, userID, activityID int64) (int64, error) {
+	return srv.repo.PurgeWithHistory(ctx, userID, activityID)
+}
+
 // ListSelectedActivities returns only selected active activities.
 func (srv *trackerService) ListSelectedActivities(ctx context.Context, userID int64) ([]models.TrackActivityItem, error) {
 	items, err := srv.ListActivities(ctx, userID)
@@ -180,7 +289,10 @@ func (srv *trackerService) DeleteArchivedForever(ctx context.Context, userID, ac
 }
 
 // GetTodayReport aggregates today's tracked durations and sessions.
-func (srv *trackerService) GetTodayReport(ctx context.Context, userID int64) (models.ReportTodayStats, error) {
+func (srv *trackerService) GetTodayReport(ctx context.Context, userID int64) (_ models.ReportTodayStats, err error) {
+	ctx, span := tracing.Start(ctx, "TrackerService.GetTodayReport", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	total, sessions, err := srv.repo.GetTodayStats(ctx, userID)
 	if err != nil {
 		return models.ReportTodayStats{}, err
@@ -202,10 +314,26 @@ func (srv *trackerService) GetTodayReport(ctx context.Context, userID int64) (mo
 		})
 	}
 
+	goals, err := goalProgress(ctx, srv.goalRepo, userID, time.Now().UTC())
+	if err != nil {
+		return models.ReportTodayStats{}, err
+	}
+
+	ids := make([]int64, 0, len(acts))
+	for _, a := range acts {
+		ids = append(ids, a.ID)
+	}
+	pomodoros, err := srv.todayPomodoros(ctx, userID, ids)
+	if err != nil {
+		return models.ReportTodayStats{}, err
+	}
+
 	return models.ReportTodayStats{
 		TotalTracked:  total,
 		TotalSessions: sessions,
 		TopActivities: top,
+		Goals:         goals,
+		Pomodoros:     pomodoros,
 	}, nil
 }
 
@@ -225,6 +353,13 @@ func (srv *trackerService) GetTodayReportBySelected(ctx context.Context, userID
 		selected[id] = struct{}{}
 	}
 
+	filteredGoals := make([]models.GoalProgress, 0, len(report.Goals))
+	for _, g := range report.Goals {
+		if _, ok := selected[g.Goal.ActivityID]; ok {
+			filteredGoals = append(filteredGoals, g)
+		}
+	}
+
 	filtered := make([]models.ActivityDurationStat, 0, len(report.TopActivities))
 	var total time.Duration
 	var sessions int
@@ -237,15 +372,39 @@ func (srv *trackerService) GetTodayReportBySelected(ctx context.Context, userID
 		sessions += item.Sessions
 	}
 
+	pomodoros, err := srv.todayPomodoros(ctx, userID, selectedIDs)
+	if err != nil {
+		return models.ReportTodayStats{}, err
+	}
+
 	return models.ReportTodayStats{
 		TotalTracked:  total,
 		TotalSessions: sessions,
 		TopActivities: filtered,
+		Goals:         filteredGoals,
+		Pomodoros:     pomodoros,
 	}, nil
 }
 
+// todayPomodoros counts focus blocks of given activities completed since UTC midnight.
+func (srv *trackerService) todayPomodoros(ctx context.Context, userID int64, activityIDs []int64) (int, error) {
+	dayStart := time.Now().UTC().Truncate(24 * time.Hour)
+	_, counts, err := srv.repo.GetPomodoroDailyCounts(ctx, userID, dayStart, dayStart.AddDate(0, 0, 1), activityIDs)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total, nil
+}
+
 // GetPeriodReport aggregates report for date range and optional activity filter.
-func (srv *trackerService) GetPeriodReport(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) (models.ReportPeriodStats, error) {
+func (srv *trackerService) GetPeriodReport(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) (_ models.ReportPeriodStats, err error) {
+	ctx, span := tracing.Start(ctx, "TrackerService.GetPeriodReport", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	acts, durs, cnts, total, sessions, err := srv.repo.GetPeriodActivities(ctx, userID, from, to, activityIDs)
 	if err != nil {
 		return models.ReportPeriodStats{}, err
@@ -273,6 +432,39 @@ func (srv *trackerService) GetPeriodReport(ctx context.Context, userID int64, fr
 		})
 	}
 
+	// Bounds are local midnights of the user, so goal days and weeks are counted in their zone.
+	completion, err := goalCompletion(ctx, srv.goalRepo, userID, from, to, time.Now(), from.Location())
+	if err != nil {
+		return models.ReportPeriodStats{}, err
+	}
+	wanted := make(map[int64]struct{}, len(activityIDs))
+	for _, id := range activityIDs {
+		wanted[id] = struct{}{}
+	}
+	goals := make([]models.GoalCompletion, 0, len(completion))
+	for _, c := range completion {
+		if _, ok := wanted[c.Goal.ActivityID]; ok {
+			goals = append(goals, c)
+		}
+	}
+
+	tags, err := srv.getPeriodTagStats(ctx, userID, from, to, activityIDs)
+	if err != nil {
+		return models.ReportPeriodStats{}, err
+	}
+
+	days, dayCounts, err := srv.repo.GetPomodoroDailyCounts(ctx, userID, from, to, activityIDs)
+	if err != nil {
+		return models.ReportPeriodStats{}, err
+	}
+	pomodoros := make([]models.DayCountStat, 0, len(days))
+	for i := range days {
+		pomodoros = append(pomodoros, models.DayCountStat{
+			Day:   days[i],
+			Count: dayCounts[i],
+		})
+	}
+
 	return models.ReportPeriodStats{
 		From:          from,
 		To:            to,
@@ -280,9 +472,32 @@ func (srv *trackerService) GetPeriodReport(ctx context.Context, userID int64, fr
 		TotalSessions: sessions,
 		Activities:    items,
 		Monthly:       monthly,
+		Goals:         goals,
+		Tags:          tags,
+		Pomodoros:     pomodoros,
 	}, nil
 }
 
+// getPeriodTagStats groups period totals by tag and fills tag display paths.
+func (srv *trackerService) getPeriodTagStats(ctx context.Context, userID int64, from, to time.Time, activityIDs []int64) ([]models.TagDurationStat, error) {
+	stats, err := srv.tagRepo.GetPeriodTagTotals(ctx, userID, from, to, activityIDs)
+	if err != nil || len(stats) == 0 {
+		return nil, err
+	}
+	tags, err := srv.tagRepo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[int64]string, len(tags))
+	for _, t := range withTagPaths(tags) {
+		paths[t.ID] = t.Path
+	}
+	for i := range stats {
+		stats[i].Path = paths[stats[i].TagID]
+	}
+	return stats, nil
+}
+
 // GetMonthDailyTotals returns daily totals for given month.
 func (srv *trackerService) GetMonthDailyTotals(ctx context.Context, userID int64, month time.Time, activityIDs []int64) (map[int]time.Duration, error) {
 	return srv.repo.GetMonthDailyTotals(ctx, userID, month, activityIDs)
@@ -293,6 +508,16 @@ func (srv *trackerService) GetPeriodBuckets(ctx context.Context, userID int64, f
 	return srv.repo.GetPeriodBuckets(ctx, userID, from, to, activityIDs, granularity)
 }
 
+// streakGoal returns the daily goal of activity marked to drive the streak.
+func streakGoal(goals []models.GoalProgress, activityID int64) (models.Goal, bool) {
+	for _, g := range goals {
+		if g.Goal.ActivityID == activityID && g.Goal.Period == models.GoalPeriodDay && g.Goal.UseForStreak {
+			return g.Goal, true
+		}
+	}
+	return models.Goal{}, false
+}
+
 func calcStreakDays(days []time.Time, now time.Time) int {
 	if len(days) == 0 {
 		return 0
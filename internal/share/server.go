@@ -0,0 +1,180 @@
+// Package share serves read-only report pages behind signed links.
+package share
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+	"tracker-bot/internal/models"
+	"tracker-bot/internal/reportcard"
+	"tracker-bot/internal/service"
+	"tracker-bot/internal/utils/httpserver"
+	"tracker-bot/pkg/textbuilder"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Server renders shared period reports as HTML (/r/{token}) and PNG (/r/{token}/card.png).
+type Server struct {
+	server   *httpserver.Server
+	shares   service.ShareService
+	tracksvc service.TrackerService
+}
+
+// NewServer creates share HTTP server listening on addr.
+func NewServer(addr string, shares service.ShareService, tracksvc service.TrackerService) *Server {
+	s := &Server{shares: shares, tracksvc: tracksvc}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /r/{token}", s.handleReport)
+	mux.HandleFunc("GET /r/{token}/card.png", s.handleCard)
+	s.server = httpserver.New("share", addr, mux)
+	return s
+}
+
+// Start serves shared report pages in background.
+func (s *Server) Start() error {
+	return s.server.Start()
+}
+
+// Shutdown stops serving links, letting in-flight pages finish.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// handleReport renders shared report page.
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	stats, ok := s.load(w, r, token)
+	if !ok {
+		return
+	}
+
+	view := reportView{
+		Range:    fmt.Sprintf("%s — %s", stats.From.Format("Jan 2, 2006"), stats.To.Format("Jan 2, 2006")),
+		Total:    textbuilder.Duration(stats.TotalTracked),
+		Sessions: stats.TotalSessions,
+		CardURL:  "/r/" + token + "/card.png",
+	}
+	for _, a := range stats.Activities {
+		view.Activities = append(view.Activities, activityView{
+			Name:     a.Name,
+			Emoji:    a.Emoji,
+			Duration: textbuilder.Duration(a.Duration),
+			Sessions: a.Sessions,
+			Percent:  percent(a.Duration, stats.TotalTracked),
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	if err := reportPage.Execute(w, view); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("share: render page failed")
+	}
+}
+
+// handleCard renders shared report as PNG card.
+func (s *Server) handleCard(w http.ResponseWriter, r *http.Request) {
+	stats, ok := s.load(w, r, r.PathValue("token"))
+	if !ok {
+		return
+	}
+	png, err := reportcard.Render(stats, "Period report")
+	if err != nil {
+		log.Error().Err(err).Msg("share: render card failed")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "private, no-store")
+	_, _ = w.Write(png)
+}
+
+// load resolves token and builds report; writes error response on failure.
+func (s *Server) load(w http.ResponseWriter, r *http.Request, token string) (models.ReportPeriodStats, bool) {
+	share, err := s.shares.Resolve(r.Context(), token)
+	switch {
+	case errors.Is(err, models.ErrShareNotFound):
+		http.Error(w, "link not found", http.StatusNotFound)
+		return models.ReportPeriodStats{}, false
+	case errors.Is(err, models.ErrShareExpired):
+		http.Error(w, "link expired or revoked", http.StatusGone)
+		return models.ReportPeriodStats{}, false
+	case err != nil:
+		log.Error().Err(err).Msg("share: resolve failed")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return models.ReportPeriodStats{}, false
+	}
+
+	stats, err := s.tracksvc.GetPeriodReport(r.Context(), share.UserID, share.From, share.To.AddDate(0, 0, 1), share.ActivityIDs)
+	if err != nil {
+		log.Error().Err(err).Int64("share_id", share.ID).Msg("share: build report failed")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return models.ReportPeriodStats{}, false
+	}
+	stats.From, stats.To = share.From, share.To
+	return stats, true
+}
+
+type reportView struct {
+	Range      string
+	Total      string
+	Sessions   int
+	CardURL    string
+	Activities []activityView
+}
+
+type activityView struct {
+	Name     string
+	Emoji    string
+	Duration string
+	Sessions int
+	Percent  int
+}
+
+func percent(part, total time.Duration) int {
+	if total <= 0 {
+		return 0
+	}
+	return int(part * 100 / total)
+}
+
+var reportPage = template.Must(template.New("report").Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Period report · {{.Range}}</title>
+<style>
+body{margin:0;background:#1e222b;color:#f2f4f8;font:16px/1.4 system-ui,sans-serif}
+main{max-width:640px;margin:0 auto;padding:32px 20px}
+h1{margin:0 0 4px;font-size:28px}
+.muted{color:#9aa3b2}
+.row{margin:18px 0}
+.bar{height:10px;background:#2f3542;border-radius:5px;overflow:hidden;margin-top:6px}
+.bar span{display:block;height:100%;background:#4f9dff}
+a{color:#4f9dff}
+</style>
+</head>
+<body>
+<main>
+<h1>Period report</h1>
+<div class="muted">{{.Range}}</div>
+<p><strong>Total {{.Total}}</strong> · {{.Sessions}} sessions</p>
+{{range .Activities}}
+<div class="row">
+<div>{{if .Emoji}}{{.Emoji}} {{end}}{{.Name}} <span class="muted">— {{.Duration}} · {{.Percent}}% · {{.Sessions}} sessions</span></div>
+<div class="bar"><span style="width:{{.Percent}}%"></span></div>
+</div>
+{{else}}
+<p class="muted">No sessions for this period.</p>
+{{end}}
+<p class="muted"><a href="{{.CardURL}}">Image card</a> · read-only view</p>
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,74 @@
+// Package httpserver runs the bot's own HTTP servers (admin, share links, webhook) in background.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// bindWait is how long Start waits for a bind error (busy port, bad certificate)
+// before it assumes the server is listening.
+const bindWait = 100 * time.Millisecond
+
+// Server is http.Server started in background; name prefixes its errors and logs.
+type Server struct {
+	name   string
+	server *http.Server
+}
+
+// New creates server for handler listening on addr.
+func New(name, addr string, handler http.Handler) *Server {
+	return &Server{
+		name: name,
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Start listens in background and surfaces bind errors.
+func (s *Server) Start() error {
+	return s.start(s.server.ListenAndServe)
+}
+
+// StartTLS is Start over HTTPS with certificate and key files.
+func (s *Server) StartTLS(certFile, keyFile string) error {
+	return s.start(func() error {
+		return s.server.ListenAndServeTLS(certFile, keyFile)
+	})
+}
+
+// Shutdown stops accepting requests and waits for in-flight handlers.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("%s shutdown: %w", s.name, err)
+	}
+	return nil
+}
+
+func (s *Server) start(serve func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		if err := serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err, ok := <-errCh:
+		if ok {
+			return fmt.Errorf("%s listen: %w", s.name, err)
+		}
+	case <-time.After(bindWait):
+	}
+	log.Info().Str("addr", s.server.Addr).Msg(s.name + " server started")
+	return nil
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Setup configures global zerolog logger level and output format.
+func Setup(level, format string) error {
+	lvl, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil {
+		return fmt.Errorf("parse log level: %w", err)
+	}
+	if lvl == zerolog.NoLevel {
+		lvl = zerolog.InfoLevel
+	}
+
+	var out io.Writer
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		out = os.Stderr
+	case FormatConsole:
+		out = zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.DateTime}
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+
+	zerolog.SetGlobalLevel(lvl)
+	log.Logger = zerolog.New(out).With().Timestamp().Logger()
+	// Contexts without request logger (scheduler, jobs) log through global one.
+	zerolog.DefaultContextLogger = &log.Logger
+	return nil
+}
@@ -3,6 +3,7 @@ package pgclient
 import (
 	"context"
 	"fmt"
+	"tracker-bot/internal/tracing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog/log"
@@ -15,7 +16,13 @@ type Client struct {
 
 // New creates and pings PostgreSQL connection pool.
 func New(ctx context.Context, dsn string) (*Client, error) {
-	db, err := pgxpool.New(ctx, dsn)
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error parse database config %w", err)
+	}
+	cfg.ConnConfig.Tracer = tracing.QueryTracer{}
+
+	db, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("error get database driver %w", err)
 	}
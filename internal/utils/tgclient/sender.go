@@ -0,0 +1,279 @@
+package tgclient
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultGlobalRate is Telegram's documented bulk limit (messages per second).
+	DefaultGlobalRate = 30
+	// DefaultChatRate is Telegram's per-chat limit (messages per second).
+	DefaultChatRate = 1
+
+	// maxRetries bounds 429 retries for one request.
+	maxRetries = 3
+	// maxScheduledWait is how long a scheduled message may wait for a slot before it is dropped.
+	maxScheduledWait = 30 * time.Second
+	// chatStateLimit triggers cleanup of idle per-chat limiter entries.
+	chatStateLimit = 10000
+)
+
+// ErrDropped is returned when a scheduled message could not get a send slot in time.
+var ErrDropped = errors.New("telegram send dropped")
+
+// Priority orders waiting messages; interactive replies go before scheduled ones.
+type Priority int
+
+const (
+	PriorityInteractive Priority = iota
+	PriorityScheduled
+)
+
+// SenderStats is a snapshot of sender counters.
+type SenderStats struct {
+	Sent        uint64
+	Failed      uint64
+	Retried     uint64
+	Dropped     uint64
+	Throttled   uint64
+	ErrorsByAPI map[int]uint64
+}
+
+// Sender wraps Telegram client with global and per-chat token buckets,
+// 429 retry_after handling and interactive-first priority.
+// It implements BotAPI, so handlers use it in place of the raw client.
+type Sender struct {
+	api *tgbotapi.BotAPI
+
+	mu          sync.Mutex
+	tokens      float64
+	burst       float64
+	rate        float64
+	chatGap     time.Duration
+	last        time.Time
+	pausedUntil time.Time
+	chatNext    map[int64]time.Time
+
+	interactiveWaiting atomic.Int64
+
+	sent      atomic.Uint64
+	failed    atomic.Uint64
+	retried   atomic.Uint64
+	dropped   atomic.Uint64
+	throttled atomic.Uint64
+	apiErrMu  sync.Mutex
+	apiErrors map[int]uint64
+}
+
+// NewSender creates rate-limited sender; non-positive rates fall back to defaults.
+func NewSender(api *tgbotapi.BotAPI, globalRate, chatRate float64) *Sender {
+	if globalRate <= 0 {
+		globalRate = DefaultGlobalRate
+	}
+	if chatRate <= 0 {
+		chatRate = DefaultChatRate
+	}
+	return &Sender{
+		api:       api,
+		tokens:    globalRate,
+		burst:     globalRate,
+		rate:      globalRate,
+		chatGap:   time.Duration(float64(time.Second) / chatRate),
+		last:      time.Now(),
+		chatNext:  make(map[int64]time.Time),
+		apiErrors: make(map[int]uint64),
+	}
+}
+
+// Send sends interactive message or edit.
+func (s *Sender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return s.SendWithPriority(PriorityInteractive, c)
+}
+
+// SendScheduled sends background message (prompts, digests); it yields to interactive traffic
+// and is dropped if no slot is available within maxScheduledWait.
+func (s *Sender) SendScheduled(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return s.SendWithPriority(PriorityScheduled, c)
+}
+
+// SendWithPriority waits for rate limit slot and sends message, retrying on 429.
+func (s *Sender) SendWithPriority(p Priority, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	chatID := chatIDOf(c)
+	for attempt := 0; ; attempt++ {
+		if !s.acquire(p, chatID) {
+			s.dropped.Add(1)
+			log.Warn().Int64("chat_id", chatID).Msg("telegram sender: scheduled message dropped")
+			return tgbotapi.Message{}, ErrDropped
+		}
+		msg, err := s.api.Send(c)
+		if err == nil {
+			s.sent.Add(1)
+			return msg, nil
+		}
+		if retryAfter, ok := s.retryAfter(err); ok && attempt < maxRetries {
+			s.retried.Add(1)
+			s.pause(retryAfter)
+			continue
+		}
+		s.failed.Add(1)
+		log.Warn().Err(err).Int64("chat_id", chatID).Msg("telegram sender: send failed")
+		return msg, err
+	}
+}
+
+// Request performs API call that returns no message (callback answers, deletes).
+// It shares the global bucket but not the per-chat one.
+func (s *Sender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	for attempt := 0; ; attempt++ {
+		s.acquire(PriorityInteractive, 0)
+		resp, err := s.api.Request(c)
+		if err == nil {
+			s.sent.Add(1)
+			return resp, nil
+		}
+		if retryAfter, ok := s.retryAfter(err); ok && attempt < maxRetries {
+			s.retried.Add(1)
+			s.pause(retryAfter)
+			continue
+		}
+		s.failed.Add(1)
+		return resp, err
+	}
+}
+
+// GetUpdatesChan proxies long polling to the wrapped client.
+func (s *Sender) GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
+	return s.api.GetUpdatesChan(config)
+}
+
+// StopReceivingUpdates proxies long polling stop to the wrapped client.
+func (s *Sender) StopReceivingUpdates() {
+	s.api.StopReceivingUpdates()
+}
+
+// Stats returns current counters.
+func (s *Sender) Stats() SenderStats {
+	s.apiErrMu.Lock()
+	byCode := make(map[int]uint64, len(s.apiErrors))
+	for code, n := range s.apiErrors {
+		byCode[code] = n
+	}
+	s.apiErrMu.Unlock()
+	return SenderStats{
+		Sent:        s.sent.Load(),
+		Failed:      s.failed.Load(),
+		Retried:     s.retried.Load(),
+		Dropped:     s.dropped.Load(),
+		Throttled:   s.throttled.Load(),
+		ErrorsByAPI: byCode,
+	}
+}
+
+// acquire blocks until both buckets allow sending; false means scheduled message timed out.
+func (s *Sender) acquire(p Priority, chatID int64) bool {
+	if p == PriorityInteractive {
+		s.interactiveWaiting.Add(1)
+		defer s.interactiveWaiting.Add(-1)
+	}
+	deadline := time.Now().Add(maxScheduledWait)
+	waited := false
+
+	for {
+		now := time.Now()
+		if p == PriorityScheduled && now.After(deadline) {
+			return false
+		}
+
+		wait := s.reserve(p, chatID, now)
+		if wait == 0 {
+			if waited {
+				s.throttled.Add(1)
+			}
+			return true
+		}
+		waited = true
+		time.Sleep(wait)
+	}
+}
+
+// reserve takes a token when available and returns zero, otherwise returns time to wait.
+func (s *Sender) reserve(p Priority, chatID int64, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Before(s.pausedUntil) {
+		return s.pausedUntil.Sub(now)
+	}
+	// Scheduled traffic steps aside while interactive replies are waiting.
+	if p == PriorityScheduled && s.interactiveWaiting.Load() > 0 {
+		return 10 * time.Millisecond
+	}
+
+	s.tokens = min(s.burst, s.tokens+now.Sub(s.last).Seconds()*s.rate)
+	s.last = now
+	if s.tokens < 1 {
+		return time.Duration((1 - s.tokens) / s.rate * float64(time.Second))
+	}
+	if chatID != 0 {
+		if next, ok := s.chatNext[chatID]; ok && now.Before(next) {
+			return next.Sub(now)
+		}
+		s.chatNext[chatID] = now.Add(s.chatGap)
+		if len(s.chatNext) > chatStateLimit {
+			for id, next := range s.chatNext {
+				if next.Before(now) {
+					delete(s.chatNext, id)
+				}
+			}
+		}
+	}
+	s.tokens--
+	return 0
+}
+
+// pause stops all sending for d; Telegram's retry_after applies to the whole bot.
+func (s *Sender) pause(d time.Duration) {
+	s.mu.Lock()
+	if until := time.Now().Add(d); until.After(s.pausedUntil) {
+		s.pausedUntil = until
+	}
+	s.mu.Unlock()
+}
+
+// retryAfter extracts retry_after from 429 response and counts API errors by code.
+func (s *Sender) retryAfter(err error) (time.Duration, bool) {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return 0, false
+	}
+	s.apiErrMu.Lock()
+	s.apiErrors[apiErr.Code]++
+	s.apiErrMu.Unlock()
+	if apiErr.Code != 429 {
+		return 0, false
+	}
+	if apiErr.RetryAfter <= 0 {
+		return time.Second, true
+	}
+	return time.Duration(apiErr.RetryAfter) * time.Second, true
+}
+
+// chatIDOf returns target chat for new messages; edits and other calls are not chat-limited.
+func chatIDOf(c tgbotapi.Chattable) int64 {
+	switch v := c.(type) {
+	case tgbotapi.MessageConfig:
+		return v.ChatID
+	case tgbotapi.PhotoConfig:
+		return v.ChatID
+	case tgbotapi.DocumentConfig:
+		return v.ChatID
+	default:
+		return 0
+	}
+}
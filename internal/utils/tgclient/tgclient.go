@@ -1,7 +1,9 @@
 package tgclient
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -9,6 +11,7 @@ import (
 // BotAPI is the minimal Telegram bot interface used by handlers.
 type BotAPI interface {
 	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	SendScheduled(c tgbotapi.Chattable) (tgbotapi.Message, error)
 	GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
 	StopReceivingUpdates()
 	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
@@ -27,3 +30,20 @@ func New(token string) (*tgbotapi.BotAPI, error) {
 
 	return api, nil
 }
+
+// IsUnreachable reports errors meaning the chat can no longer receive messages:
+// 403 "bot was blocked by the user"/"user is deactivated" and 400 "chat not found".
+func IsUnreachable(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case 403:
+		return true
+	case 400:
+		return strings.Contains(strings.ToLower(apiErr.Message), "chat not found")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,197 @@
+package tgclient
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"tracker-bot/internal/utils/httpserver"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// secretTokenHeader carries secret_token passed to setWebhook.
+	secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+	// maxUpdateBody bounds request body; Telegram updates are far smaller.
+	maxUpdateBody = 1 << 20
+	// updateBuffer is how many accepted updates may wait for the dispatcher.
+	updateBuffer = 100
+	// seenUpdatesLimit is how many recent update ids are kept for deduplication.
+	seenUpdatesLimit = 10000
+)
+
+// WebhookConfig describes webhook HTTP server.
+type WebhookConfig struct {
+	// URL is public address registered with setWebhook; empty skips registration
+	// (local runs where updates are POSTed by hand).
+	URL    string
+	Listen string
+	Path   string
+	// SecretToken is required: requests without matching header are rejected.
+	SecretToken string
+	// TLSCert and TLSKey enable HTTPS; leave empty behind a TLS-terminating reverse proxy.
+	TLSCert        string
+	TLSKey         string
+	AllowedUpdates []string
+}
+
+// WebhookServer receives updates over HTTP and exposes them as UpdatesChannel.
+type WebhookServer struct {
+	cfg     WebhookConfig
+	server  *httpserver.Server
+	updates chan tgbotapi.Update
+
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+	seen     map[int]struct{}
+	seenIDs  []int
+	seenNext int
+}
+
+// NewWebhookServer creates webhook server; call Start to listen.
+func NewWebhookServer(cfg WebhookConfig) *WebhookServer {
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+	s := &WebhookServer{
+		cfg:     cfg,
+		updates: make(chan tgbotapi.Update, updateBuffer),
+		seen:    make(map[int]struct{}, seenUpdatesLimit),
+		seenIDs: make([]int, 0, seenUpdatesLimit),
+	}
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, s)
+	s.server = httpserver.New("webhook", cfg.Listen, mux)
+	return s
+}
+
+// Updates returns channel closed after Shutdown drained accepted updates.
+func (s *WebhookServer) Updates() tgbotapi.UpdatesChannel {
+	return s.updates
+}
+
+// Register points Telegram to cfg.URL with secret token; no-op when URL is empty.
+func (s *WebhookServer) Register(api *tgbotapi.BotAPI) error {
+	if s.cfg.URL == "" {
+		log.Warn().Msg("webhook: public URL is empty, setWebhook skipped")
+		return nil
+	}
+	params := tgbotapi.Params{"url": s.cfg.URL}
+	params["secret_token"] = s.cfg.SecretToken
+	if err := params.AddInterface("allowed_updates", s.cfg.AllowedUpdates); err != nil {
+		return fmt.Errorf("set webhook: %w", err)
+	}
+	if _, err := api.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("set webhook: %w", err)
+	}
+	log.Info().Str("url", s.cfg.URL).Msg("webhook registered")
+	return nil
+}
+
+// Start listens in background; TLS is used when certificate and key are set.
+func (s *WebhookServer) Start() error {
+	if s.cfg.TLSCert != "" && s.cfg.TLSKey != "" {
+		return s.server.StartTLS(s.cfg.TLSCert, s.cfg.TLSKey)
+	}
+	return s.server.Start()
+}
+
+// Shutdown stops accepting requests, waits for in-flight handlers and closes Updates.
+// The dispatcher keeps reading until the channel is drained.
+func (s *WebhookServer) Shutdown(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.inflight.Wait()
+	close(s.updates)
+
+	return err
+}
+
+// ServeHTTP verifies secret token, decodes update and queues it once per update_id.
+func (s *WebhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Empty secret rejects everything rather than accepting unauthenticated updates.
+	got := r.Header.Get(secretTokenHeader)
+	if s.cfg.SecretToken == "" || subtle.ConstantTimeCompare([]byte(got), []byte(s.cfg.SecretToken)) != 1 {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxUpdateBody))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+	var update tgbotapi.Update
+	if err := json.Unmarshal(body, &update); err != nil {
+		http.Error(w, "invalid update", http.StatusBadRequest)
+		return
+	}
+
+	if !s.begin(update.UpdateID) {
+		// Duplicate delivery or shutting down; duplicates are acknowledged so Telegram stops retrying.
+		if s.isClosed() {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	defer s.inflight.Done()
+
+	select {
+	case s.updates <- update:
+		w.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+		// Not queued: forget id so Telegram's retry is accepted.
+		s.forget(update.UpdateID)
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}
+}
+
+// begin marks update as seen and registers in-flight handler; false for duplicates or after shutdown.
+func (s *WebhookServer) begin(updateID int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if _, ok := s.seen[updateID]; ok {
+		return false
+	}
+
+	if len(s.seenIDs) < seenUpdatesLimit {
+		s.seenIDs = append(s.seenIDs, updateID)
+	} else {
+		delete(s.seen, s.seenIDs[s.seenNext])
+		s.seenIDs[s.seenNext] = updateID
+		s.seenNext = (s.seenNext + 1) % seenUpdatesLimit
+	}
+	s.seen[updateID] = struct{}{}
+	s.inflight.Add(1)
+	return true
+}
+
+func (s *WebhookServer) forget(updateID int) {
+	s.mu.Lock()
+	delete(s.seen, updateID)
+	s.mu.Unlock()
+}
+
+func (s *WebhookServer) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
@@ -1,14 +1,24 @@
 package tgctx
 
-import "context"
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
 
 // MsgContext is Telegram update context for message-based handlers.
 type MsgContext struct {
+	// Ctx carries update span and Log (see zerolog.Ctx) to services and repositories.
 	Ctx context.Context
+	// Log is request-scoped logger with update id, user ids and callback data.
+	Log zerolog.Logger
 
+	// ChatID is where replies go; in groups it differs from sender's UserID.
 	ChatID   int64
 	UserID   int64
 	DBUserID int64
+	// IsGroup marks group and supergroup chats, where only team features are served.
+	IsGroup bool
 
 	Text      string
 	MessageID int
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_activity_goals_user;
+DROP INDEX IF EXISTS uq_activity_goals_activity_period;
+DROP TABLE IF EXISTS activity_goals;
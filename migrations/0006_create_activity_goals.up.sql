@@ -0,0 +1,23 @@
+-- Per-activity targets: "at least N minutes" or "at most N minutes" per day/week.
+CREATE TABLE IF NOT EXISTS activity_goals (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_id    BIGINT      NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
+    period         TEXT        NOT NULL,
+    kind           TEXT        NOT NULL DEFAULT 'min',
+    target_min     INTEGER     NOT NULL,
+    use_for_streak BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT chk_goal_period CHECK (period IN ('day', 'week')),
+    CONSTRAINT chk_goal_kind CHECK (kind IN ('min', 'max')),
+    CONSTRAINT chk_goal_target_positive CHECK (target_min > 0 AND target_min <= 10080)
+);
+
+-- One goal per activity and period.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_activity_goals_activity_period
+    ON activity_goals (activity_id, period);
+
+CREATE INDEX IF NOT EXISTS idx_activity_goals_user
+    ON activity_goals (user_id);
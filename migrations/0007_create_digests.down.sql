@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS digest_deliveries;
+DROP INDEX IF EXISTS idx_digest_enabled;
+DROP TABLE IF EXISTS user_digest_settings;
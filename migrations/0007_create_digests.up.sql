@@ -0,0 +1,26 @@
+-- Opt-in automatic report digests. send_at_min is local time of day in minutes.
+CREATE TABLE IF NOT EXISTS user_digest_settings (
+    user_id     BIGINT      PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    daily       BOOLEAN     NOT NULL DEFAULT FALSE,
+    weekly      BOOLEAN     NOT NULL DEFAULT FALSE,
+    monthly     BOOLEAN     NOT NULL DEFAULT FALSE,
+    send_at_min INTEGER     NOT NULL DEFAULT 1260,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT chk_digest_send_at CHECK (send_at_min >= 0 AND send_at_min < 1440)
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_enabled
+    ON user_digest_settings (user_id)
+    WHERE daily OR weekly OR monthly;
+
+-- One row per delivered digest: the primary key guarantees one digest per period.
+CREATE TABLE IF NOT EXISTS digest_deliveries (
+    user_id      BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind         TEXT        NOT NULL,
+    period_start DATE        NOT NULL,
+    sent_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (user_id, kind, period_start),
+    CONSTRAINT chk_digest_kind CHECK (kind IN ('daily', 'weekly', 'monthly'))
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_activity_tags_tag;
+DROP TABLE IF EXISTS activity_tags;
+DROP INDEX IF EXISTS idx_tags_parent;
+DROP INDEX IF EXISTS uq_tags_user_lower_name;
+DROP TABLE IF EXISTS tags;
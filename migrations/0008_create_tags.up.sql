@@ -0,0 +1,27 @@
+-- Tags (categories) group activities; parent_id builds a hierarchy like Learning -> Go.
+CREATE TABLE IF NOT EXISTS tags (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id  BIGINT      NULL REFERENCES tags(id) ON DELETE SET NULL,
+    name       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT chk_tag_not_own_parent CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_tags_user_lower_name
+    ON tags (user_id, lower(name));
+
+CREATE INDEX IF NOT EXISTS idx_tags_parent
+    ON tags (parent_id);
+
+-- Many-to-many assignment of tags to activities.
+CREATE TABLE IF NOT EXISTS activity_tags (
+    activity_id BIGINT      NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
+    tag_id      BIGINT      NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (activity_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_tags_tag
+    ON activity_tags (tag_id);
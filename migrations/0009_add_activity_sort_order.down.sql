@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_activities_user_sort;
+ALTER TABLE activities DROP COLUMN IF EXISTS sort_order;
@@ -0,0 +1,15 @@
+-- Manual ordering of activities in keyboards; lower values go first.
+ALTER TABLE activities
+    ADD COLUMN IF NOT EXISTS sort_order INTEGER NOT NULL DEFAULT 0;
+
+-- Keep current alphabetical order for existing activities.
+UPDATE activities a
+SET sort_order = o.pos
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY lower(name), id) AS pos
+    FROM activities
+) o
+WHERE a.id = o.id;
+
+CREATE INDEX IF NOT EXISTS idx_activities_user_sort
+    ON activities (user_id, sort_order);
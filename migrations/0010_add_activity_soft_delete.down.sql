@@ -0,0 +1,12 @@
+-- Without deleted_at trash cannot be told apart, so trashed activities are purged with
+-- their sessions, as emptying the trash would do; this also frees their names for the
+-- full unique index.
+DELETE FROM activity_sessions
+WHERE activity_id IN (SELECT id FROM activities WHERE deleted_at IS NOT NULL);
+DELETE FROM activities WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_activities_user_deleted;
+DROP INDEX IF EXISTS uq_activities_user_lower_name;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_activities_user_lower_name
+    ON activities (user_id, lower(name));
+ALTER TABLE activities DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,14 @@
+-- Soft delete: deleted activities stay in trash (with their sessions) until purged.
+-- Deleted activities are also archived, so every "is_archived = FALSE" query skips them.
+ALTER TABLE activities
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+
+-- Names only need to be unique among activities that are not in trash.
+DROP INDEX IF EXISTS uq_activities_user_lower_name;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_activities_user_lower_name
+    ON activities (user_id, lower(name))
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_activities_user_deleted
+    ON activities (user_id, deleted_at)
+    WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_prompt_deliveries_claimed_at;
+DROP TABLE IF EXISTS prompt_deliveries;
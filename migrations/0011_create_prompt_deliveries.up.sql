@@ -0,0 +1,12 @@
+-- One row per scheduled prompt; the primary key makes delivery at-most-once
+-- even when several bot instances run the timer scheduler.
+CREATE TABLE IF NOT EXISTS prompt_deliveries (
+    user_id      BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    claimed_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at      TIMESTAMPTZ NULL,
+    PRIMARY KEY (user_id, scheduled_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_prompt_deliveries_claimed_at
+    ON prompt_deliveries (claimed_at);
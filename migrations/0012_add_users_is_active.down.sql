@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_inactive;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deactivated_at,
+    DROP COLUMN IF EXISTS is_active;
@@ -0,0 +1,8 @@
+-- Users who blocked the bot are kept but marked inactive until their next /start.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_inactive
+    ON users (id)
+    WHERE is_active = FALSE;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_broadcast_recipients_queue;
+DROP TABLE IF EXISTS broadcast_recipients;
+DROP INDEX IF EXISTS idx_broadcasts_due;
+DROP TABLE IF EXISTS broadcasts;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS plan;
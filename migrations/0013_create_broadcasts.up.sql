@@ -0,0 +1,47 @@
+-- Subscription plan used for broadcast targeting; everyone starts on free.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS plan TEXT NOT NULL DEFAULT 'free';
+
+-- Announcement composed by an operator. Empty segment fields mean "everyone".
+CREATE TABLE IF NOT EXISTS broadcasts (
+    id                  BIGSERIAL PRIMARY KEY,
+    created_by          BIGINT      NOT NULL,
+    text                TEXT        NOT NULL DEFAULT '',
+    photo_file_id       TEXT        NULL,
+    buttons             JSONB       NOT NULL DEFAULT '[]',
+    segment_language    TEXT        NULL,
+    segment_plan        TEXT        NULL,
+    segment_active_days INTEGER     NOT NULL DEFAULT 0,
+    status              TEXT        NOT NULL DEFAULT 'draft',
+    scheduled_at        TIMESTAMPTZ NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at          TIMESTAMPTZ NULL,
+    finished_at         TIMESTAMPTZ NULL,
+
+    CONSTRAINT chk_broadcast_status CHECK (status IN ('draft', 'scheduled', 'sending', 'done', 'cancelled')),
+    CONSTRAINT chk_broadcast_active_days CHECK (segment_active_days >= 0),
+    CONSTRAINT chk_broadcast_content CHECK (text <> '' OR photo_file_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_broadcasts_due
+    ON broadcasts (scheduled_at)
+    WHERE status IN ('scheduled', 'sending');
+
+-- Recipients are materialized when sending starts; the queue survives restarts.
+CREATE TABLE IF NOT EXISTS broadcast_recipients (
+    broadcast_id BIGINT      NOT NULL REFERENCES broadcasts(id) ON DELETE CASCADE,
+    user_id      BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chat_id      BIGINT      NOT NULL,
+    status       TEXT        NOT NULL DEFAULT 'pending',
+    attempts     INTEGER     NOT NULL DEFAULT 0,
+    error        TEXT        NULL,
+    claimed_at   TIMESTAMPTZ NULL,
+    sent_at      TIMESTAMPTZ NULL,
+    PRIMARY KEY (broadcast_id, user_id),
+
+    CONSTRAINT chk_broadcast_recipient_status CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'blocked'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_broadcast_recipients_queue
+    ON broadcast_recipients (broadcast_id, user_id)
+    WHERE status IN ('pending', 'sending');
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_report_shares_user_active;
+DROP TABLE IF EXISTS report_shares;
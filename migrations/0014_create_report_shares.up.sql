@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS report_shares (
+    id           BIGSERIAL   PRIMARY KEY,
+    user_id      BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_date    DATE        NOT NULL,
+    to_date      DATE        NOT NULL,
+    activity_ids BIGINT[]    NOT NULL DEFAULT '{}',
+    expires_at   TIMESTAMPTZ NOT NULL,
+    revoked_at   TIMESTAMPTZ NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT chk_report_shares_range CHECK (to_date >= from_date)
+);
+
+-- Profile screen lists links that still work.
+CREATE INDEX IF NOT EXISTS idx_report_shares_user_active
+    ON report_shares (user_id, expires_at)
+    WHERE revoked_at IS NULL;
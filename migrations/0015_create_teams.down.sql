@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS team_shared_activities;
+DROP INDEX IF EXISTS idx_team_members_user;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,42 @@
+-- Team is a Telegram group the bot was added to; settings are changed by group admins.
+CREATE TABLE IF NOT EXISTS teams (
+    id               BIGSERIAL   PRIMARY KEY,
+    chat_id          BIGINT      NOT NULL,
+    title            TEXT        NOT NULL DEFAULT '',
+    is_active        BOOLEAN     NOT NULL DEFAULT TRUE,
+    digest_enabled   BOOLEAN     NOT NULL DEFAULT TRUE,
+    digest_weekday   SMALLINT    NOT NULL DEFAULT 1,
+    digest_hour      SMALLINT    NOT NULL DEFAULT 9,
+    leaderboard_days SMALLINT    NOT NULL DEFAULT 7,
+    last_digest_at   TIMESTAMPTZ NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_teams_chat UNIQUE (chat_id),
+    CONSTRAINT chk_teams_digest_weekday CHECK (digest_weekday BETWEEN 0 AND 6),
+    CONSTRAINT chk_teams_digest_hour CHECK (digest_hour BETWEEN 0 AND 23),
+    CONSTRAINT chk_teams_leaderboard_days CHECK (leaderboard_days > 0)
+);
+
+-- Members share nothing until they opt in.
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id      BIGINT      NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id      BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    display_name TEXT        NOT NULL DEFAULT '',
+    sharing      BOOLEAN     NOT NULL DEFAULT FALSE,
+    joined_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_user
+    ON team_members (user_id);
+
+-- Activities whose totals member shares with team.
+CREATE TABLE IF NOT EXISTS team_shared_activities (
+    team_id     BIGINT NOT NULL,
+    user_id     BIGINT NOT NULL,
+    activity_id BIGINT NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (team_id, user_id, activity_id),
+    FOREIGN KEY (team_id, user_id) REFERENCES team_members (team_id, user_id) ON DELETE CASCADE
+);
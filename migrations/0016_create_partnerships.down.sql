@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS partner_nudges;
+DROP TABLE IF EXISTS partner_shared_activities;
+DROP INDEX IF EXISTS idx_partnerships_user_b;
+DROP TABLE IF EXISTS partnerships;
+DROP TABLE IF EXISTS partner_invites;
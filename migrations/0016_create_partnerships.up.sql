@@ -0,0 +1,41 @@
+-- One-time invite sent as deep link t.me/<bot>?start=invite_<token>.
+CREATE TABLE IF NOT EXISTS partner_invites (
+    token      TEXT        PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Pair is stored once with user_a < user_b.
+CREATE TABLE IF NOT EXISTS partnerships (
+    id         BIGSERIAL   PRIMARY KEY,
+    user_a     BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_b     BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT chk_partnerships_order CHECK (user_a < user_b),
+    CONSTRAINT uq_partnerships_pair UNIQUE (user_a, user_b)
+);
+
+CREATE INDEX IF NOT EXISTS idx_partnerships_user_b
+    ON partnerships (user_b);
+
+-- Activities whose goal progress user shows to partner; nothing by default.
+CREATE TABLE IF NOT EXISTS partner_shared_activities (
+    partnership_id BIGINT NOT NULL REFERENCES partnerships(id) ON DELETE CASCADE,
+    user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_id    BIGINT NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (partnership_id, user_id, activity_id)
+);
+
+-- At most one nudge per sender per day.
+CREATE TABLE IF NOT EXISTS partner_nudges (
+    partnership_id BIGINT      NOT NULL REFERENCES partnerships(id) ON DELETE CASCADE,
+    from_user_id   BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day            DATE        NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (partnership_id, from_user_id, day)
+);
@@ -0,0 +1,11 @@
+ALTER TABLE user_timer_settings
+    DROP CONSTRAINT IF EXISTS chk_quiet_hours,
+    DROP COLUMN IF EXISTS quiet_to_min,
+    DROP COLUMN IF EXISTS quiet_from_min;
+
+DROP INDEX IF EXISTS idx_users_start_payload;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS start_payload_at,
+    DROP COLUMN IF EXISTS start_payload,
+    DROP COLUMN IF EXISTS onboarding_step;
@@ -0,0 +1,21 @@
+-- First-run wizard progress; 'done' once finished or skipped.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS onboarding_step TEXT NOT NULL DEFAULT 'language',
+    ADD COLUMN IF NOT EXISTS start_payload TEXT NULL,
+    ADD COLUMN IF NOT EXISTS start_payload_at TIMESTAMPTZ NULL;
+
+-- Users who joined before the wizard existed are already set up.
+UPDATE users SET onboarding_step = 'done';
+
+CREATE INDEX IF NOT EXISTS idx_users_start_payload
+    ON users (start_payload)
+    WHERE start_payload IS NOT NULL;
+
+-- Quiet hours are local minutes after midnight; window may wrap past midnight.
+ALTER TABLE user_timer_settings
+    ADD COLUMN IF NOT EXISTS quiet_from_min SMALLINT NULL,
+    ADD COLUMN IF NOT EXISTS quiet_to_min SMALLINT NULL,
+    ADD CONSTRAINT chk_quiet_hours CHECK (
+        (quiet_from_min IS NULL AND quiet_to_min IS NULL)
+        OR (quiet_from_min BETWEEN 0 AND 1439 AND quiet_to_min BETWEEN 0 AND 1439 AND quiet_from_min <> quiet_to_min)
+    );
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pomodoros_phase_ends_at;
+DROP TABLE IF EXISTS pomodoros;
+DROP TABLE IF EXISTS pomodoro_settings;
@@ -0,0 +1,35 @@
+-- Pomodoro lengths in minutes; defaults are the classic 25/5/15, long break every 4 focus blocks.
+CREATE TABLE IF NOT EXISTS pomodoro_settings (
+    user_id          BIGINT   PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    focus_min        SMALLINT NOT NULL DEFAULT 25,
+    short_break_min  SMALLINT NOT NULL DEFAULT 5,
+    long_break_min   SMALLINT NOT NULL DEFAULT 15,
+    long_break_every SMALLINT NOT NULL DEFAULT 4,
+
+    CONSTRAINT chk_pomodoro_settings CHECK (
+        focus_min BETWEEN 1 AND 180
+        AND short_break_min BETWEEN 1 AND 60
+        AND long_break_min BETWEEN 1 AND 120
+        AND long_break_every BETWEEN 2 AND 12
+    )
+);
+
+-- Running pomodoro, at most one per user; row is removed when user stops it.
+-- message_id is the countdown message edited in place, shown_min its last rendered minutes left.
+CREATE TABLE IF NOT EXISTS pomodoros (
+    user_id          BIGINT      PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    activity_id      BIGINT      NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
+    phase            TEXT        NOT NULL,
+    cycle            INTEGER     NOT NULL DEFAULT 1,
+    phase_started_at TIMESTAMPTZ NOT NULL,
+    phase_ends_at    TIMESTAMPTZ NOT NULL,
+    message_id       INTEGER     NOT NULL DEFAULT 0,
+    shown_min        INTEGER     NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT chk_pomodoros_phase CHECK (phase IN ('focus', 'short_break', 'long_break')),
+    CONSTRAINT chk_pomodoros_cycle CHECK (cycle >= 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pomodoros_phase_ends_at
+    ON pomodoros (phase_ends_at);
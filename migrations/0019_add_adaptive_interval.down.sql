@@ -0,0 +1,9 @@
+COMMENT ON COLUMN activity_sessions.planned_min IS NULL;
+
+ALTER TABLE user_timer_settings
+    DROP CONSTRAINT IF EXISTS chk_adaptive_bounds,
+    DROP COLUMN IF EXISTS same_answer_streak,
+    DROP COLUMN IF EXISTS last_answer_activity_id,
+    DROP COLUMN IF EXISTS max_interval_min,
+    DROP COLUMN IF EXISTS min_interval_min,
+    DROP COLUMN IF EXISTS adaptive;
@@ -0,0 +1,14 @@
+-- Adaptive timer moves interval_min between the bounds: longer while answers repeat
+-- one activity, shorter while they keep switching.
+ALTER TABLE user_timer_settings
+    ADD COLUMN IF NOT EXISTS adaptive BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS min_interval_min SMALLINT NOT NULL DEFAULT 10,
+    ADD COLUMN IF NOT EXISTS max_interval_min SMALLINT NOT NULL DEFAULT 60,
+    ADD COLUMN IF NOT EXISTS last_answer_activity_id BIGINT NULL REFERENCES activities(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS same_answer_streak INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_adaptive_bounds CHECK (
+        min_interval_min >= 1 AND min_interval_min <= max_interval_min AND max_interval_min <= 360
+    );
+
+COMMENT ON COLUMN activity_sessions.planned_min IS
+    'Interval covered by the session: for prompt answers the interval in effect for that prompt, which varies with adaptive timer.';
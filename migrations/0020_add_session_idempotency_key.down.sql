@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uniq_sessions_idempotency_key;
+
+ALTER TABLE activity_sessions
+    DROP COLUMN IF EXISTS idempotency_key;
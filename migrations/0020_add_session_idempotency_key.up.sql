@@ -0,0 +1,8 @@
+-- Sessions created from retried deliveries (e.g. chosen inline results) carry the
+-- delivery id, so the same delivery is saved once.
+ALTER TABLE activity_sessions
+    ADD COLUMN IF NOT EXISTS idempotency_key TEXT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_sessions_idempotency_key
+    ON activity_sessions (user_id, idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
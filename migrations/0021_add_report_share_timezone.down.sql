@@ -0,0 +1,2 @@
+ALTER TABLE report_shares
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,4 @@
+-- from_date/to_date are days in the zone the report was built in; links created
+-- before this column existed were built over UTC days.
+ALTER TABLE report_shares
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,5 @@
+DELETE FROM pomodoros WHERE phase = 'waiting';
+
+ALTER TABLE pomodoros
+    DROP CONSTRAINT IF EXISTS chk_pomodoros_phase,
+    ADD CONSTRAINT chk_pomodoros_phase CHECK (phase IN ('focus', 'short_break', 'long_break'));
@@ -0,0 +1,5 @@
+-- After a break the pomodoro waits for the user to start the next focus block,
+-- so a forgotten pomodoro stops recording focus.
+ALTER TABLE pomodoros
+    DROP CONSTRAINT IF EXISTS chk_pomodoros_phase,
+    ADD CONSTRAINT chk_pomodoros_phase CHECK (phase IN ('focus', 'short_break', 'long_break', 'waiting'));
@@ -0,0 +1,211 @@
+// Package cmdparse tokenizes and parses arguments of bot text commands.
+package cmdparse
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"tracker-bot/pkg/timeparse"
+	"unicode"
+)
+
+// ErrSyntax is returned when command arguments do not match expected form.
+var ErrSyntax = errors.New("invalid command syntax")
+
+const (
+	// maxLogDuration bounds one manually logged session.
+	maxLogDuration = 24 * time.Hour
+	// maxPeriodWords is the longest period phrase tried in /report ("last 7 days").
+	maxPeriodWords = 3
+	// maxDurationWords is the longest duration phrase tried in /log ("1 h 30 m").
+	maxDurationWords = 4
+)
+
+// LogArgs is parsed "/log <activity> <45m|14:00-15:30> [day]".
+type LogArgs struct {
+	Activity string
+	Start    time.Time
+	End      time.Time
+}
+
+// ReportArgs is parsed "/report [period] [name,name]".
+type ReportArgs struct {
+	From       time.Time
+	To         time.Time
+	Activities []string
+}
+
+// Tokenize splits args by whitespace; double quotes keep spaces inside one token.
+func Tokenize(s string) []string {
+	var (
+		out    []string
+		cur    strings.Builder
+		quoted bool
+		has    bool
+	)
+	flush := func() {
+		if has {
+			out = append(out, cur.String())
+		}
+		cur.Reset()
+		has = false
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			has = true
+		case unicode.IsSpace(r) && !quoted:
+			flush()
+		default:
+			cur.WriteRune(r)
+			has = true
+		}
+	}
+	flush()
+	return out
+}
+
+// ParseLog parses "<activity> <duration|HH:MM-HH:MM> [day]" where day is any
+// timeparse day expression. Duration entries end at now (or at the same clock time of given day).
+// Sessions ending in the future are rejected.
+//
+// Activity names may contain numbers ("Project 2 45m"), so the amount is the first
+// token span after the name that is followed by nothing or by a valid day.
+func ParseLog(args string, p timeparse.Parser) (LogArgs, error) {
+	tokens := Tokenize(args)
+	now := p.Now()
+
+	for i := 1; i < len(tokens); i++ {
+		for j := i + 1; j <= min(len(tokens), i+maxDurationWords); j++ {
+			day := p.Today()
+			if rest := strings.Join(tokens[j:], " "); rest != "" {
+				d, err := p.Day(rest)
+				if err != nil {
+					continue
+				}
+				day = d
+			}
+
+			out := LogArgs{Activity: strings.Join(tokens[:i], " ")}
+			amount := strings.Join(tokens[i:j], " ")
+			if start, end, err := p.ClockRange(amount, day); err == nil && j == i+1 {
+				out.Start, out.End = start, end
+			} else if d, err := p.Duration(amount); err == nil {
+				if d > maxLogDuration {
+					return LogArgs{}, ErrSyntax
+				}
+				out.End = time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), day.Location())
+				out.Start = out.End.Add(-d)
+			} else {
+				continue
+			}
+
+			if out.Start.After(now) || out.End.After(now) {
+				return LogArgs{}, ErrSyntax
+			}
+			return out, nil
+		}
+	}
+	return LogArgs{}, ErrSyntax
+}
+
+// ParseReport parses "[period] [name,name]" where period is any timeparse range
+// ("2026-09-01..2026-09-30", "last week", "3d"); missing period means last 7 days.
+func ParseReport(args string, p timeparse.Parser) (ReportArgs, error) {
+	tokens := Tokenize(args)
+	today := p.Today()
+	out := ReportArgs{From: today.AddDate(0, 0, -6), To: today}
+
+	// Longest leading phrase that is a period wins, so "last week Go" keeps "Go".
+	for n := min(len(tokens), maxPeriodWords); n > 0; n-- {
+		from, to, err := p.Range(strings.Join(tokens[:n], " "))
+		if err != nil {
+			continue
+		}
+		out.From, out.To = from, to
+		tokens = tokens[n:]
+		break
+	}
+	if len(tokens) > 0 && strings.Contains(tokens[0], "..") {
+		return ReportArgs{}, ErrSyntax
+	}
+
+	for _, name := range strings.Split(strings.Join(tokens, " "), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			out.Activities = append(out.Activities, name)
+		}
+	}
+	return out, nil
+}
+
+// MatchName returns index of best fuzzy match of query among names.
+// Exact match wins, then prefix, then substring, then small typo distance;
+// false when nothing matches or the best match is ambiguous.
+func MatchName(query string, names []string) (int, bool) {
+	q := normalize(query)
+	if q == "" {
+		return -1, false
+	}
+
+	best, bestScore, tie := -1, -1, false
+	for i, name := range names {
+		score := matchScore(q, normalize(name))
+		if score < 0 {
+			continue
+		}
+		switch {
+		case best < 0 || score < bestScore:
+			best, bestScore, tie = i, score, false
+		case score == bestScore:
+			tie = true
+		}
+	}
+	if best < 0 || tie {
+		return -1, false
+	}
+	return best, true
+}
+
+// matchScore ranks candidate; lower is better, -1 means no match.
+func matchScore(q, name string) int {
+	switch {
+	case name == q:
+		return 0
+	case strings.HasPrefix(name, q):
+		return 1
+	case strings.Contains(name, q):
+		return 2
+	}
+	limit := max(1, len([]rune(q))/3)
+	if d := levenshtein(q, name); d <= limit {
+		return 3 + d
+	}
+	return -1
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// levenshtein returns edit distance between a and b in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,167 @@
+package cmdparse
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+	"tracker-bot/pkg/timeparse"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "", want: nil},
+		{in: "  Go   45m ", want: []string{"Go", "45m"}},
+		{in: `"Side project" 1h yesterday`, want: []string{"Side project", "1h", "yesterday"}},
+		{in: `"" 1h`, want: []string{"", "1h"}},
+		{in: "Go\t45m\nyesterday", want: []string{"Go", "45m", "yesterday"}},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseLog(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone is not available: %v", err)
+	}
+	at := func(m time.Month, d, h, min int) time.Time {
+		return time.Date(2026, m, d, h, min, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		locale  string
+		in      string
+		want    LogArgs
+		wantErr bool
+	}{
+		{name: "duration ends now", now: at(10, 16, 14, 30), locale: "en", in: "Go 45m",
+			want: LogArgs{Activity: "Go", Start: at(10, 16, 13, 45), End: at(10, 16, 14, 30)}},
+		{name: "bare minutes", now: at(10, 16, 14, 30), locale: "en", in: "Go 90",
+			want: LogArgs{Activity: "Go", Start: at(10, 16, 13, 0), End: at(10, 16, 14, 30)}},
+		{name: "compound duration with space", now: at(10, 16, 14, 30), locale: "en", in: "Go 1h 30m",
+			want: LogArgs{Activity: "Go", Start: at(10, 16, 13, 0), End: at(10, 16, 14, 30)}},
+		{name: "number in activity name", now: at(10, 16, 14, 30), locale: "en", in: "Project 2 45m",
+			want: LogArgs{Activity: "Project 2", Start: at(10, 16, 13, 45), End: at(10, 16, 14, 30)}},
+		{name: "number in activity name with day", now: at(10, 16, 14, 30), locale: "en", in: "Project 2 45m yesterday",
+			want: LogArgs{Activity: "Project 2", Start: at(10, 15, 13, 45), End: at(10, 15, 14, 30)}},
+		{name: "multi-word activity", now: at(10, 16, 14, 30), locale: "en", in: "Read 2 books 30m",
+			want: LogArgs{Activity: "Read 2 books", Start: at(10, 16, 14, 0), End: at(10, 16, 14, 30)}},
+		{name: "quoted activity", now: at(10, 16, 14, 30), locale: "en", in: `"Side project" 1h`,
+			want: LogArgs{Activity: "Side project", Start: at(10, 16, 13, 30), End: at(10, 16, 14, 30)}},
+		{name: "duration on weekday", now: at(10, 16, 14, 30), locale: "en", in: "Go 45m last friday",
+			want: LogArgs{Activity: "Go", Start: at(10, 9, 13, 45), End: at(10, 9, 14, 30)}},
+		{name: "clock range today", now: at(10, 16, 14, 30), locale: "en", in: "English 12:00-13:30",
+			want: LogArgs{Activity: "English", Start: at(10, 16, 12, 0), End: at(10, 16, 13, 30)}},
+		{name: "clock range yesterday", now: at(10, 16, 14, 30), locale: "en", in: "English 14:00-15:30 yesterday",
+			want: LogArgs{Activity: "English", Start: at(10, 15, 14, 0), End: at(10, 15, 15, 30)}},
+		{name: "clock range crossing midnight into today", now: at(10, 16, 14, 30), locale: "en", in: "Go 23:00-01:00 yesterday",
+			want: LogArgs{Activity: "Go", Start: at(10, 15, 23, 0), End: at(10, 16, 1, 0)}},
+		{name: "localized day", now: at(10, 16, 14, 30), locale: "ru", in: "Английский 1ч вчера",
+			want: LogArgs{Activity: "Английский", Start: at(10, 15, 13, 30), End: at(10, 15, 14, 30)}},
+		// Berlin switches to summer time on 2026-03-29: same clock time, not same elapsed time.
+		{name: "duration on day before DST switch", now: at(3, 29, 14, 30), locale: "en", in: "Go 30m yesterday",
+			want: LogArgs{Activity: "Go", Start: at(3, 28, 14, 0), End: at(3, 28, 14, 30)}},
+		{name: "range ends in future", now: at(10, 16, 14, 30), locale: "en", in: "English 14:00-15:30", wantErr: true},
+		{name: "range crossing midnight ends tomorrow", now: at(10, 16, 23, 30), locale: "en", in: "Go 22:00-01:00", wantErr: true},
+		{name: "range starts in future", now: at(10, 16, 14, 30), locale: "en", in: "Go 16:00-17:00", wantErr: true},
+		{name: "too long", now: at(10, 16, 14, 30), locale: "en", in: "Go 25h", wantErr: true},
+		{name: "missing activity", now: at(10, 16, 14, 30), locale: "en", in: "45m", wantErr: true},
+		{name: "missing duration", now: at(10, 16, 14, 30), locale: "en", in: "Go yesterday", wantErr: true},
+		{name: "unknown day", now: at(10, 16, 14, 30), locale: "en", in: "Go 45m someday", wantErr: true},
+		{name: "empty", now: at(10, 16, 14, 30), locale: "en", in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLog(tt.in, timeparse.New(tt.now, tt.locale))
+			if tt.wantErr {
+				if !errors.Is(err, ErrSyntax) {
+					t.Fatalf("ParseLog(%q) = %+v, %v; want ErrSyntax", tt.in, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLog(%q): %v", tt.in, err)
+			}
+			if got.Activity != tt.want.Activity || !got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End) {
+				t.Errorf("ParseLog(%q) = %q %v..%v, want %q %v..%v", tt.in,
+					got.Activity, got.Start, got.End, tt.want.Activity, tt.want.Start, tt.want.End)
+			}
+		})
+	}
+}
+
+func TestParseReport(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2026, 9, 30, 15, 0, 0, 0, time.UTC)
+	day := func(m time.Month, d int) time.Time {
+		return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		in         string
+		from, to   time.Time
+		activities []string
+		wantErr    bool
+	}{
+		{in: "", from: day(9, 24), to: day(9, 30)},
+		{in: "Go", from: day(9, 24), to: day(9, 30), activities: []string{"Go"}},
+		{in: "last week Go, English", from: day(9, 21), to: day(9, 27), activities: []string{"Go", "English"}},
+		{in: "last 7 days", from: day(9, 24), to: day(9, 30)},
+		{in: "3d Go", from: day(9, 28), to: day(9, 30), activities: []string{"Go"}},
+		{in: "since monday", from: day(9, 28), to: day(9, 30)},
+		{in: "2026-09-01..2026-09-15 Go,English", from: day(9, 1), to: day(9, 15), activities: []string{"Go", "English"}},
+		{in: `"Side project"`, from: day(9, 24), to: day(9, 30), activities: []string{"Side project"}},
+		{in: "2026-09-15..2026-09-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseReport(tt.in, timeparse.New(now, "en"))
+			if tt.wantErr {
+				if !errors.Is(err, ErrSyntax) {
+					t.Fatalf("ParseReport(%q) = %+v, %v; want ErrSyntax", tt.in, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReport(%q): %v", tt.in, err)
+			}
+			if !got.From.Equal(tt.from) || !got.To.Equal(tt.to) || !slices.Equal(got.Activities, tt.activities) {
+				t.Errorf("ParseReport(%q) = %v..%v %q, want %v..%v %q", tt.in,
+					got.From, got.To, got.Activities, tt.from, tt.to, tt.activities)
+			}
+		})
+	}
+}
+
+func TestMatchName(t *testing.T) {
+	names := []string{"Go", "English", "English grammar", "Workout", "Reading"}
+	tests := []struct {
+		query string
+		want  int
+		ok    bool
+	}{
+		{query: "go", want: 0, ok: true},
+		{query: "  ENGLISH ", want: 1, ok: true},
+		{query: "work", want: 3, ok: true},
+		{query: "grammar", want: 2, ok: true},
+		{query: "Readng", want: 4, ok: true},
+		{query: "Eng", ok: false},
+		{query: "Swimming", ok: false},
+		{query: "", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := MatchName(tt.query, names)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("MatchName(%q) = %d, %v; want %d, %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 // Package textbuilder provides small text-format helpers for bot UI.
 package textbuilder
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 func strOrDash(s *string) string {
 	if s == nil || *s == "" {
@@ -28,3 +32,20 @@ func escapeMarkdown(s string) string {
 func StrOrDashMD(s *string) string {
 	return escapeMarkdown(strOrDash(s))
 }
+
+// Duration formats duration as "Xh Ym", "Xh" or "Ym".
+func Duration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	switch {
+	case h > 0 && m > 0:
+		return fmt.Sprintf("%dh %dm", h, m)
+	case h > 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dm", m)
+	}
+}
@@ -0,0 +1,123 @@
+package timeparse
+
+import "time"
+
+// Canonical words every locale is mapped to before parsing.
+const (
+	wordToday     = "today"
+	wordYesterday = "yesterday"
+	wordThis      = "this"
+	wordLast      = "last"
+	wordSince     = "since"
+	wordDay       = "day"
+	wordWeek      = "week"
+	wordMonth     = "month"
+	wordYear      = "year"
+	wordHour      = "hour"
+	wordMinute    = "minute"
+)
+
+// lexicons map lower-case locale words to canonical words or weekday names.
+// English is always accepted in addition to user's locale.
+var lexicons = map[string]map[string]string{
+	"en": {
+		"today": wordToday, "yesterday": wordYesterday,
+		"this": wordThis, "current": wordThis, "last": wordLast, "previous": wordLast, "past": wordLast,
+		"since": wordSince, "from": wordSince,
+		"d": wordDay, "day": wordDay, "days": wordDay,
+		"w": wordWeek, "week": wordWeek, "weeks": wordWeek,
+		"month": wordMonth, "months": wordMonth,
+		"y": wordYear, "year": wordYear, "years": wordYear,
+		"h": wordHour, "hr": wordHour, "hrs": wordHour, "hour": wordHour, "hours": wordHour,
+		"m": wordMinute, "min": wordMinute, "mins": wordMinute, "minute": wordMinute, "minutes": wordMinute,
+		"monday": "monday", "mon": "monday", "tuesday": "tuesday", "tue": "tuesday",
+		"wednesday": "wednesday", "wed": "wednesday", "thursday": "thursday", "thu": "thursday",
+		"friday": "friday", "fri": "friday", "saturday": "saturday", "sat": "saturday",
+		"sunday": "sunday", "sun": "sunday",
+	},
+	"ru": {
+		"сегодня": wordToday, "вчера": wordYesterday,
+		"этот": wordThis, "эта": wordThis, "эту": wordThis, "этой": wordThis, "этом": wordThis, "этого": wordThis, "текущий": wordThis,
+		"прошлый": wordLast, "прошлая": wordLast, "прошлую": wordLast, "прошлой": wordLast, "прошлом": wordLast, "прошлого": wordLast,
+		"последние": wordLast, "последних": wordLast,
+		"с": wordSince, "со": wordSince,
+		"д": wordDay, "дн": wordDay, "день": wordDay, "дня": wordDay, "дней": wordDay,
+		"нед": wordWeek, "неделя": wordWeek, "неделю": wordWeek, "недели": wordWeek, "неделе": wordWeek, "недель": wordWeek,
+		"месяц": wordMonth, "месяца": wordMonth, "месяце": wordMonth, "месяцев": wordMonth,
+		"год": wordYear, "года": wordYear, "году": wordYear, "лет": wordYear,
+		"ч": wordHour, "час": wordHour, "часа": wordHour, "часов": wordHour,
+		"м": wordMinute, "мин": wordMinute, "минута": wordMinute, "минуты": wordMinute, "минут": wordMinute,
+		"понедельник": "monday", "понедельника": "monday", "пн": "monday",
+		"вторник": "tuesday", "вторника": "tuesday", "вт": "tuesday",
+		"среда": "wednesday", "среду": "wednesday", "среды": "wednesday", "ср": "wednesday",
+		"четверг": "thursday", "четверга": "thursday", "чт": "thursday",
+		"пятница": "friday", "пятницу": "friday", "пятницы": "friday", "пт": "friday",
+		"суббота": "saturday", "субботу": "saturday", "субботы": "saturday", "сб": "saturday",
+		"воскресенье": "sunday", "воскресенья": "sunday", "вс": "sunday",
+	},
+	"de": {
+		"heute": wordToday, "gestern": wordYesterday,
+		"diese": wordThis, "diesen": wordThis, "dieser": wordThis, "diesem": wordThis, "dieses": wordThis,
+		"letzte": wordLast, "letzten": wordLast, "letzter": wordLast, "letztes": wordLast, "vorige": wordLast, "vorigen": wordLast,
+		"seit": wordSince, "ab": wordSince,
+		"t": wordDay, "tag": wordDay, "tage": wordDay, "tagen": wordDay,
+		"woche": wordWeek, "wochen": wordWeek,
+		"monat": wordMonth, "monats": wordMonth, "monate": wordMonth, "monaten": wordMonth,
+		"jahr": wordYear, "jahres": wordYear, "jahre": wordYear, "jahren": wordYear,
+		"std": wordHour, "stunde": wordHour, "stunden": wordHour,
+		"minute": wordMinute, "minuten": wordMinute,
+		"montag": "monday", "mo": "monday", "dienstag": "tuesday", "di": "tuesday",
+		"mittwoch": "wednesday", "mi": "wednesday", "donnerstag": "thursday", "do": "thursday",
+		"freitag": "friday", "fr": "friday", "samstag": "saturday", "sa": "saturday",
+		"sonntag": "sunday", "so": "sunday",
+	},
+	"uk": {
+		"сьогодні": wordToday, "вчора": wordYesterday, "учора": wordYesterday,
+		"цей": wordThis, "ця": wordThis, "цю": wordThis, "цього": wordThis, "цієї": wordThis, "цьому": wordThis,
+		"минулий": wordLast, "минула": wordLast, "минулу": wordLast, "минулого": wordLast, "минулої": wordLast, "минулому": wordLast,
+		"останні": wordLast, "останніх": wordLast,
+		"з": wordSince, "із": wordSince, "від": wordSince,
+		"д": wordDay, "дн": wordDay, "день": wordDay, "дні": wordDay, "днів": wordDay,
+		"тиж": wordWeek, "тиждень": wordWeek, "тижня": wordWeek, "тижні": wordWeek, "тижнів": wordWeek,
+		"місяць": wordMonth, "місяця": wordMonth, "місяці": wordMonth, "місяців": wordMonth,
+		"рік": wordYear, "року": wordYear, "роки": wordYear, "років": wordYear,
+		"г": wordHour, "год": wordHour, "година": wordHour, "години": wordHour, "годин": wordHour,
+		"хв": wordMinute, "хвилина": wordMinute, "хвилини": wordMinute, "хвилин": wordMinute,
+		"понеділок": "monday", "понеділка": "monday", "пн": "monday",
+		"вівторок": "tuesday", "вівторка": "tuesday", "вт": "tuesday",
+		"середа": "wednesday", "середу": "wednesday", "середи": "wednesday", "ср": "wednesday",
+		"четвер": "thursday", "четверга": "thursday", "чт": "thursday",
+		"пʼятниця": "friday", "п'ятниця": "friday", "пʼятницю": "friday", "п'ятницю": "friday", "пт": "friday",
+		"субота": "saturday", "суботу": "saturday", "суботи": "saturday", "сб": "saturday",
+		"неділя": "sunday", "неділю": "sunday", "неділі": "sunday", "нд": "sunday",
+	},
+	"ar": {
+		"اليوم": wordToday, "امس": wordYesterday, "أمس": wordYesterday, "البارحة": wordYesterday,
+		"هذا": wordThis, "هذه": wordThis, "الحالي": wordThis, "الحالية": wordThis,
+		"الماضي": wordLast, "الماضية": wordLast, "السابق": wordLast, "آخر": wordLast, "اخر": wordLast,
+		"منذ": wordSince, "من": wordSince,
+		"ي": wordDay, "يوم": wordDay, "أيام": wordDay, "ايام": wordDay,
+		"أسبوع": wordWeek, "اسبوع": wordWeek, "الأسبوع": wordWeek, "الاسبوع": wordWeek, "أسابيع": wordWeek,
+		"شهر": wordMonth, "الشهر": wordMonth, "أشهر": wordMonth,
+		"سنة": wordYear, "السنة": wordYear, "عام": wordYear, "العام": wordYear,
+		"س": wordHour, "ساعة": wordHour, "ساعات": wordHour,
+		"د": wordMinute, "دقيقة": wordMinute, "دقائق": wordMinute,
+		"الاثنين": "monday", "الإثنين": "monday", "الثلاثاء": "tuesday", "الأربعاء": "wednesday", "الاربعاء": "wednesday",
+		"الخميس": "thursday", "الجمعة": "friday", "السبت": "saturday", "الأحد": "sunday", "الاحد": "sunday",
+	},
+}
+
+var weekdays = map[string]time.Weekday{
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+}
+
+// weekStarts holds first day of week per locale; Monday when not listed.
+var weekStarts = map[string]time.Weekday{
+	"ar": time.Saturday,
+}
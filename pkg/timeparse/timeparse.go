@@ -0,0 +1,304 @@
+// Package timeparse parses natural-language dates, periods and durations
+// ("yesterday", "last week", "3d", "1h30m", "since monday", "14:00-15:30")
+// relative to user's timezone and locale (ru/en/de/uk/ar).
+package timeparse
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrUnrecognized is returned when text is not a supported expression.
+var ErrUnrecognized = errors.New("unrecognized time expression")
+
+const dateLayout = "2006-01-02"
+
+// amountUnit matches "3d", "1h30m", "45 min", "2 недели" pieces.
+var amountUnit = regexp.MustCompile(`(\d+)\s*([^\d\s]+)`)
+
+// Parser resolves expressions against user's current time and locale.
+type Parser struct {
+	now     time.Time
+	lexicon map[string]string
+	weekday time.Weekday
+}
+
+// New creates parser; now must be in user's location, unknown locale falls back to English.
+func New(now time.Time, locale string) Parser {
+	lexicon := make(map[string]string, len(lexicons["en"])+len(lexicons[locale]))
+	for k, v := range lexicons["en"] {
+		lexicon[k] = v
+	}
+	for k, v := range lexicons[locale] {
+		lexicon[k] = v
+	}
+	weekStart, ok := weekStarts[locale]
+	if !ok {
+		weekStart = time.Monday
+	}
+	return Parser{now: now, lexicon: lexicon, weekday: weekStart}
+}
+
+// Now returns current time in user's location.
+func (p Parser) Now() time.Time {
+	return p.now
+}
+
+// Today returns midnight of current day in user's location.
+func (p Parser) Today() time.Time {
+	return midnight(p.now)
+}
+
+// Range parses a period into inclusive day bounds (midnights in user's location):
+// "today", "yesterday", "this week", "last month", "3d", "last 7 days",
+// "since monday", "2026-09-01", "2026-09-01..2026-09-30".
+func (p Parser) Range(s string) (from, to time.Time, err error) {
+	s = strings.TrimSpace(s)
+	if left, right, ok := strings.Cut(s, ".."); ok {
+		from, err = p.Day(left)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to, err = p.Day(right)
+		if err != nil || from.After(to) {
+			return time.Time{}, time.Time{}, ErrUnrecognized
+		}
+		return from, to, nil
+	}
+
+	words := p.words(s)
+	today := p.Today()
+	if len(words) == 0 {
+		return time.Time{}, time.Time{}, ErrUnrecognized
+	}
+
+	if words[0] == wordSince {
+		from, err = p.Day(strings.Join(rawWords(s)[1:], " "))
+		if err != nil || from.After(today) {
+			return time.Time{}, time.Time{}, ErrUnrecognized
+		}
+		return from, today, nil
+	}
+
+	// "last 7 days" / "7 days" / "7d".
+	if n, unit, ok := p.amount(words); ok {
+		switch unit {
+		case wordDay:
+			return today.AddDate(0, 0, 1-n), today, nil
+		case wordWeek:
+			return today.AddDate(0, 0, 1-7*n), today, nil
+		case wordMonth:
+			return monthsBack(today, n).AddDate(0, 0, 1), today, nil
+		}
+		return time.Time{}, time.Time{}, ErrUnrecognized
+	}
+
+	// Word order differs by language ("last week", "الأسبوع الماضي"), so match as set.
+	last, this := slices.Contains(words, wordLast), slices.Contains(words, wordThis)
+	switch {
+	case slices.Contains(words, wordWeek) && len(words) <= 2:
+		start := p.weekStart(today)
+		if last {
+			return start.AddDate(0, 0, -7), start.AddDate(0, 0, -1), nil
+		}
+		return start, today, nil
+	case slices.Contains(words, wordMonth) && len(words) <= 2:
+		start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		if last {
+			return start.AddDate(0, -1, 0), start.AddDate(0, 0, -1), nil
+		}
+		return start, today, nil
+	case slices.Contains(words, wordYear) && len(words) <= 2:
+		start := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, today.Location())
+		if last {
+			return start.AddDate(-1, 0, 0), start.AddDate(0, 0, -1), nil
+		}
+		return start, today, nil
+	case this && len(words) == 1:
+		return time.Time{}, time.Time{}, ErrUnrecognized
+	}
+
+	day, err := p.Day(s)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return day, day, nil
+}
+
+// Day parses "today", "yesterday", weekday name (latest such day, today included),
+// "last friday" (strictly before today) or "YYYY-MM-DD" into midnight in user's location.
+func (p Parser) Day(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	today := p.Today()
+	if d, err := time.ParseInLocation(dateLayout, s, today.Location()); err == nil {
+		return d, nil
+	}
+
+	words := p.words(s)
+	last := slices.Contains(words, wordLast)
+	if last {
+		words = without(words, wordLast)
+	}
+	if len(words) != 1 {
+		return time.Time{}, ErrUnrecognized
+	}
+	switch words[0] {
+	case wordToday:
+		return today, nil
+	case wordYesterday:
+		return today.AddDate(0, 0, -1), nil
+	}
+	wd, ok := weekdays[words[0]]
+	if !ok {
+		return time.Time{}, ErrUnrecognized
+	}
+	back := (int(today.Weekday()) - int(wd) + 7) % 7
+	if back == 0 && last {
+		back = 7
+	}
+	return today.AddDate(0, 0, -back), nil
+}
+
+// Duration parses "45m", "1h30m", "1h 30m", "2 часа", "90" (bare minutes).
+func (p Parser) Duration(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil {
+		if n <= 0 {
+			return 0, ErrUnrecognized
+		}
+		return time.Duration(n) * time.Minute, nil
+	}
+
+	matches := amountUnit.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return 0, ErrUnrecognized
+	}
+	var total time.Duration
+	pos := 0
+	for _, m := range matches {
+		if strings.TrimSpace(s[pos:m[0]]) != "" {
+			return 0, ErrUnrecognized
+		}
+		pos = m[1]
+		n, err := strconv.Atoi(s[m[2]:m[3]])
+		if err != nil {
+			return 0, ErrUnrecognized
+		}
+		switch p.lexicon[s[m[4]:m[5]]] {
+		case wordHour:
+			total += time.Duration(n) * time.Hour
+		case wordMinute:
+			total += time.Duration(n) * time.Minute
+		default:
+			return 0, ErrUnrecognized
+		}
+	}
+	if strings.TrimSpace(s[pos:]) != "" || total <= 0 {
+		return 0, ErrUnrecognized
+	}
+	return total, nil
+}
+
+// ClockRange parses "14:00-15:30" on given day; end before start means the range crosses midnight.
+func (p Parser) ClockRange(s string, day time.Time) (start, end time.Time, err error) {
+	left, right, ok := strings.Cut(strings.ReplaceAll(strings.TrimSpace(s), "–", "-"), "-")
+	if !ok {
+		return time.Time{}, time.Time{}, ErrUnrecognized
+	}
+	from, ok := parseClock(left)
+	if !ok {
+		return time.Time{}, time.Time{}, ErrUnrecognized
+	}
+	to, ok := parseClock(right)
+	if !ok || to == from {
+		return time.Time{}, time.Time{}, ErrUnrecognized
+	}
+	endDay := day
+	if to < from {
+		endDay = day.AddDate(0, 0, 1)
+	}
+	return atClock(day, from), atClock(endDay, to), nil
+}
+
+// words lower-cases text and maps locale words to canonical ones; unknown words stay as is.
+func (p Parser) words(s string) []string {
+	raw := rawWords(s)
+	out := make([]string, 0, len(raw))
+	for _, w := range raw {
+		if c, ok := p.lexicon[w]; ok {
+			w = c
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// amount detects "[last] N unit" and "Nunit" forms.
+func (p Parser) amount(words []string) (int, string, bool) {
+	words = without(words, wordLast)
+	text := strings.Join(words, " ")
+	m := amountUnit.FindStringSubmatch(text)
+	if m == nil || m[0] != text {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n <= 0 {
+		return 0, "", false
+	}
+	unit := m[2]
+	if c, ok := p.lexicon[unit]; ok {
+		unit = c
+	}
+	return n, unit, true
+}
+
+func (p Parser) weekStart(day time.Time) time.Time {
+	back := (int(day.Weekday()) - int(p.weekday) + 7) % 7
+	return day.AddDate(0, 0, -back)
+}
+
+func rawWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+}
+
+func without(words []string, w string) []string {
+	out := make([]string, 0, len(words))
+	for _, x := range words {
+		if x != w {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// atClock returns clock time of day; adding duration to midnight would be off by an hour on DST days.
+func atClock(day time.Time, clock time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, day.Location())
+}
+
+// monthsBack returns day n months earlier, clamped to the end of shorter months
+// (March 31 minus one month is February 28, not March 3).
+func monthsBack(day time.Time, n int) time.Time {
+	first := time.Date(day.Year(), day.Month()-time.Month(n), 1, 0, 0, 0, 0, day.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(day.Day(), last), 0, 0, 0, 0, day.Location())
+}
+
+func parseClock(s string) (time.Duration, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
@@ -0,0 +1,253 @@
+package timeparse
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s is not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestRange(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 15, 0, 0, 0, berlin)
+	}
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		locale   string
+		in       string
+		from, to time.Time
+		wantErr  bool
+	}{
+		{name: "today", now: at(2026, 9, 30), locale: "en", in: "today", from: day(2026, 9, 30), to: day(2026, 9, 30)},
+		{name: "yesterday", now: at(2026, 9, 30), locale: "en", in: "yesterday", from: day(2026, 9, 29), to: day(2026, 9, 29)},
+		{name: "this week from wednesday", now: at(2026, 9, 30), locale: "en", in: "this week", from: day(2026, 9, 28), to: day(2026, 9, 30)},
+		{name: "last week", now: at(2026, 9, 30), locale: "en", in: "last week", from: day(2026, 9, 21), to: day(2026, 9, 27)},
+		{name: "this week on sunday", now: at(2026, 3, 1), locale: "en", in: "this week", from: day(2026, 2, 23), to: day(2026, 3, 1)},
+		{name: "last week across month", now: at(2026, 3, 1), locale: "en", in: "last week", from: day(2026, 2, 16), to: day(2026, 2, 22)},
+		{name: "this week across year", now: at(2026, 1, 1), locale: "en", in: "this week", from: day(2025, 12, 29), to: day(2026, 1, 1)},
+		{name: "this month", now: at(2026, 9, 30), locale: "en", in: "this month", from: day(2026, 9, 1), to: day(2026, 9, 30)},
+		{name: "last month from 31st", now: at(2026, 3, 31), locale: "en", in: "last month", from: day(2026, 2, 1), to: day(2026, 2, 28)},
+		{name: "last month across year", now: at(2026, 1, 1), locale: "en", in: "last month", from: day(2025, 12, 1), to: day(2025, 12, 31)},
+		{name: "last year", now: at(2026, 1, 1), locale: "en", in: "last year", from: day(2025, 1, 1), to: day(2025, 12, 31)},
+		{name: "compact days", now: at(2026, 9, 30), locale: "en", in: "3d", from: day(2026, 9, 28), to: day(2026, 9, 30)},
+		{name: "last 7 days", now: at(2026, 9, 30), locale: "en", in: "last 7 days", from: day(2026, 9, 24), to: day(2026, 9, 30)},
+		{name: "days across month", now: at(2026, 3, 2), locale: "en", in: "5 days", from: day(2026, 2, 26), to: day(2026, 3, 2)},
+		{name: "weeks", now: at(2026, 9, 30), locale: "en", in: "2 weeks", from: day(2026, 9, 17), to: day(2026, 9, 30)},
+		{name: "one month from 31st", now: at(2026, 3, 31), locale: "en", in: "1 month", from: day(2026, 3, 1), to: day(2026, 3, 31)},
+		{name: "one month from 30th", now: at(2026, 3, 30), locale: "en", in: "1 month", from: day(2026, 3, 1), to: day(2026, 3, 30)},
+		{name: "one month mid month", now: at(2026, 1, 15), locale: "en", in: "1 month", from: day(2025, 12, 16), to: day(2026, 1, 15)},
+		{name: "three months", now: at(2026, 5, 31), locale: "en", in: "3 months", from: day(2026, 3, 1), to: day(2026, 5, 31)},
+		{name: "m is minute, not month", now: at(2026, 9, 30), locale: "en", in: "3m", wantErr: true},
+		{name: "since weekday", now: at(2026, 9, 30), locale: "en", in: "since monday", from: day(2026, 9, 28), to: day(2026, 9, 30)},
+		{name: "since future date", now: at(2026, 9, 30), locale: "en", in: "since 2026-10-05", wantErr: true},
+		{name: "explicit range", now: at(2026, 9, 30), locale: "en", in: "2026-09-01..2026-09-15", from: day(2026, 9, 1), to: day(2026, 9, 15)},
+		{name: "reversed range", now: at(2026, 9, 30), locale: "en", in: "2026-09-15..2026-09-01", wantErr: true},
+		{name: "single date", now: at(2026, 9, 30), locale: "en", in: "2026-09-10", from: day(2026, 9, 10), to: day(2026, 9, 10)},
+		{name: "bare this", now: at(2026, 9, 30), locale: "en", in: "this", wantErr: true},
+		{name: "garbage", now: at(2026, 9, 30), locale: "en", in: "soon", wantErr: true},
+		{name: "ru last week", now: at(2026, 9, 30), locale: "ru", in: "прошлая неделя", from: day(2026, 9, 21), to: day(2026, 9, 27)},
+		{name: "ru last days", now: at(2026, 9, 30), locale: "ru", in: "последние 3 дня", from: day(2026, 9, 28), to: day(2026, 9, 30)},
+		{name: "ru since weekday", now: at(2026, 9, 30), locale: "ru", in: "с понедельника", from: day(2026, 9, 28), to: day(2026, 9, 30)},
+		{name: "de last month", now: at(2026, 9, 30), locale: "de", in: "letzten monat", from: day(2026, 8, 1), to: day(2026, 8, 31)},
+		{name: "uk this month", now: at(2026, 9, 30), locale: "uk", in: "цей місяць", from: day(2026, 9, 1), to: day(2026, 9, 30)},
+		// Arabic weeks start on Saturday; 2026-10-16 is a Friday, 2026-10-17 a Saturday.
+		{name: "ar this week on friday", now: at(2026, 10, 16), locale: "ar", in: "هذا الأسبوع", from: day(2026, 10, 10), to: day(2026, 10, 16)},
+		{name: "ar this week on saturday", now: at(2026, 10, 17), locale: "ar", in: "هذا الأسبوع", from: day(2026, 10, 17), to: day(2026, 10, 17)},
+		{name: "ar last week", now: at(2026, 10, 16), locale: "ar", in: "الأسبوع الماضي", from: day(2026, 10, 3), to: day(2026, 10, 9)},
+		{name: "en keeps monday week", now: at(2026, 10, 16), locale: "en", in: "this week", from: day(2026, 10, 12), to: day(2026, 10, 16)},
+		// Berlin switches to summer time on 2026-03-29 and back on 2026-10-25.
+		{name: "days across spring DST", now: at(2026, 3, 31), locale: "en", in: "last 7 days", from: day(2026, 3, 25), to: day(2026, 3, 31)},
+		{name: "last week across autumn DST", now: at(2026, 10, 28), locale: "en", in: "last week", from: day(2026, 10, 19), to: day(2026, 10, 25)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := New(tt.now, tt.locale).Range(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Range(%q) = %v..%v, want error", tt.in, from, to)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Range(%q): %v", tt.in, err)
+			}
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Errorf("Range(%q) = %v..%v, want %v..%v", tt.in, from, to, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestDay(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	day := func(m time.Month, d int) time.Time {
+		return time.Date(2026, m, d, 0, 0, 0, 0, berlin)
+	}
+	// Friday.
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, berlin)
+
+	tests := []struct {
+		locale  string
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{locale: "en", in: "today", want: day(10, 16)},
+		{locale: "en", in: "yesterday", want: day(10, 15)},
+		{locale: "en", in: "friday", want: day(10, 16)},
+		{locale: "en", in: "last friday", want: day(10, 9)},
+		{locale: "en", in: "monday", want: day(10, 12)},
+		{locale: "en", in: "last monday", want: day(10, 12)},
+		{locale: "en", in: "sat", want: day(10, 10)},
+		{locale: "en", in: "2026-10-01", want: day(10, 1)},
+		{locale: "ru", in: "прошлую пятницу", want: day(10, 9)},
+		{locale: "ru", in: "пятницу", want: day(10, 16)},
+		{locale: "de", in: "letzten freitag", want: day(10, 9)},
+		{locale: "uk", in: "вчора", want: day(10, 15)},
+		{locale: "ar", in: "الجمعة", want: day(10, 16)},
+		{locale: "ar", in: "الجمعة الماضية", want: day(10, 9)},
+		{locale: "en", in: "next friday", wantErr: true},
+		{locale: "en", in: "2026-13-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.in, func(t *testing.T) {
+			got, err := New(now, tt.locale).Day(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Day(%q) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Day(%q): %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Day(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		locale  string
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{locale: "en", in: "45m", want: 45 * time.Minute},
+		{locale: "en", in: "90", want: 90 * time.Minute},
+		{locale: "en", in: "2h", want: 2 * time.Hour},
+		{locale: "en", in: "1h30m", want: 90 * time.Minute},
+		{locale: "en", in: "1h 30m", want: 90 * time.Minute},
+		{locale: "en", in: "1 hour 5 minutes", want: 65 * time.Minute},
+		{locale: "en", in: "45 MIN", want: 45 * time.Minute},
+		{locale: "ru", in: "2 часа", want: 2 * time.Hour},
+		{locale: "ru", in: "1ч30м", want: 90 * time.Minute},
+		{locale: "de", in: "1 std 15 minuten", want: 75 * time.Minute},
+		{locale: "uk", in: "2 год", want: 2 * time.Hour},
+		{locale: "ar", in: "30 د", want: 30 * time.Minute},
+		// "год" is an hour in Ukrainian but a year in Russian.
+		{locale: "ru", in: "2 год", wantErr: true},
+		{locale: "en", in: "0", wantErr: true},
+		{locale: "en", in: "-5", wantErr: true},
+		{locale: "en", in: "0m", wantErr: true},
+		{locale: "en", in: "3d", wantErr: true},
+		{locale: "en", in: "m", wantErr: true},
+		{locale: "en", in: "1h30", wantErr: true},
+		{locale: "en", in: "2 45m", wantErr: true},
+		{locale: "en", in: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.in, func(t *testing.T) {
+			got, err := New(now, tt.locale).Duration(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Duration(%q) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Duration(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Duration(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClockRange(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	at := func(m time.Month, d, h, min int) time.Time {
+		return time.Date(2026, m, d, h, min, 0, 0, berlin)
+	}
+	p := New(at(10, 16, 9, 0), "en")
+
+	tests := []struct {
+		name       string
+		in         string
+		day        time.Time
+		start, end time.Time
+		wantErr    bool
+	}{
+		{name: "plain", in: "14:00-15:30", day: at(10, 16, 0, 0), start: at(10, 16, 14, 0), end: at(10, 16, 15, 30)},
+		{name: "en dash", in: "14:00–15:30", day: at(10, 16, 0, 0), start: at(10, 16, 14, 0), end: at(10, 16, 15, 30)},
+		{name: "day with clock time", in: "08:15-09:00", day: at(10, 16, 18, 45), start: at(10, 16, 8, 15), end: at(10, 16, 9, 0)},
+		{name: "crosses midnight", in: "23:00-01:00", day: at(10, 16, 0, 0), start: at(10, 16, 23, 0), end: at(10, 17, 1, 0)},
+		{name: "crosses month end", in: "22:30-00:15", day: at(10, 31, 0, 0), start: at(10, 31, 22, 30), end: at(11, 1, 0, 15)},
+		// Clock times stay clock times on days that are 23 or 25 hours long.
+		{name: "spring DST", in: "01:00-04:00", day: at(3, 29, 0, 0), start: at(3, 29, 1, 0), end: at(3, 29, 4, 0)},
+		{name: "autumn DST", in: "01:00-04:00", day: at(10, 25, 0, 0), start: at(10, 25, 1, 0), end: at(10, 25, 4, 0)},
+		{name: "into spring DST day", in: "22:00-04:00", day: at(3, 28, 0, 0), start: at(3, 28, 22, 0), end: at(3, 29, 4, 0)},
+		{name: "empty range", in: "15:00-15:00", day: at(10, 16, 0, 0), wantErr: true},
+		{name: "bad hour", in: "25:00-26:00", day: at(10, 16, 0, 0), wantErr: true},
+		{name: "no separator", in: "14:00", day: at(10, 16, 0, 0), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := p.ClockRange(tt.in, tt.day)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ClockRange(%q) = %v..%v, want error", tt.in, start, end)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ClockRange(%q): %v", tt.in, err)
+			}
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("ClockRange(%q) = %v..%v, want %v..%v", tt.in, start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestNewFallsBackToEnglish(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	p := New(now, "xx")
+	if _, err := p.Day("yesterday"); err != nil {
+		t.Fatalf("Day(yesterday) with unknown locale: %v", err)
+	}
+	from, _, err := p.Range("this week")
+	if err != nil {
+		t.Fatalf("Range(this week) with unknown locale: %v", err)
+	}
+	if from.Weekday() != time.Monday {
+		t.Errorf("week starts on %v, want Monday", from.Weekday())
+	}
+}